@@ -1,4 +1,5 @@
-// Package clip is a library for clipping geometry to a bounding box.
+// Package clip is a library for clipping geometry to a bounding box
+// or polygon.
 package clip
 
 import (
@@ -12,6 +13,8 @@ import (
 // correct functions for the type.
 // This operation will modify the input of '1d or 2d geometry' by using as a
 // scratch space so clone if necessary.
+// Coordinates are planar, so lon/lat geometries that cross the antimeridian
+// must be split first, e.g. with geo.SplitAntimeridian.
 func Geometry(b orb.Bound, g orb.Geometry) orb.Geometry {
 	if g == nil {
 		return nil
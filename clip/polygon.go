@@ -0,0 +1,422 @@
+package clip
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/segment"
+	"github.com/paulmach/orb/planar"
+)
+
+// ByPolygon will clip the geometry to the polygon, holes included, using
+// the correct functions for the type. Lines along the polygon boundary are kept
+// unless the OpenBound option is used. Unlike Geometry, the input is not modified.
+// 2d results are rebuilt so outer rings are counter-clockwise and holes clockwise.
+func ByPolygon(p orb.Polygon, g orb.Geometry, opts ...Option) orb.Geometry {
+	return ByMultiPolygon(orb.MultiPolygon{p}, g, opts...)
+}
+
+// ByMultiPolygon will clip the geometry to the multi polygon using
+// the correct functions for the type. See ByPolygon for details.
+func ByMultiPolygon(mp orb.MultiPolygon, g orb.Geometry, opts ...Option) orb.Geometry {
+	if g == nil {
+		return nil
+	}
+
+	mp = nonEmpty(mp)
+	if len(mp) == 0 || !mp.Bound().Intersects(g.Bound()) {
+		return nil
+	}
+
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		index := newEdgeIndex(polygonEdges(mp), len(mp))
+		if containsPoint(index, g) {
+			return g
+		}
+
+		return nil
+	case orb.MultiPoint:
+		index := newEdgeIndex(polygonEdges(mp), len(mp))
+
+		var result orb.MultiPoint
+		for _, p := range g {
+			if containsPoint(index, p) {
+				result = append(result, p)
+			}
+		}
+
+		if len(result) == 1 {
+			return result[0]
+		}
+
+		if result == nil {
+			return nil
+		}
+
+		return result
+	case orb.LineString:
+		return lineStringResult(lineByPolygon(mp, g, o.openBound))
+	case orb.MultiLineString:
+		var result orb.MultiLineString
+		for _, ls := range g {
+			result = append(result, lineByPolygon(mp, ls, o.openBound)...)
+		}
+
+		return lineStringResult(result)
+	case orb.Ring:
+		result := planar.Intersection(mp, g)
+		if len(result) == 1 && len(result[0]) == 1 {
+			return result[0][0]
+		}
+
+		return polygonResult(result)
+	case orb.Polygon, orb.MultiPolygon, orb.Bound:
+		return polygonResult(planar.Intersection(mp, g))
+	case orb.Collection:
+		var result orb.Collection
+		for _, c := range g {
+			clipped := ByMultiPolygon(mp, c, opts...)
+			if clipped != nil {
+				result = append(result, clipped)
+			}
+		}
+
+		if len(result) == 1 {
+			return result[0]
+		}
+
+		if result == nil {
+			return nil
+		}
+
+		return result
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func nonEmpty(mp orb.MultiPolygon) orb.MultiPolygon {
+	var result orb.MultiPolygon
+	for _, p := range mp {
+		if len(p) > 0 && len(p[0]) > 0 {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+func lineStringResult(mls orb.MultiLineString) orb.Geometry {
+	if len(mls) == 1 {
+		return mls[0]
+	}
+
+	if len(mls) == 0 {
+		return nil
+	}
+
+	return mls
+}
+
+func polygonResult(mp orb.MultiPolygon) orb.Geometry {
+	if len(mp) == 1 {
+		return mp[0]
+	}
+
+	if len(mp) == 0 {
+		return nil
+	}
+
+	return mp
+}
+
+const (
+	locOutside = iota
+	locInside
+	locBoundary
+)
+
+// lineByPolygon splits the line where it meets the polygon boundaries
+// and keeps the pieces that are inside.
+func lineByPolygon(mp orb.MultiPolygon, ls orb.LineString, open bool) orb.MultiLineString {
+	var (
+		result  orb.MultiLineString
+		current orb.LineString
+	)
+
+	flush := func() {
+		if len(current) > 1 {
+			result = append(result, current)
+		}
+		current = nil
+	}
+
+	edges := polygonEdges(mp)
+	index := newEdgeIndex(edges, len(mp))
+	near := nearEdges(ls, edges)
+
+	for i := 0; i < len(ls)-1; i++ {
+		points := splitSegment(near[i], ls[i], ls[i+1])
+		for j := 0; j < len(points)-1; j++ {
+			a, b := points[j], points[j+1]
+			if a == b {
+				continue
+			}
+
+			loc := locateSegment(index, near[i], a, b)
+			if loc == locOutside || (loc == locBoundary && open) {
+				flush()
+				continue
+			}
+
+			if len(current) == 0 {
+				current = append(current, a)
+			} else if open && onEdges(near[i], a) {
+				flush()
+				current = append(current, a)
+			}
+
+			current = append(current, b)
+		}
+	}
+	flush()
+
+	return result
+}
+
+// splitSegment returns the segment's start and end points
+// plus all the places it meets the edges, in order.
+func splitSegment(edges []polygonEdge, a, b orb.Point) []orb.Point {
+	result := []orb.Point{a}
+	if a == b {
+		return append(result, b)
+	}
+
+	for _, e := range edges {
+		p1, p2, n := segment.Intersection(a, b, e.a, e.b)
+		if n > 0 && p1 != a && p1 != b {
+			result = append(result, p1)
+		}
+		if n > 1 && p2 != a && p2 != b {
+			result = append(result, p2)
+		}
+	}
+
+	dx := b[0] - a[0]
+	dy := b[1] - a[1]
+	sort.Slice(result, func(i, j int) bool {
+		ti := (result[i][0]-a[0])*dx + (result[i][1]-a[1])*dy
+		tj := (result[j][0]-a[0])*dx + (result[j][1]-a[1])*dy
+		return ti < tj
+	})
+
+	// remove duplicates
+	points := result[:1]
+	for _, p := range result[1:] {
+		if p != points[len(points)-1] {
+			points = append(points, p)
+		}
+	}
+
+	return append(points, b)
+}
+
+// locateSegment returns where the segment is relative to the polygons.
+// The segment must not cross any boundary and the edges must include
+// all the edges near the segment.
+func locateSegment(index *edgeIndex, edges []polygonEdge, a, b orb.Point) int {
+	for _, e := range edges {
+		if segment.OnSegment(e.a, e.b, a) && segment.OnSegment(e.a, e.b, b) {
+			return locBoundary
+		}
+	}
+
+	mid := orb.Point{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+	if index.contains(mid) {
+		return locInside
+	}
+
+	return locOutside
+}
+
+// containsPoint checks if the point is within the polygons.
+// Points on the boundary, including the boundary of holes, are considered in.
+func containsPoint(index *edgeIndex, p orb.Point) bool {
+	return index.onBoundary(p) || index.contains(p)
+}
+
+func onEdges(edges []polygonEdge, p orb.Point) bool {
+	for _, e := range edges {
+		if segment.OnSegment(e.a, e.b, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// polygonEdge is an edge of one of the polygons, rings that
+// are not closed get an edge from the last point to the first.
+type polygonEdge struct {
+	a, b    orb.Point
+	polygon int
+}
+
+func polygonEdges(mp orb.MultiPolygon) []polygonEdge {
+	var edges []polygonEdge
+	for i, p := range mp {
+		for _, r := range p {
+			if len(r) == 0 {
+				continue
+			}
+
+			if r[0] != r[len(r)-1] {
+				edges = append(edges, polygonEdge{a: r[len(r)-1], b: r[0], polygon: i})
+			}
+
+			for j := 0; j < len(r)-1; j++ {
+				edges = append(edges, polygonEdge{a: r[j], b: r[j+1], polygon: i})
+			}
+		}
+	}
+
+	return edges
+}
+
+// nearEdges returns, for every segment of the line, the edges
+// whose bound intersects the bound of the segment.
+func nearEdges(ls orb.LineString, edges []polygonEdge) [][]polygonEdge {
+	n := len(ls) - 1
+	if n < 1 {
+		return nil
+	}
+
+	bounds := make([]orb.Bound, n, n+len(edges))
+	for i := range bounds {
+		bounds[i] = orb.MultiPoint{ls[i], ls[i+1]}.Bound()
+	}
+
+	for _, e := range edges {
+		bounds = append(bounds, orb.MultiPoint{e.a, e.b}.Bound())
+	}
+
+	near := make([][]polygonEdge, n)
+	segment.Sweep(bounds, func(i, j int) {
+		// the line segments come first, so i < j
+		if i < n && j >= n {
+			near[i] = append(near[i], edges[j-n])
+		}
+	})
+
+	return near
+}
+
+// edgeIndex speeds up the point queries by bucketing
+// the edges of the polygons by their y range.
+type edgeIndex struct {
+	minY, step float64
+	buckets    [][]polygonEdge
+	polygons   int
+}
+
+func newEdgeIndex(edges []polygonEdge, polygons int) *edgeIndex {
+	if len(edges) == 0 {
+		return &edgeIndex{}
+	}
+
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, e := range edges {
+		minY = math.Min(minY, math.Min(e.a[1], e.b[1]))
+		maxY = math.Max(maxY, math.Max(e.a[1], e.b[1]))
+	}
+
+	n := int(math.Sqrt(float64(len(edges)))) + 1
+	index := &edgeIndex{
+		minY:     minY,
+		step:     (maxY - minY) / float64(n),
+		buckets:  make([][]polygonEdge, n),
+		polygons: polygons,
+	}
+
+	for _, e := range edges {
+		lo := index.bucket(math.Min(e.a[1], e.b[1]))
+		hi := index.bucket(math.Max(e.a[1], e.b[1]))
+		for j := lo; j <= hi; j++ {
+			index.buckets[j] = append(index.buckets[j], e)
+		}
+	}
+
+	return index
+}
+
+func (ei *edgeIndex) bucket(y float64) int {
+	if ei.step == 0 {
+		return 0
+	}
+
+	i := int((y - ei.minY) / ei.step)
+	if i < 0 {
+		return 0
+	}
+
+	if i >= len(ei.buckets) {
+		return len(ei.buckets) - 1
+	}
+
+	return i
+}
+
+// near returns the edges that could touch the point.
+func (ei *edgeIndex) near(p orb.Point) []polygonEdge {
+	if len(ei.buckets) == 0 {
+		return nil
+	}
+
+	if p[1] < ei.minY || p[1] > ei.minY+ei.step*float64(len(ei.buckets)) {
+		return nil
+	}
+
+	return ei.buckets[ei.bucket(p[1])]
+}
+
+func (ei *edgeIndex) onBoundary(p orb.Point) bool {
+	return onEdges(ei.near(p), p)
+}
+
+// contains returns true if the point is inside one of the polygons
+// using the even-odd rule for each polygon separately, so overlapping
+// polygons work as expected. Points on the boundary may be in or out.
+func (ei *edgeIndex) contains(p orb.Point) bool {
+	edges := ei.near(p)
+	if len(edges) == 0 {
+		return false
+	}
+
+	inside := make([]bool, ei.polygons)
+	for _, e := range edges {
+		if (e.a[1] > p[1]) == (e.b[1] > p[1]) {
+			continue
+		}
+
+		x := e.a[0] + (p[1]-e.a[1])*(e.b[0]-e.a[0])/(e.b[1]-e.a[1])
+		if x > p[0] {
+			inside[e.polygon] = !inside[e.polygon]
+		}
+	}
+
+	for _, in := range inside {
+		if in {
+			return true
+		}
+	}
+
+	return false
+}
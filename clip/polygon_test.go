@@ -0,0 +1,255 @@
+package clip
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+// +-----------+
+// |           |
+// |   +---+   |
+// |   |   |   |
+// |   +---+   |
+// |           |
+// +-----------+
+var donut = orb.Polygon{
+	{{0, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 0}},
+	{{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}},
+}
+
+func TestByPolygon(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		ByPolygon(donut, g)
+	}
+}
+
+func TestByPolygon_lineString(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  orb.Geometry
+		open   bool
+		output orb.Geometry
+	}{
+		{
+			name:  "through the hole",
+			input: orb.LineString{{-1, 3}, {7, 3}},
+			output: orb.MultiLineString{
+				{{0, 3}, {2, 3}},
+				{{4, 3}, {6, 3}},
+			},
+		},
+		{
+			name:   "all inside",
+			input:  orb.LineString{{1, 1}, {1, 5}, {5, 5}},
+			output: orb.LineString{{1, 1}, {1, 5}, {5, 5}},
+		},
+		{
+			name:   "outside",
+			input:  orb.LineString{{7, 7}, {8, 8}},
+			output: nil,
+		},
+		{
+			name:   "along boundary",
+			input:  orb.LineString{{1, 1}, {2, 1}, {2, 3}, {1, 3}},
+			output: orb.LineString{{1, 1}, {2, 1}, {2, 2}, {2, 3}, {1, 3}},
+		},
+		{
+			name:  "along boundary with open bound",
+			input: orb.LineString{{1, 1}, {2, 1}, {2, 3}, {1, 3}},
+			open:  true,
+			output: orb.MultiLineString{
+				{{1, 1}, {2, 1}, {2, 2}},
+				{{2, 3}, {1, 3}},
+			},
+		},
+		{
+			name:   "touching boundary",
+			input:  orb.LineString{{1, 1}, {2, 2}, {1, 3}},
+			output: orb.LineString{{1, 1}, {2, 2}, {1, 3}},
+		},
+		{
+			name:  "touching boundary with open bound",
+			input: orb.LineString{{1, 1}, {2, 2}, {1, 3}},
+			open:  true,
+			output: orb.MultiLineString{
+				{{1, 1}, {2, 2}},
+				{{2, 2}, {1, 3}},
+			},
+		},
+		{
+			name: "multi line string",
+			input: orb.MultiLineString{
+				{{-1, 1}, {1, 1}},
+				{{5, 5}, {7, 7}},
+			},
+			output: orb.MultiLineString{
+				{{0, 1}, {1, 1}},
+				{{5, 5}, {6, 6}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := ByPolygon(donut, tc.input, OpenBound(tc.open))
+			if !reflect.DeepEqual(result, tc.output) {
+				t.Errorf("incorrect clip")
+				t.Logf("%v", result)
+				t.Logf("%v", tc.output)
+			}
+		})
+	}
+}
+
+func TestByPolygon_points(t *testing.T) {
+	mp := orb.MultiPoint{{1, 1}, {3, 3}, {2, 2}, {7, 7}}
+
+	result := ByPolygon(donut, mp)
+	expected := orb.MultiPoint{{1, 1}, {2, 2}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("incorrect clip: %v", result)
+	}
+
+	if result := ByPolygon(donut, orb.Point{3, 3}); result != nil {
+		t.Errorf("point in hole should be nil: %v", result)
+	}
+}
+
+func TestByPolygon_polygon(t *testing.T) {
+	bound := orb.Bound{Min: orb.Point{1, 1}, Max: orb.Point{3, 3}}
+
+	result := ByPolygon(donut, bound)
+	p, ok := result.(orb.Polygon)
+	if !ok {
+		t.Fatalf("should be a polygon: %T", result)
+	}
+
+	if a := planar.Area(p); a != 3 {
+		t.Errorf("incorrect area: %v", a)
+	}
+
+	// crossing the hole splits the polygon
+	bound = orb.Bound{Min: orb.Point{-1, 2.5}, Max: orb.Point{7, 3.5}}
+	result = ByPolygon(donut, bound.ToRing())
+	mp, ok := result.(orb.MultiPolygon)
+	if !ok {
+		t.Fatalf("should be a multi polygon: %T", result)
+	}
+
+	if a := planar.Area(mp); a != 4 {
+		t.Errorf("incorrect area: %v", a)
+	}
+
+	// a ring that is fully inside is still a ring
+	r := orb.Ring{{0.5, 0.5}, {1.5, 0.5}, {1.5, 1.5}, {0.5, 1.5}, {0.5, 0.5}}
+	if result := ByPolygon(donut, r); !reflect.DeepEqual(result, r) {
+		t.Errorf("incorrect ring: %v", result)
+	}
+}
+
+func TestByMultiPolygon(t *testing.T) {
+	mp := orb.MultiPolygon{
+		{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		{{{2, 0}, {3, 0}, {3, 1}, {2, 1}, {2, 0}}},
+	}
+
+	result := ByMultiPolygon(mp, orb.LineString{{-1, 0.5}, {4, 0.5}})
+	expected := orb.MultiLineString{
+		{{0, 0.5}, {1, 0.5}},
+		{{2, 0.5}, {3, 0.5}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("incorrect clip: %v", result)
+	}
+
+	c := orb.Collection{orb.Point{0.5, 0.5}, orb.Point{1.5, 0.5}}
+	if result := ByMultiPolygon(mp, c); !reflect.DeepEqual(result, orb.Point{0.5, 0.5}) {
+		t.Errorf("incorrect collection clip: %v", result)
+	}
+}
+
+func TestByMultiPolygon_overlappingMembers(t *testing.T) {
+	mp := orb.MultiPolygon{
+		{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+		{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}},
+	}
+
+	result := ByMultiPolygon(mp, orb.Bound{Min: orb.Point{-1, -1}, Max: orb.Point{20, 20}}.ToPolygon())
+	p, ok := result.(orb.Polygon)
+	if !ok {
+		t.Fatalf("should be a single polygon: %v", result)
+	}
+
+	if a := planar.Area(p); a != 175 {
+		t.Errorf("incorrect area: %v", a)
+	}
+}
+
+func TestByPolygon_manyEdges(t *testing.T) {
+	// circles with lots of edges and a zigzag line crossing them many times
+	circle := func(r float64, n int) orb.Ring {
+		ring := make(orb.Ring, 0, n+1)
+		for i := 0; i < n; i++ {
+			a := 2 * math.Pi * float64(i) / float64(n)
+			ring = append(ring, orb.Point{r * math.Cos(a), r * math.Sin(a)})
+		}
+		return append(ring, ring[0])
+	}
+
+	hole := circle(5, 1000)
+	hole.Reverse()
+	p := orb.Polygon{circle(10, 1000), hole}
+
+	ls := make(orb.LineString, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		ls = append(ls, orb.Point{-12 + 24*float64(i)/999, float64(i%2)*24 - 12})
+	}
+
+	result, ok := ByPolygon(p, ls).(orb.MultiLineString)
+	if !ok {
+		t.Fatalf("should be a multi line string: %T", result)
+	}
+
+	// every piece must be inside, and the pieces must cover the
+	// parts of the line that are inside
+	inside := 0.0
+	for _, l := range result {
+		for i := 0; i < len(l)-1; i++ {
+			mid := orb.Point{(l[i][0] + l[i+1][0]) / 2, (l[i][1] + l[i+1][1]) / 2}
+			if !planar.PolygonContains(p, mid) {
+				t.Fatalf("piece should be inside: %v", l[i:i+2])
+			}
+		}
+		inside += planar.Length(l)
+	}
+
+	// the length of the segment inside the true circle
+	chord := func(a, b orb.Point, r float64) float64 {
+		dx, dy := b[0]-a[0], b[1]-a[1]
+		qa := dx*dx + dy*dy
+		qb := 2 * (a[0]*dx + a[1]*dy)
+		qc := a[0]*a[0] + a[1]*a[1] - r*r
+
+		d := qb*qb - 4*qa*qc
+		if d <= 0 {
+			return 0
+		}
+
+		t1 := math.Max((-qb-math.Sqrt(d))/(2*qa), 0)
+		t2 := math.Min((-qb+math.Sqrt(d))/(2*qa), 1)
+		return math.Max(t2-t1, 0) * math.Sqrt(qa)
+	}
+
+	expected := 0.0
+	for i := 0; i < len(ls)-1; i++ {
+		expected += chord(ls[i], ls[i+1], 10) - chord(ls[i], ls[i+1], 5)
+	}
+
+	if math.Abs(inside-expected) > 1e-3*expected {
+		t.Errorf("incorrect length inside: %v != %v", inside, expected)
+	}
+}
@@ -32,6 +32,12 @@ type Pointer interface {
 	Point() Point
 }
 
+// Bounder is something that can be represented by a bound,
+// such as all the geometry types.
+type Bounder interface {
+	Bound() Bound
+}
+
 // A Simplifier is something that can simplify geometry.
 type Simplifier interface {
 	Simplify(g Geometry) Geometry
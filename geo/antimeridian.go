@@ -0,0 +1,413 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+// SplitAntimeridian cuts the geometry where it crosses the antimeridian,
+// +-180 degrees longitude. An edge crosses the antimeridian if that is
+// the shorter way around the earth, ie. the longitudes are more than 180
+// degrees apart. Lines are split into a MultiLineString and rings and
+// polygons into a MultiPolygon with the parts touching +180 and -180.
+// Rings that encircle a pole are closed using the pole in the same hemisphere.
+// Geometries that do not cross are returned unchanged and points
+// are never changed.
+func SplitAntimeridian(g orb.Geometry) orb.Geometry {
+	if g == nil {
+		return nil
+	}
+
+	switch g := g.(type) {
+	case orb.Point, orb.MultiPoint:
+		return g
+	case orb.LineString:
+		mls := splitLineString(nil, g)
+		if len(mls) == 1 && len(mls[0]) == len(g) {
+			return g
+		}
+		return mls
+	case orb.MultiLineString:
+		var mls orb.MultiLineString
+		for _, ls := range g {
+			mls = splitLineString(mls, ls)
+		}
+		return mls
+	case orb.Ring:
+		if mp := splitPolygon(orb.Polygon{g}); mp != nil {
+			return mp
+		}
+		return g
+	case orb.Polygon:
+		if mp := splitPolygon(g); mp != nil {
+			return mp
+		}
+		return g
+	case orb.MultiPolygon:
+		var mp orb.MultiPolygon
+		for _, p := range g {
+			if split := splitPolygon(p); split != nil {
+				mp = append(mp, split...)
+			} else {
+				mp = append(mp, p)
+			}
+		}
+		return mp
+	case orb.Collection:
+		c := make(orb.Collection, 0, len(g))
+		for _, geom := range g {
+			c = append(c, SplitAntimeridian(geom))
+		}
+		return c
+	case orb.Bound:
+		if g.Min[0] <= g.Max[0] {
+			return g
+		}
+
+		// a bound from Bound that wraps around the antimeridian
+		west := orb.Bound{Min: g.Min, Max: orb.Point{180, g.Max[1]}}
+		east := orb.Bound{Min: orb.Point{-180, g.Min[1]}, Max: g.Max}
+		return orb.MultiPolygon{{west.ToRing()}, {east.ToRing()}}
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+// splitLineString appends the parts of the line string between crossings
+// of the antimeridian.
+func splitLineString(mls orb.MultiLineString, ls orb.LineString) orb.MultiLineString {
+	if len(ls) == 0 {
+		return mls
+	}
+
+	current := orb.LineString{{wrapLongitude(ls[0][0]), ls[0][1]}}
+	for i := 1; i < len(ls); i++ {
+		p1 := current[len(current)-1]
+		p2 := orb.Point{wrapLongitude(ls[i][0]), ls[i][1]}
+
+		lon1, lon2 := p1[0], p2[0]
+		if math.Abs(lon2-lon1) > 180 {
+			// the crossing longitude
+			c := 180.0
+			if lon1 < 0 {
+				c = -180
+			}
+
+			d := math.Remainder(lon2-lon1, 360)
+			t := (c - lon1) / d
+			cross := orb.Point{c, p1[1] + t*(p2[1]-p1[1])}
+
+			if current[len(current)-1] != cross {
+				current = append(current, cross)
+			}
+			if len(current) > 1 {
+				mls = append(mls, current)
+			}
+
+			current = orb.LineString{{-c, cross[1]}}
+			if p2 == current[0] {
+				continue
+			}
+		}
+
+		current = append(current, p2)
+	}
+
+	if len(current) > 1 || len(mls) == 0 {
+		mls = append(mls, current)
+	}
+
+	return mls
+}
+
+// splitPolygon returns the parts of the polygon on either side of
+// the antimeridian. It returns nil if the polygon does not cross it.
+// The rings are unwrapped so the longitude is continuous, then intersected
+// with each 360 degree window and shifted back into range.
+func splitPolygon(p orb.Polygon) orb.MultiPolygon {
+	if len(p) == 0 || len(p[0]) == 0 {
+		return nil
+	}
+
+	shell, pole := unwrapRing(p[0])
+	bound := shell.Bound()
+	if !pole && bound.Min[0] >= -180 && bound.Max[0] <= 180 {
+		return nil
+	}
+
+	unwrapped := orb.Polygon{shell}
+	center := (bound.Min[0] + bound.Max[0]) / 2
+	for _, r := range p[1:] {
+		hole, _ := unwrapRing(r)
+		if len(hole) == 0 {
+			continue
+		}
+
+		// move the hole so it's with the shell
+		shift := 360 * math.Round((center-hole[0][0])/360)
+		for i := range hole {
+			hole[i][0] += shift
+		}
+		unwrapped = append(unwrapped, hole)
+	}
+
+	mp := orb.MultiPolygon{}
+	first := math.Floor((bound.Min[0] + 180) / 360)
+	last := math.Ceil((bound.Max[0] - 180) / 360)
+	for k := first; k <= last; k++ {
+		window := orb.Bound{
+			Min: orb.Point{360*k - 180, -90},
+			Max: orb.Point{360*k + 180, 90},
+		}
+
+		for _, part := range planar.Intersection(unwrapped, window) {
+			for _, r := range part {
+				for i := range r {
+					r[i][0] -= 360 * k
+				}
+			}
+			mp = append(mp, part)
+		}
+	}
+
+	return mp
+}
+
+// unwrapRing returns a closed copy of the ring where consecutive longitudes
+// are never more than 180 degrees apart. If the ring encircles a pole it is
+// closed by going to the pole and true is returned.
+func unwrapRing(r orb.Ring) (orb.Ring, bool) {
+	if len(r) > 1 && r[0] == r[len(r)-1] {
+		r = r[:len(r)-1]
+	}
+
+	if len(r) == 0 {
+		return nil, false
+	}
+
+	result := make(orb.Ring, 0, len(r)+4)
+	result = append(result, orb.Point{wrapLongitude(r[0][0]), r[0][1]})
+
+	lat := r[0][1]
+	for i := 1; i < len(r); i++ {
+		prev := result[len(result)-1]
+		d := math.Remainder(r[i][0]-r[i-1][0], 360)
+		result = append(result, orb.Point{prev[0] + d, r[i][1]})
+		lat += r[i][1]
+	}
+
+	start, end := result[0], result[len(result)-1]
+	net := end[0] + math.Remainder(r[0][0]-r[len(r)-1][0], 360) - start[0]
+	if math.Abs(net) < 180 {
+		return append(result, start), false
+	}
+
+	// encircles a pole, go around the pole in the same hemisphere
+	pole := 90.0
+	if lat < 0 {
+		pole = -90
+	}
+
+	// Start the ring where it crosses the antimeridian so it's closed
+	// along the antimeridian and not cut at the first point.
+	path := append(result, orb.Point{start[0] + net, start[1]})
+
+	first, cross := 0, start
+	for i := 0; i < len(path)-1; i++ {
+		a, b := path[i], path[i+1]
+		lo, hi := math.Min(a[0], b[0]), math.Max(a[0], b[0])
+
+		c := 360*math.Ceil((lo-180)/360) + 180
+		if c > hi {
+			continue
+		}
+
+		first, cross = i, a
+		if a[0] != b[0] {
+			t := (c - a[0]) / (b[0] - a[0])
+			cross = orb.Point{c, a[1] + t*(b[1]-a[1])}
+		}
+		break
+	}
+
+	rotated := make(orb.Ring, 0, len(path)+5)
+	add := func(p orb.Point) {
+		if len(rotated) == 0 || rotated[len(rotated)-1] != p {
+			rotated = append(rotated, p)
+		}
+	}
+
+	add(cross)
+	for _, p := range path[first+1:] {
+		add(p)
+	}
+	for _, p := range path[1 : first+1] {
+		add(orb.Point{p[0] + net, p[1]})
+	}
+	add(orb.Point{cross[0] + net, cross[1]})
+	add(orb.Point{cross[0] + net, pole})
+	add(orb.Point{cross[0], pole})
+	add(cross)
+
+	return rotated, true
+}
+
+// Bound returns the bound of the geometry taking into account that
+// longitudes wrap around at the antimeridian. If the geometry crosses
+// the antimeridian the returned bound will have Min.Lon > Max.Lon, ie.
+// it goes east from the min to 180 and then on from -180 to the max.
+// Edges are assumed to go the shorter way around the earth. Polygons that
+// encircle a pole cover all longitudes and extend to the pole.
+// Note that orb.Bound methods, such as IsEmpty and Contains, do not
+// support bounds that wrap.
+func Bound(g orb.Geometry) orb.Bound {
+	if g == nil {
+		return orb.Bound{}
+	}
+
+	if b, ok := g.(orb.Bound); ok {
+		return b
+	}
+
+	lb := &lonBound{minLat: math.Inf(1), maxLat: math.Inf(-1)}
+	lb.geometry(g)
+	if len(lb.intervals) == 0 {
+		return g.Bound()
+	}
+
+	// merge the intervals and find the largest gap in longitude
+	sort.Slice(lb.intervals, func(i, j int) bool {
+		return lb.intervals[i][0] < lb.intervals[j][0]
+	})
+
+	merged := lb.intervals[:1]
+	for _, in := range lb.intervals[1:] {
+		last := &merged[len(merged)-1]
+		if in[0] <= last[1] {
+			last[1] = math.Max(last[1], in[1])
+		} else {
+			merged = append(merged, in)
+		}
+	}
+
+	// the gap over the antimeridian
+	minLon, maxLon := merged[0][0], merged[len(merged)-1][1]
+	gap := minLon + 360 - maxLon
+	for i := 1; i < len(merged); i++ {
+		if d := merged[i][0] - merged[i-1][1]; d > gap {
+			gap = d
+			minLon, maxLon = merged[i][0], merged[i-1][1]
+		}
+	}
+
+	return orb.Bound{
+		Min: orb.Point{minLon, lb.minLat},
+		Max: orb.Point{maxLon, lb.maxLat},
+	}
+}
+
+// lonBound collects the longitude intervals covered by a geometry,
+// each within [-180, 180], and the latitude range.
+type lonBound struct {
+	intervals      [][2]float64
+	minLat, maxLat float64
+}
+
+func (lb *lonBound) geometry(g orb.Geometry) {
+	switch g := g.(type) {
+	case orb.Point:
+		lb.line([]orb.Point{g}, false)
+	case orb.MultiPoint:
+		for _, p := range g {
+			lb.line([]orb.Point{p}, false)
+		}
+	case orb.LineString:
+		lb.line(g, false)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			lb.line(ls, false)
+		}
+	case orb.Ring:
+		lb.polygon(orb.Polygon{g})
+	case orb.Polygon:
+		lb.polygon(g)
+	case orb.MultiPolygon:
+		for _, p := range g {
+			lb.polygon(p)
+		}
+	case orb.Collection:
+		for _, c := range g {
+			lb.geometry(c)
+		}
+	case orb.Bound:
+		lb.line(g.ToRing(), true)
+	default:
+		panic(fmt.Sprintf("geometry type not supported: %T", g))
+	}
+}
+
+func (lb *lonBound) polygon(p orb.Polygon) {
+	if len(p) == 0 {
+		return
+	}
+
+	lb.line(p[0], true)
+
+	if r, pole := unwrapRing(p[0]); pole {
+		// the second to last point is the pole
+		lb.add(-180, 180)
+		lb.minLat = math.Min(lb.minLat, r[len(r)-2][1])
+		lb.maxLat = math.Max(lb.maxLat, r[len(r)-2][1])
+	}
+}
+
+func (lb *lonBound) line(ls []orb.Point, closed bool) {
+	for i, p := range ls {
+		lb.minLat = math.Min(lb.minLat, p[1])
+		lb.maxLat = math.Max(lb.maxLat, p[1])
+
+		lon := wrapLongitude(p[0])
+		lb.add(lon, lon)
+
+		var next orb.Point
+		if i < len(ls)-1 {
+			next = ls[i+1]
+		} else if closed && len(ls) > 1 {
+			next = ls[0]
+		} else {
+			continue
+		}
+
+		d := math.Remainder(next[0]-p[0], 360)
+		if d < 0 {
+			lb.add(lon+d, lon)
+		} else {
+			lb.add(lon, lon+d)
+		}
+	}
+}
+
+// add adds the interval splitting it if it goes past the antimeridian.
+func (lb *lonBound) add(lo, hi float64) {
+	switch {
+	case lo < -180:
+		lb.intervals = append(lb.intervals, [2]float64{lo + 360, 180}, [2]float64{-180, hi})
+	case hi > 180:
+		lb.intervals = append(lb.intervals, [2]float64{lo, 180}, [2]float64{-180, hi - 360})
+	default:
+		lb.intervals = append(lb.intervals, [2]float64{lo, hi})
+	}
+}
+
+// wrapLongitude moves the longitude into the range [-180, 180].
+func wrapLongitude(lon float64) float64 {
+	if lon < -180 || lon > 180 {
+		return math.Remainder(lon, 360)
+	}
+
+	return lon
+}
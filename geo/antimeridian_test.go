@@ -0,0 +1,238 @@
+package geo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestSplitAntimeridian(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		SplitAntimeridian(g)
+	}
+
+	cases := []struct {
+		name   string
+		input  orb.Geometry
+		result orb.Geometry
+	}{
+		{
+			name:   "line does not cross",
+			input:  orb.LineString{{170, 0}, {-10, 0}},
+			result: orb.LineString{{170, 0}, {-10, 0}},
+		},
+		{
+			name:   "line crosses",
+			input:  orb.LineString{{170, 0}, {-170, 10}},
+			result: orb.MultiLineString{{{170, 0}, {180, 5}}, {{-180, 5}, {-170, 10}}},
+		},
+		{
+			name:  "line crosses back",
+			input: orb.LineString{{-170, 0}, {170, 10}, {-170, 20}},
+			result: orb.MultiLineString{
+				{{-170, 0}, {-180, 5}},
+				{{180, 5}, {170, 10}, {180, 15}},
+				{{-180, 15}, {-170, 20}},
+			},
+		},
+		{
+			name:   "line ends on the antimeridian",
+			input:  orb.LineString{{170, 0}, {180, 0}, {-170, 0}},
+			result: orb.MultiLineString{{{170, 0}, {180, 0}}, {{-180, 0}, {-170, 0}}},
+		},
+		{
+			name:   "line with longitude out of range",
+			input:  orb.LineString{{170, 0}, {190, 10}},
+			result: orb.MultiLineString{{{170, 0}, {180, 5}}, {{-180, 5}, {-170, 10}}},
+		},
+		{
+			name:   "polygon does not cross",
+			input:  orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+			result: orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		},
+		{
+			name:  "bound crossing",
+			input: orb.Bound{Min: orb.Point{170, 0}, Max: orb.Point{-170, 10}},
+			result: orb.MultiPolygon{
+				{orb.Bound{Min: orb.Point{170, 0}, Max: orb.Point{180, 10}}.ToRing()},
+				{orb.Bound{Min: orb.Point{-180, 0}, Max: orb.Point{-170, 10}}.ToRing()},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := SplitAntimeridian(tc.input)
+			if !reflect.DeepEqual(result, tc.result) {
+				t.Errorf("incorrect result: %v", result)
+				t.Log(tc.result)
+			}
+		})
+	}
+}
+
+func TestSplitAntimeridian_polygon(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  orb.Geometry
+		bounds []orb.Bound
+		area   float64
+	}{
+		{
+			name:  "box",
+			input: orb.Ring{{170, 0}, {-170, 0}, {-170, 10}, {170, 10}, {170, 0}},
+			bounds: []orb.Bound{
+				{Min: orb.Point{170, 0}, Max: orb.Point{180, 10}},
+				{Min: orb.Point{-180, 0}, Max: orb.Point{-170, 10}},
+			},
+			area: 200,
+		},
+		{
+			name: "box with hole",
+			input: orb.Polygon{
+				{{170, 0}, {-170, 0}, {-170, 10}, {170, 10}, {170, 0}},
+				{{175, 2}, {175, 8}, {-175, 8}, {-175, 2}, {175, 2}},
+			},
+			bounds: []orb.Bound{
+				{Min: orb.Point{170, 0}, Max: orb.Point{180, 10}},
+				{Min: orb.Point{-180, 0}, Max: orb.Point{-170, 10}},
+			},
+			area: 140,
+		},
+		{
+			name: "multi polygon",
+			input: orb.MultiPolygon{
+				{{{170, 0}, {-170, 0}, {-170, 10}, {170, 10}, {170, 0}}},
+				{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+			},
+			bounds: []orb.Bound{
+				{Min: orb.Point{170, 0}, Max: orb.Point{180, 10}},
+				{Min: orb.Point{-180, 0}, Max: orb.Point{-170, 10}},
+				{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}},
+			},
+			area: 201,
+		},
+		{
+			name:  "around the north pole",
+			input: orb.Ring{{-90, 80}, {0, 80}, {90, 80}, {180, 80}},
+			bounds: []orb.Bound{
+				{Min: orb.Point{-180, 80}, Max: orb.Point{180, 90}},
+			},
+			area: 3600,
+		},
+		{
+			name:  "around the north pole from lon 0",
+			input: orb.Ring{{0, 80}, {90, 80}, {180, 80}, {-90, 70}},
+			bounds: []orb.Bound{
+				{Min: orb.Point{-180, 70}, Max: orb.Point{180, 90}},
+			},
+			area: 3600 + 450 + 450,
+		},
+		{
+			name:  "around the south pole",
+			input: orb.Ring{{0, -80}, {-90, -80}, {-180, -80}, {90, -80}},
+			bounds: []orb.Bound{
+				{Min: orb.Point{-180, -90}, Max: orb.Point{180, -80}},
+			},
+			area: 3600,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, ok := SplitAntimeridian(tc.input).(orb.MultiPolygon)
+			if !ok {
+				t.Fatalf("should return multi polygon: %T", result)
+			}
+
+			if len(result) != len(tc.bounds) {
+				t.Fatalf("incorrect number of polygons: %v", len(result))
+			}
+
+			for i, p := range result {
+				if b := p.Bound(); b != tc.bounds[i] {
+					t.Errorf("incorrect bound %d: %v", i, b)
+				}
+			}
+
+			if a := planar.Area(result); math.Abs(a-tc.area) > 1e-9 {
+				t.Errorf("incorrect area: %v", a)
+			}
+		})
+	}
+}
+
+func TestBound(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		Bound(g)
+	}
+
+	cases := []struct {
+		name   string
+		input  orb.Geometry
+		result orb.Bound
+	}{
+		{
+			name:   "point",
+			input:  orb.Point{1, 2},
+			result: orb.Bound{Min: orb.Point{1, 2}, Max: orb.Point{1, 2}},
+		},
+		{
+			name:   "points on either side",
+			input:  orb.MultiPoint{{170, 0}, {-170, 10}},
+			result: orb.Bound{Min: orb.Point{170, 0}, Max: orb.Point{-170, 10}},
+		},
+		{
+			name:   "points far apart",
+			input:  orb.MultiPoint{{100, 0}, {-100, 10}, {0, 5}},
+			result: orb.Bound{Min: orb.Point{-100, 0}, Max: orb.Point{100, 10}},
+		},
+		{
+			name:   "line does not cross",
+			input:  orb.LineString{{-170, 0}, {0, 0}, {170, 10}},
+			result: orb.Bound{Min: orb.Point{-170, 0}, Max: orb.Point{170, 10}},
+		},
+		{
+			name:   "line crosses",
+			input:  orb.LineString{{-170, 0}, {170, 10}},
+			result: orb.Bound{Min: orb.Point{170, 0}, Max: orb.Point{-170, 10}},
+		},
+		{
+			name:   "fiji",
+			input:  orb.Ring{{177, -18}, {-179, -18}, {-179, -16}, {177, -16}, {177, -18}},
+			result: orb.Bound{Min: orb.Point{177, -18}, Max: orb.Point{-179, -16}},
+		},
+		{
+			name:   "around the south pole",
+			input:  orb.Polygon{{{-90, -80}, {180, -80}, {90, -80}, {0, -80}}},
+			result: orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, -80}},
+		},
+		{
+			name: "collection",
+			input: orb.Collection{
+				orb.Point{175, 0},
+				orb.LineString{{-175, 5}, {-170, 5}},
+			},
+			result: orb.Bound{Min: orb.Point{175, 0}, Max: orb.Point{-170, 5}},
+		},
+		{
+			name:   "empty",
+			input:  orb.LineString{},
+			result: orb.LineString{}.Bound(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Bound(tc.input)
+			if result != tc.result {
+				t.Errorf("incorrect bound: %v != %v", result, tc.result)
+			}
+		})
+	}
+}
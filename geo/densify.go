@@ -0,0 +1,86 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// Densify returns a copy of the geometry with points added along the
+// great circle path between each pair of points so that no segment is
+// longer than the given number of meters. The new points are evenly spaced
+// and the existing points are kept. Longitudes of the new points are in
+// the range [-180, 180] so use SplitAntimeridian if a path crosses it.
+func Densify(g orb.Geometry, maxSegmentMeters float64) orb.Geometry {
+	if g == nil {
+		return nil
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		return g
+	case orb.MultiPoint:
+		return g.Clone()
+	case orb.LineString:
+		return orb.LineString(densify(g, maxSegmentMeters))
+	case orb.MultiLineString:
+		mls := make(orb.MultiLineString, 0, len(g))
+		for _, ls := range g {
+			mls = append(mls, densify(ls, maxSegmentMeters))
+		}
+		return mls
+	case orb.Ring:
+		return orb.Ring(densify(g, maxSegmentMeters))
+	case orb.Polygon:
+		return densifyPolygon(g, maxSegmentMeters)
+	case orb.MultiPolygon:
+		mp := make(orb.MultiPolygon, 0, len(g))
+		for _, p := range g {
+			mp = append(mp, densifyPolygon(p, maxSegmentMeters))
+		}
+		return mp
+	case orb.Collection:
+		c := make(orb.Collection, 0, len(g))
+		for _, geom := range g {
+			c = append(c, Densify(geom, maxSegmentMeters))
+		}
+		return c
+	case orb.Bound:
+		return Densify(g.ToRing(), maxSegmentMeters)
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func densifyPolygon(p orb.Polygon, maxSegmentMeters float64) orb.Polygon {
+	result := make(orb.Polygon, 0, len(p))
+	for _, r := range p {
+		result = append(result, densify(r, maxSegmentMeters))
+	}
+
+	return result
+}
+
+func densify(ls []orb.Point, maxSegmentMeters float64) []orb.Point {
+	if len(ls) == 0 {
+		return []orb.Point{}
+	}
+
+	result := make([]orb.Point, 0, len(ls))
+	result = append(result, ls[0])
+	for i := 1; i < len(ls); i++ {
+		p1, p2 := ls[i-1], ls[i]
+
+		if maxSegmentMeters > 0 {
+			n := math.Ceil(DistanceHaversine(p1, p2) / maxSegmentMeters)
+			for j := 1.0; j < n; j++ {
+				result = append(result, Interpolate(p1, p2, j/n))
+			}
+		}
+
+		result = append(result, p2)
+	}
+
+	return result
+}
@@ -0,0 +1,55 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestDensify(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		Densify(g, 1000)
+	}
+
+	ls := orb.LineString{{0, 0}, {10, 0}, {10, 0.001}}
+	result := Densify(ls, 200000).(orb.LineString)
+
+	// 1113 km / 200 km = 6 segments plus the short one
+	if len(result) != 8 {
+		t.Fatalf("incorrect number of points: %v", len(result))
+	}
+
+	if result[0] != ls[0] || result[6] != ls[1] || result[7] != ls[2] {
+		t.Errorf("should keep the original points: %v", result)
+	}
+
+	for i := 1; i < len(result); i++ {
+		if d := DistanceHaversine(result[i-1], result[i]); d > 200000 {
+			t.Errorf("segment %d too long: %v", i, d)
+		}
+	}
+
+	if len(ls) != 3 {
+		t.Errorf("should not modify the input")
+	}
+
+	t.Run("polygon", func(t *testing.T) {
+		p := orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}
+		result := Densify(p, 50000).(orb.Polygon)
+		if l := len(result[0]); l != 13 {
+			t.Errorf("incorrect number of points: %v", l)
+		}
+
+		if !result[0].Closed() {
+			t.Errorf("ring should be closed")
+		}
+	})
+
+	t.Run("non-positive max", func(t *testing.T) {
+		result := Densify(ls, 0).(orb.LineString)
+		if !result.Equal(ls) {
+			t.Errorf("should not change: %v", result)
+		}
+	})
+}
@@ -70,6 +70,39 @@ func Midpoint(p, p2 orb.Point) orb.Point {
 	return r
 }
 
+// Interpolate returns the point at the fraction of the way along the great circle
+// path between the two points. A fraction of 0 returns p1 and 1 returns p2.
+// The path between antipodal points is not defined.
+func Interpolate(p1, p2 orb.Point, fraction float64) orb.Point {
+	if fraction == 0 || p1 == p2 {
+		return p1
+	}
+
+	if fraction == 1 {
+		return p2
+	}
+
+	lat1, lon1 := deg2rad(p1[1]), deg2rad(p1[0])
+	lat2, lon2 := deg2rad(p2[1]), deg2rad(p2[0])
+
+	d := DistanceHaversine(p1, p2) / orb.EarthRadius
+	if d == 0 {
+		return p1
+	}
+
+	a := math.Sin((1-fraction)*d) / math.Sin(d)
+	b := math.Sin(fraction*d) / math.Sin(d)
+
+	x := a*math.Cos(lat1)*math.Cos(lon1) + b*math.Cos(lat2)*math.Cos(lon2)
+	y := a*math.Cos(lat1)*math.Sin(lon1) + b*math.Cos(lat2)*math.Sin(lon2)
+	z := a*math.Sin(lat1) + b*math.Sin(lat2)
+
+	return orb.Point{
+		rad2deg(math.Atan2(y, x)),
+		rad2deg(math.Atan2(z, math.Sqrt(x*x+y*y))),
+	}
+}
+
 // PointAtBearingAndDistance returns the point at the given bearing and distance in meters from the point
 func PointAtBearingAndDistance(p orb.Point, bearing, distance float64) orb.Point {
 	aLat := deg2rad(p[1])
@@ -0,0 +1,144 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// CrossTrackDistance returns the distance in meters from the point to the
+// great circle going through a and b. It is positive if the point is to the
+// right of the path from a to b and negative if to the left.
+func CrossTrackDistance(a, b, p orb.Point) float64 {
+	d13 := DistanceHaversine(a, p) / orb.EarthRadius
+	dt := deg2rad(Bearing(a, p) - Bearing(a, b))
+
+	return math.Asin(math.Sin(d13)*math.Sin(dt)) * orb.EarthRadius
+}
+
+// AlongTrackDistance returns the distance in meters from a to the point
+// on the great circle going through a and b that is closest to p.
+// It is negative if the closest point is behind a.
+func AlongTrackDistance(a, b, p orb.Point) float64 {
+	d13 := DistanceHaversine(a, p) / orb.EarthRadius
+	dt := deg2rad(Bearing(a, p) - Bearing(a, b))
+
+	// from the right spherical triangle, tan(at) = tan(d13) * cos(dt)
+	return math.Atan2(math.Sin(d13)*math.Cos(dt), math.Cos(d13)) * orb.EarthRadius
+}
+
+// DistanceFromSegment returns the distance in meters from the point to
+// the great circle segment [a, b].
+func DistanceFromSegment(a, b, p orb.Point) float64 {
+	if a == b {
+		return DistanceHaversine(a, p)
+	}
+
+	at := AlongTrackDistance(a, b, p)
+	if at <= 0 {
+		return DistanceHaversine(a, p)
+	}
+
+	if at >= DistanceHaversine(a, b) {
+		return DistanceHaversine(b, p)
+	}
+
+	return math.Abs(CrossTrackDistance(a, b, p))
+}
+
+// DistanceFrom returns the distance in meters from the point to the boundary
+// of the geometry, computed on the sphere, plus an index. For line strings
+// and rings the index is of the closest segment, for multi points it is of the
+// closest point, otherwise it is of the closest sub-geometry, such as the ring
+// of a polygon or the line string of a multi line string.
+func DistanceFrom(g orb.Geometry, p orb.Point) (float64, int) {
+	if g == nil {
+		return math.Inf(1), -1
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		return DistanceHaversine(g, p), 0
+	case orb.MultiPoint:
+		dist := math.Inf(1)
+		index := -1
+		for i := range g {
+			if d := DistanceHaversine(g[i], p); d < dist {
+				dist = d
+				index = i
+			}
+		}
+
+		return dist, index
+	case orb.LineString:
+		return lineStringDistanceFrom(g, p)
+	case orb.MultiLineString:
+		dist := math.Inf(1)
+		index := -1
+		for i, ls := range g {
+			if d, _ := lineStringDistanceFrom(ls, p); d < dist {
+				dist = d
+				index = i
+			}
+		}
+
+		return dist, index
+	case orb.Ring:
+		return lineStringDistanceFrom(orb.LineString(g), p)
+	case orb.Polygon:
+		dist := math.Inf(1)
+		index := -1
+		for i, r := range g {
+			if d, _ := lineStringDistanceFrom(orb.LineString(r), p); d < dist {
+				dist = d
+				index = i
+			}
+		}
+
+		return dist, index
+	case orb.MultiPolygon:
+		dist := math.Inf(1)
+		index := -1
+		for i, poly := range g {
+			if d, _ := DistanceFrom(poly, p); d < dist {
+				dist = d
+				index = i
+			}
+		}
+
+		return dist, index
+	case orb.Collection:
+		dist := math.Inf(1)
+		index := -1
+		for i, ge := range g {
+			if d, _ := DistanceFrom(ge, p); d < dist {
+				dist = d
+				index = i
+			}
+		}
+
+		return dist, index
+	case orb.Bound:
+		return DistanceFrom(g.ToRing(), p)
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func lineStringDistanceFrom(ls orb.LineString, p orb.Point) (float64, int) {
+	if len(ls) == 1 {
+		return DistanceHaversine(ls[0], p), 0
+	}
+
+	dist := math.Inf(1)
+	index := -1
+	for i := 0; i < len(ls)-1; i++ {
+		if d := DistanceFromSegment(ls[i], ls[i+1], p); d < dist {
+			dist = d
+			index = i
+		}
+	}
+
+	return dist, index
+}
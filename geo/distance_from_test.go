@@ -0,0 +1,177 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestCrossTrackDistance(t *testing.T) {
+	a := orb.Point{0, 0}
+	b := orb.Point{10, 0}
+
+	// one degree north of the equator is to the left
+	d := CrossTrackDistance(a, b, orb.Point{5, 1})
+	if math.Abs(d+DistanceHaversine(orb.Point{5, 0}, orb.Point{5, 1})) > 1e-6 {
+		t.Errorf("incorrect distance: %v", d)
+	}
+
+	// to the right
+	d = CrossTrackDistance(a, b, orb.Point{5, -1})
+	if math.Abs(d-DistanceHaversine(orb.Point{5, 0}, orb.Point{5, -1})) > 1e-6 {
+		t.Errorf("incorrect distance: %v", d)
+	}
+
+	// past the end is still on the great circle
+	d = CrossTrackDistance(a, b, orb.Point{20, 0})
+	if math.Abs(d) > 1e-6 {
+		t.Errorf("incorrect distance: %v", d)
+	}
+}
+
+func TestAlongTrackDistance(t *testing.T) {
+	a := orb.Point{0, 0}
+	b := orb.Point{10, 0}
+
+	cases := []struct {
+		name   string
+		point  orb.Point
+		result float64
+	}{
+		{
+			name:   "on the segment",
+			point:  orb.Point{5, 0},
+			result: DistanceHaversine(a, orb.Point{5, 0}),
+		},
+		{
+			name:   "off the segment",
+			point:  orb.Point{3, 2},
+			result: DistanceHaversine(a, orb.Point{3, 0}),
+		},
+		{
+			name:   "behind the start",
+			point:  orb.Point{-2, 1},
+			result: -DistanceHaversine(a, orb.Point{-2, 0}),
+		},
+		{
+			name:   "past the end",
+			point:  orb.Point{20, -1},
+			result: DistanceHaversine(a, orb.Point{20, 0}),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := AlongTrackDistance(a, b, tc.point)
+			if math.Abs(d-tc.result) > 1e-6 {
+				t.Errorf("incorrect distance: %v != %v", d, tc.result)
+			}
+		})
+	}
+}
+
+func TestDistanceFromSegment(t *testing.T) {
+	a := orb.Point{0, 0}
+	b := orb.Point{10, 0}
+
+	cases := []struct {
+		name   string
+		point  orb.Point
+		result float64
+	}{
+		{
+			name:   "beside",
+			point:  orb.Point{5, 1},
+			result: DistanceHaversine(orb.Point{5, 0}, orb.Point{5, 1}),
+		},
+		{
+			name:   "before the start",
+			point:  orb.Point{-1, 1},
+			result: DistanceHaversine(a, orb.Point{-1, 1}),
+		},
+		{
+			name:   "after the end",
+			point:  orb.Point{11, 1},
+			result: DistanceHaversine(b, orb.Point{11, 1}),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := DistanceFromSegment(a, b, tc.point)
+			if math.Abs(d-tc.result) > 1e-6 {
+				t.Errorf("incorrect distance: %v != %v", d, tc.result)
+			}
+		})
+	}
+
+	if d := DistanceFromSegment(a, a, b); d != DistanceHaversine(a, b) {
+		t.Errorf("incorrect distance for degenerate segment: %v", d)
+	}
+}
+
+func TestDistanceFrom(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		DistanceFrom(g, orb.Point{})
+	}
+
+	ls := orb.LineString{{0, 0}, {1, 0}, {1, 1}, {2, 1}}
+	cases := []struct {
+		name     string
+		geometry orb.Geometry
+		point    orb.Point
+		distance float64
+		index    int
+	}{
+		{
+			name:     "first segment",
+			geometry: ls,
+			point:    orb.Point{0.5, -0.1},
+			distance: DistanceHaversine(orb.Point{0.5, 0}, orb.Point{0.5, -0.1}),
+			index:    0,
+		},
+		{
+			name:     "middle segment",
+			geometry: ls,
+			point:    orb.Point{1.1, 0.5},
+			distance: DistanceFromSegment(orb.Point{1, 0}, orb.Point{1, 1}, orb.Point{1.1, 0.5}),
+			index:    1,
+		},
+		{
+			name:     "multi point",
+			geometry: orb.MultiPoint{{0, 0}, {1, 1}},
+			point:    orb.Point{1, 1.5},
+			distance: DistanceHaversine(orb.Point{1, 1}, orb.Point{1, 1.5}),
+			index:    1,
+		},
+		{
+			name:     "polygon hole",
+			geometry: orb.Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}, {{1, 1}, {1, 2}, {2, 2}, {2, 1}, {1, 1}}},
+			point:    orb.Point{1.5, 1.5},
+			distance: DistanceFromSegment(orb.Point{1, 1}, orb.Point{1, 2}, orb.Point{1.5, 1.5}),
+			index:    1,
+		},
+		{
+			name:     "multi line string",
+			geometry: orb.MultiLineString{{{0, 5}, {1, 5}}, ls},
+			point:    orb.Point{2.5, 1},
+			distance: DistanceHaversine(orb.Point{2, 1}, orb.Point{2.5, 1}),
+			index:    1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, i := DistanceFrom(tc.geometry, tc.point)
+			if math.Abs(d-tc.distance) > 1e-6 {
+				t.Errorf("incorrect distance: %v != %v", d, tc.distance)
+			}
+
+			if i != tc.index {
+				t.Errorf("incorrect index: %v != %v", i, tc.index)
+			}
+		})
+	}
+}
@@ -217,3 +217,39 @@ func TestPointAtDistanceAlongLineWithEmptyLineString(t *testing.T) {
 	line := orb.LineString{}
 	PointAtDistanceAlongLine(line, 90000)
 }
+
+func TestInterpolate(t *testing.T) {
+	p1 := orb.Point{-73.77888889, 40.63972222}
+	p2 := orb.Point{-0.46194444, 51.4775}
+
+	if p := Interpolate(p1, p2, 0.5); math.Abs(p[0]-Midpoint(p1, p2)[0]) > 1e-9 || math.Abs(p[1]-Midpoint(p1, p2)[1]) > 1e-9 {
+		t.Errorf("should be the midpoint: %v != %v", p, Midpoint(p1, p2))
+	}
+
+	if p := Interpolate(p1, p2, 0); p != p1 {
+		t.Errorf("should be the start: %v", p)
+	}
+
+	if p := Interpolate(p1, p2, 1); p != p2 {
+		t.Errorf("should be the end: %v", p)
+	}
+
+	if p := Interpolate(p1, p1, 0.3); p != p1 {
+		t.Errorf("should be the same point: %v", p)
+	}
+
+	// along the equator
+	p := Interpolate(orb.Point{0, 0}, orb.Point{10, 0}, 0.25)
+	if math.Abs(p[0]-2.5) > 1e-9 || math.Abs(p[1]) > 1e-9 {
+		t.Errorf("incorrect point: %v", p)
+	}
+
+	// the distances should be proportional
+	d := DistanceHaversine(p1, p2)
+	for _, f := range []float64{0.1, 0.25, 0.6, 0.9} {
+		p := Interpolate(p1, p2, f)
+		if v := DistanceHaversine(p1, p); math.Abs(v-f*d) > 1e-3 {
+			t.Errorf("incorrect distance for %v: %v != %v", f, v, f*d)
+		}
+	}
+}
@@ -52,3 +52,73 @@ func ExampleLength() {
 	// Output:
 	// 325 meters
 }
+
+func ExampleGeodesicInverse() {
+	jfk := orb.Point{-73.77888889, 40.63972222}
+	lhr := orb.Point{-0.46194444, 51.4775}
+
+	d, forward, back := geo.GeodesicInverse(jfk, lhr)
+
+	fmt.Printf("%0.3f meters, %0.3f, %0.3f", d, forward, back)
+	// Output:
+	// 5554307.548 meters, 51.373, -72.029
+}
+
+func ExampleGeodesicArea() {
+	poly := orb.Polygon{
+		{
+			{-122.4163816, 37.7792782},
+			{-122.4162786, 37.7787626},
+			{-122.4151027, 37.7789118},
+			{-122.4152143, 37.7794274},
+			{-122.4163816, 37.7792782},
+		},
+	}
+	a := geo.GeodesicArea(poly)
+
+	fmt.Printf("%f m^2", a)
+	// Output:
+	// 6063.138336 m^2
+}
+
+func ExampleSplitAntimeridian() {
+	// a flight path from Auckland to Honolulu
+	path := orb.LineString{{174.79, -37.01}, {-157.92, 21.32}}
+
+	split := geo.SplitAntimeridian(path)
+
+	fmt.Println(len(split.(orb.MultiLineString)))
+	fmt.Println(geo.Bound(path))
+	// Output:
+	// 2
+	// {[174.79 -37.01] [-157.92 21.32]}
+}
+
+func ExampleDensify() {
+	jfk := orb.Point{-73.77888889, 40.63972222}
+	lhr := orb.Point{-0.46194444, 51.4775}
+
+	route := geo.Densify(orb.LineString{jfk, lhr}, 1000000).(orb.LineString)
+	for _, p := range route {
+		fmt.Printf("%0.2f, %0.2f\n", p[0], p[1])
+	}
+	// Output:
+	// -73.78, 40.64
+	// -64.52, 45.47
+	// -53.71, 49.43
+	// -41.31, 52.22
+	// -27.71, 53.57
+	// -13.76, 53.31
+	// -0.46, 51.48
+}
+
+func ExampleDistanceFrom() {
+	route := orb.LineString{{-122.4194, 37.7749}, {-122.2711, 37.8044}, {-122.2585, 37.8716}}
+	ping := orb.Point{-122.27, 37.83}
+
+	d, i := geo.DistanceFrom(route, ping)
+
+	fmt.Printf("%0.1f meters from segment %d", d, i)
+	// Output:
+	// 321.6 meters from segment 1
+}
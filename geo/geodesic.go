@@ -0,0 +1,1134 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/length"
+)
+
+// GeodesicDistance returns the shortest distance in meters between two points
+// on the WGS84 ellipsoid. It is accurate to within a few nanometers but is
+// slower than Distance and DistanceHaversine that assume a spherical earth.
+func GeodesicDistance(p1, p2 orb.Point) float64 {
+	r := wgs84.inverse(p1[1], p1[0], p2[1], p2[0], false)
+	return r.distance
+}
+
+// GeodesicInverse returns the shortest distance in meters between two points
+// on the WGS84 ellipsoid along with the forward azimuth, the direction at p1
+// towards p2, and the back azimuth, the direction at p2 back towards p1.
+// Azimuths are in degrees clockwise from north in the range [-180, 180].
+func GeodesicInverse(p1, p2 orb.Point) (distance, forward, back float64) {
+	r := wgs84.inverse(p1[1], p1[0], p2[1], p2[0], false)
+	return r.distance, atan2d(r.salp1, r.calp1), atan2d(-r.salp2, -r.calp2)
+}
+
+// GeodesicDirect returns the point at the given distance in meters from
+// the point traveling along the geodesic that starts at the azimuth,
+// in degrees clockwise from north, on the WGS84 ellipsoid.
+func GeodesicDirect(p orb.Point, azimuth, distance float64) orb.Point {
+	l := wgs84.line(p[1], p[0], azimuth)
+	lat, lon, _ := l.position(distance)
+
+	return orb.Point{lon, lat}
+}
+
+// GeodesicLength returns the length of the boundary of the geometry
+// using the geodesic distance on the WGS84 ellipsoid.
+func GeodesicLength(g orb.Geometry) float64 {
+	return length.Length(g, GeodesicDistance)
+}
+
+// GeodesicArea returns the area of the geometry in square meters on the
+// WGS84 ellipsoid. The edges of polygons are geodesics and rings may
+// encircle a pole. Holes are subtracted from their polygon.
+func GeodesicArea(g orb.Geometry) float64 {
+	if g == nil {
+		return 0
+	}
+
+	switch g := g.(type) {
+	case orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString:
+		return 0
+	case orb.Ring:
+		return math.Abs(geodesicRingArea(g))
+	case orb.Polygon:
+		return geodesicPolygonArea(g)
+	case orb.MultiPolygon:
+		sum := 0.0
+		for _, p := range g {
+			sum += geodesicPolygonArea(p)
+		}
+		return sum
+	case orb.Collection:
+		sum := 0.0
+		for _, c := range g {
+			sum += GeodesicArea(c)
+		}
+		return sum
+	case orb.Bound:
+		return GeodesicArea(g.ToRing())
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+// GeodesicSignedArea returns the signed area of the ring in square meters
+// on the WGS84 ellipsoid. Will return negative if the ring is in the clockwise
+// direction. Will implicitly close the ring.
+func GeodesicSignedArea(r orb.Ring) float64 {
+	return geodesicRingArea(r)
+}
+
+func geodesicPolygonArea(p orb.Polygon) float64 {
+	if len(p) == 0 {
+		return 0
+	}
+
+	sum := math.Abs(geodesicRingArea(p[0]))
+	for i := 1; i < len(p); i++ {
+		sum -= math.Abs(geodesicRingArea(p[i]))
+	}
+
+	return sum
+}
+
+// geodesicRingArea sums the area between each edge and the equator.
+// Every time the ring crosses the prime meridian is tracked so rings
+// that encircle a pole can be corrected.
+func geodesicRingArea(r orb.Ring) float64 {
+	if len(r) < 3 {
+		return 0
+	}
+
+	l := len(r)
+	if r[0] == r[l-1] {
+		l--
+	}
+
+	var area, err float64
+	crossings := 0
+	for i := 0; i < l; i++ {
+		p1, p2 := r[i], r[(i+1)%l]
+
+		res := wgs84.inverse(p1[1], p1[0], p2[1], p2[0], true)
+		var e float64
+		area, e = sum(area, res.area)
+		err += e
+
+		crossings += transit(p1[0], p2[0])
+	}
+
+	// the total area of the ellipsoid
+	area0 := 4 * math.Pi * wgs84.c2
+
+	area = math.Remainder(area+err, area0)
+	if crossings%2 != 0 {
+		if area < 0 {
+			area += area0 / 2
+		} else {
+			area -= area0 / 2
+		}
+	}
+
+	// counter clockwise is positive, in the range (-area0/2, area0/2]
+	area = -area
+	if area > area0/2 {
+		area -= area0
+	} else if area <= -area0/2 {
+		area += area0
+	}
+
+	return 0 + area
+}
+
+// transit returns 1 or -1 if the edge crosses the prime meridian going
+// east or west respectively.
+func transit(lon1, lon2 float64) int {
+	lon12, _ := angDiff(lon1, lon2)
+	lon1 = angNormalize(lon1)
+	lon2 = angNormalize(lon2)
+
+	if lon12 > 0 && ((lon1 < 0 && lon2 >= 0) || (lon1 > 0 && lon2 == 0)) {
+		return 1
+	}
+
+	if lon12 < 0 && lon1 >= 0 && lon2 < 0 {
+		return -1
+	}
+
+	return 0
+}
+
+// The WGS84 ellipsoid.
+const (
+	wgs84Radius     = 6378137.0
+	wgs84Flattening = 1 / 298.257223563
+)
+
+var wgs84 = newGeodesic(wgs84Radius, wgs84Flattening)
+
+// The geodesic calculations below are a port of GeographicLib by Charles Karney,
+// see https://geographiclib.sourceforge.io and
+// C. F. F. Karney, Algorithms for geodesics, J. Geodesy 87, 43-55 (2013).
+// Only ellipsoids with a positive flattening are supported.
+const (
+	geodesicOrder = 6
+	nC3x          = geodesicOrder * (geodesicOrder - 1) / 2
+	nC4x          = geodesicOrder * (geodesicOrder + 1) / 2
+	maxit1        = 20
+	maxit2        = maxit1 + 53 + 10
+)
+
+var (
+	tiny    = math.Sqrt(math.SmallestNonzeroFloat64 * (1 << 52))
+	tol0    = math.Nextafter(1, 2) - 1
+	tol1    = 200 * tol0
+	tol2    = math.Sqrt(tol0)
+	tolb    = tol0 * tol2
+	xthresh = 1000 * tol2
+)
+
+type geodesic struct {
+	a, f, f1, e2, ep2, n, b, c2, etol2 float64
+
+	a3x [geodesicOrder]float64
+	c3x [nC3x]float64
+	c4x [nC4x]float64
+}
+
+func newGeodesic(a, f float64) *geodesic {
+	g := &geodesic{a: a, f: f}
+	g.f1 = 1 - f
+	g.e2 = f * (2 - f)
+	g.ep2 = g.e2 / (g.f1 * g.f1)
+	g.n = f / (2 - f)
+	g.b = a * g.f1
+
+	// authalic radius squared
+	g.c2 = (a*a + g.b*g.b*math.Atanh(math.Sqrt(g.e2))/math.Sqrt(g.e2)) / 2
+	g.etol2 = 0.1 * tol2 / math.Sqrt(math.Max(0.001, math.Abs(f))*math.Min(1, 1-f/2)/2)
+
+	g.a3coeff()
+	g.c3coeff()
+	g.c4coeff()
+
+	return g
+}
+
+// geodesicResult is the solution to the inverse problem. The azimuths
+// are the forward directions at each end as sin/cos pairs. The area is
+// between the geodesic and the equator and is only computed if requested.
+type geodesicResult struct {
+	distance     float64
+	salp1, calp1 float64
+	salp2, calp2 float64
+	area         float64
+}
+
+func (g *geodesic) inverse(lat1, lon1, lat2, lon2 float64, area bool) geodesicResult {
+	var c1a, c2a [geodesicOrder + 1]float64
+	var c3a [geodesicOrder]float64
+
+	lon12, lon12s := angDiff(lon1, lon2)
+	lonsign := 1.0
+	if lon12 < 0 {
+		lonsign = -1
+	}
+
+	lon12 = lonsign * angRound(lon12)
+	lon12s = angRound((180 - lon12) - lonsign*lon12s)
+	lam12 := deg2rad(lon12)
+
+	var slam12, clam12 float64
+	if lon12 > 90 {
+		slam12, clam12 = sincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosd(lon12)
+	}
+
+	lat1 = angRound(latFix(lat1))
+	lat2 = angRound(latFix(lat2))
+
+	// make lat1 the one with the larger absolute value
+	swapp := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+
+	// make lat1 <= 0
+	latsign := -1.0
+	if lat1 < 0 {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1 *= g.f1
+	sbet1, cbet1 = norm(sbet1, cbet1)
+	cbet1 = math.Max(tiny, cbet1)
+
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2 *= g.f1
+	sbet2, cbet2 = norm(sbet2, cbet2)
+	cbet2 = math.Max(tiny, cbet2)
+
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+
+	dn1 := math.Sqrt(1 + g.ep2*sbet1*sbet1)
+	dn2 := math.Sqrt(1 + g.ep2*sbet2*sbet2)
+
+	var (
+		s12x, m12x, sig12 float64
+		salp1, calp1      float64
+		salp2, calp2      float64
+		ssig1, csig1      float64
+		ssig2, csig2      float64
+		eps               float64
+	)
+
+	// somg12 > 1 marks that it needs to be calculated from omg12
+	somg12, comg12, omg12 := 2.0, 0.0, 0.0
+
+	meridian := lat1 == -90 || slam12 == 0
+	if meridian {
+		// the endpoints are on a meridian or one is at a pole
+		calp1, salp1 = clam12, slam12
+		calp2, salp2 = 1, 0
+
+		ssig1, csig1 = sbet1, calp1*cbet1
+		ssig2, csig2 = sbet2, calp2*cbet2
+
+		sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+		s12x, m12x, _ = g.lengths(g.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, c1a[:], c2a[:])
+
+		if sig12 < 1 || m12x >= 0 {
+			if sig12 < 3*tiny {
+				sig12, m12x, s12x = 0, 0, 0
+			}
+			m12x *= g.b
+			s12x *= g.b
+		} else {
+			// m12 < 0, ie. prolate and too close to the anti-podal point
+			meridian = false
+		}
+	}
+
+	if !meridian && sbet1 == 0 && (g.f <= 0 || lon12s >= g.f*180) {
+		// along the equator
+		calp1, calp2 = 0, 0
+		salp1, salp2 = 1, 1
+		s12x = g.a * lam12
+		omg12 = lam12 / g.f1
+	} else if !meridian {
+		var dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = g.inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, lam12, slam12, clam12)
+
+		if sig12 >= 0 {
+			// short lines
+			s12x = sig12 * g.b * dnm
+			omg12 = lam12 / (g.f1 * dnm)
+		} else {
+			// solve for alp1 with newton's method, falling back to bisection
+			var v, dv, domg12 float64
+			tripn, tripb := false, false
+			salp1a, calp1a := tiny, 1.0
+			salp1b, calp1b := tiny, -1.0
+
+			for numit := 0; numit < maxit2; {
+				v, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dv = g.lambda12(
+					sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam12, clam12,
+					numit < maxit1, c1a[:], c2a[:], c3a[:])
+
+				tol := tol0
+				if tripn {
+					tol *= 8
+				}
+				if tripb || !(math.Abs(v) >= tol) {
+					break
+				}
+
+				// update the bracketing values
+				if v > 0 && (numit > maxit1 || calp1/salp1 > calp1b/salp1b) {
+					salp1b, calp1b = salp1, calp1
+				} else if v < 0 && (numit > maxit1 || calp1/salp1 < calp1a/salp1a) {
+					salp1a, calp1a = salp1, calp1
+				}
+
+				numit++
+				if numit < maxit1 && dv > 0 {
+					dalp1 := -v / dv
+					sdalp1, cdalp1 := math.Sincos(dalp1)
+					nsalp1 := salp1*cdalp1 + calp1*sdalp1
+					if nsalp1 > 0 && math.Abs(dalp1) < math.Pi {
+						calp1 = calp1*cdalp1 - salp1*sdalp1
+						salp1 = nsalp1
+						salp1, calp1 = norm(salp1, calp1)
+						tripn = math.Abs(v) <= 16*tol0
+						continue
+					}
+				}
+
+				salp1 = (salp1a + salp1b) / 2
+				calp1 = (calp1a + calp1b) / 2
+				salp1, calp1 = norm(salp1, calp1)
+				tripn = false
+				tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < tolb ||
+					math.Abs(salp1-salp1b)+(calp1-calp1b) < tolb
+			}
+
+			s12x, _, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, c1a[:], c2a[:])
+			s12x *= g.b
+
+			// omg12 = lam12 - domg12
+			sdomg12, cdomg12 := math.Sincos(domg12)
+			somg12 = slam12*cdomg12 - clam12*sdomg12
+			comg12 = clam12*cdomg12 + slam12*sdomg12
+		}
+	}
+
+	var s12 float64
+	if area {
+		s12 = g.area(sbet1, cbet1, sbet2, cbet2, salp1, calp1, salp2, calp2, somg12, comg12, omg12, meridian)
+		s12 *= swapp * lonsign * latsign
+	}
+
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+	}
+
+	salp1 *= swapp * lonsign
+	calp1 *= swapp * latsign
+	salp2 *= swapp * lonsign
+	calp2 *= swapp * latsign
+
+	return geodesicResult{
+		distance: 0 + s12x,
+		salp1:    salp1,
+		calp1:    calp1,
+		salp2:    salp2,
+		calp2:    calp2,
+		area:     0 + s12,
+	}
+}
+
+// area returns the area between the geodesic and the equator using the
+// values found when solving the inverse problem.
+func (g *geodesic) area(
+	sbet1, cbet1, sbet2, cbet2, salp1, calp1, salp2, calp2, somg12, comg12, omg12 float64,
+	meridian bool,
+) float64 {
+	var s12 float64
+
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+	if calp0 != 0 && salp0 != 0 {
+		ssig1, csig1 := norm(sbet1, calp1*cbet1)
+		ssig2, csig2 := norm(sbet2, calp2*cbet2)
+
+		k2 := calp0 * calp0 * g.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		a4 := g.a * g.a * calp0 * salp0 * g.e2
+
+		var c4a [geodesicOrder]float64
+		g.c4f(eps, c4a[:])
+		b41 := sinCosSeries(false, ssig1, csig1, c4a[:])
+		b42 := sinCosSeries(false, ssig2, csig2, c4a[:])
+		s12 = a4 * (b42 - b41)
+	}
+	// else avoid problems with indeterminate sig1, sig2 on the equator
+
+	if !meridian && somg12 == 2 {
+		somg12, comg12 = math.Sincos(omg12)
+	}
+
+	var alp12 float64
+	if !meridian && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+		// the longitude and latitude differences are not too big so use
+		// tan(gamma/2) = tan(omg12/2) * (tan(bet1/2)+tan(bet2/2))/(1+tan(bet1/2)*tan(bet2/2))
+		domg12 := 1 + comg12
+		dbet1 := 1 + cbet1
+		dbet2 := 1 + cbet2
+		alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1), domg12*(sbet1*sbet2+dbet1*dbet2))
+	} else {
+		// alp12 = alp2 - alp1, used in atan2 so no need to normalize
+		salp12 := salp2*calp1 - calp2*salp1
+		calp12 := calp2*calp1 + salp2*salp1
+		if salp12 == 0 && calp12 < 0 {
+			// make sure alp12 is -180 when alp1 = +/-180 and alp2 = 0
+			salp12 = tiny * calp1
+			calp12 = -1
+		}
+		alp12 = math.Atan2(salp12, calp12)
+	}
+
+	return s12 + g.c2*alp12
+}
+
+// inverseStart returns a starting point for newton's method. If sig12 is
+// positive the line is short and the result is the solution.
+func (g *geodesic) inverseStart(
+	sbet1, cbet1, dn1, sbet2, cbet2, lam12, slam12, clam12 float64,
+) (sig12, salp1, calp1, salp2, calp2, dnm float64) {
+	sig12 = -1
+	salp2, calp2, dnm = math.NaN(), math.NaN(), math.NaN()
+
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := (sbet1 + sbet2) * (sbet1 + sbet2)
+		sbetm2 /= sbetm2 + (cbet1+cbet2)*(cbet1+cbet2)
+		dnm = math.Sqrt(1 + g.ep2*sbetm2)
+		omg12 := lam12 / (g.f1 * dnm)
+		somg12, comg12 = math.Sincos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*somg12*somg12/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+	}
+
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	if shortline && ssig12 < g.etol2 {
+		// really short lines
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*(somg12*somg12/(1+comg12))
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm(salp2, calp2)
+		sig12 = math.Atan2(ssig12, csig12)
+	} else if math.Abs(g.n) >= 0.1 || csig12 >= 0 || ssig12 >= 6*math.Abs(g.n)*math.Pi*cbet1*cbet1 {
+		// nothing to do, the zeroth order spherical approximation is fine
+	} else {
+		// nearly antipodal points, scale to the astroid problem
+		lam12x := math.Atan2(-slam12, -clam12)
+
+		k2 := sbet1 * sbet1 * g.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		lamscale := g.f * cbet1 * g.a3f(eps) * math.Pi
+		betscale := lamscale * cbet1
+		x := lam12x / lamscale
+		y := sbet12a / betscale
+
+		if y > -tol1 && x > -1-xthresh {
+			salp1 = math.Min(1, -x)
+			calp1 = -math.Sqrt(1 - salp1*salp1)
+		} else {
+			k := astroid(x, y)
+			omg12a := lamscale * (-x * k / (1 + k))
+			somg12, comg12 = math.Sincos(omg12a)
+			comg12 = -comg12
+
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+		}
+	}
+
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+
+	return sig12, salp1, calp1, salp2, calp2, dnm
+}
+
+func (g *geodesic) lambda12(
+	sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam120, clam120 float64,
+	diffp bool, c1a, c2a, c3a []float64,
+) (lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12 float64) {
+	if sbet1 == 0 && calp1 == 0 {
+		// break the degeneracy of the equatorial line
+		calp1 = -tiny
+	}
+
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+
+	ssig1 = sbet1
+	somg1 := salp0 * sbet1
+	csig1 = calp1 * cbet1
+	comg1 := csig1
+	ssig1, csig1 = norm(ssig1, csig1)
+
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	} else {
+		salp2 = salp1
+	}
+
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var t float64
+		if cbet1 < -sbet1 {
+			t = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			t = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		calp2 = math.Sqrt(calp1*cbet1*calp1*cbet1+t) / cbet2
+	} else {
+		calp2 = math.Abs(calp1)
+	}
+
+	ssig2 = sbet2
+	somg2 := salp0 * sbet2
+	csig2 = calp2 * cbet2
+	comg2 := csig2
+	ssig2, csig2 = norm(ssig2, csig2)
+
+	sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2)
+	comg12 := comg1*comg2 + somg1*somg2
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+
+	k2 := calp0 * calp0 * g.ep2
+	eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	g.c3f(eps, c3a)
+	b312 := sinCosSeries(true, ssig2, csig2, c3a) - sinCosSeries(true, ssig1, csig1, c3a)
+	domg12 = -g.f * g.a3f(eps) * salp0 * (sig12 + b312)
+	lam12 = eta + domg12
+
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * g.f1 * dn1 / sbet1
+		} else {
+			_, dlam12, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, c1a, c2a)
+			dlam12 *= g.f1 / (calp2 * cbet2)
+		}
+	} else {
+		dlam12 = math.NaN()
+	}
+
+	return
+}
+
+// lengths returns the distance and reduced length, divided by b,
+// along with m0, the coefficient of secular term in the reduced length.
+func (g *geodesic) lengths(
+	eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2 float64,
+	c1a, c2a []float64,
+) (s12b, m12b, m0 float64) {
+	a1 := a1m1f(eps)
+	c1f(eps, c1a)
+	a2 := a2m1f(eps)
+	c2f(eps, c2a)
+
+	m0 = a1 - a2
+	a1++
+	a2++
+
+	b1 := sinCosSeries(true, ssig2, csig2, c1a) - sinCosSeries(true, ssig1, csig1, c1a)
+	b2 := sinCosSeries(true, ssig2, csig2, c2a) - sinCosSeries(true, ssig1, csig1, c2a)
+
+	s12b = a1 * (sig12 + b1)
+	j12 := m0*sig12 + (a1*b1 - a2*b2)
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*j12
+
+	return s12b, m12b, m0
+}
+
+// geodesicLine is a geodesic starting at a point and azimuth
+// used to solve the direct problem.
+type geodesicLine struct {
+	g *geodesic
+
+	lat1, lon1    float64
+	salp0, calp0  float64
+	ssig1, csig1  float64
+	somg1, comg1  float64
+	k2, a1m1      float64
+	b11, b31, a3c float64
+	stau1, ctau1  float64
+	c1a, c1pa     [geodesicOrder + 1]float64
+	c3a           [geodesicOrder]float64
+}
+
+func (g *geodesic) line(lat1, lon1, azi1 float64) *geodesicLine {
+	l := &geodesicLine{g: g, lat1: latFix(lat1), lon1: lon1}
+
+	salp1, calp1 := sincosd(angRound(azi1))
+	sbet1, cbet1 := sincosd(angRound(l.lat1))
+	sbet1 *= g.f1
+	sbet1, cbet1 = norm(sbet1, cbet1)
+	cbet1 = math.Max(tiny, cbet1)
+
+	l.salp0 = salp1 * cbet1
+	l.calp0 = math.Hypot(calp1, salp1*sbet1)
+
+	l.ssig1 = sbet1
+	l.somg1 = l.salp0 * sbet1
+	if sbet1 != 0 || calp1 != 0 {
+		l.csig1 = cbet1 * calp1
+	} else {
+		l.csig1 = 1
+	}
+	l.comg1 = l.csig1
+	l.ssig1, l.csig1 = norm(l.ssig1, l.csig1)
+
+	l.k2 = l.calp0 * l.calp0 * g.ep2
+	eps := l.k2 / (2*(1+math.Sqrt(1+l.k2)) + l.k2)
+
+	l.a1m1 = a1m1f(eps)
+	c1f(eps, l.c1a[:])
+	l.b11 = sinCosSeries(true, l.ssig1, l.csig1, l.c1a[:])
+	s, c := math.Sincos(l.b11)
+	l.stau1 = l.ssig1*c + l.csig1*s
+	l.ctau1 = l.csig1*c - l.ssig1*s
+
+	c1pf(eps, l.c1pa[:])
+
+	l.a3c = -g.f * l.salp0 * g.a3f(eps)
+	g.c3f(eps, l.c3a[:])
+	l.b31 = sinCosSeries(true, l.ssig1, l.csig1, l.c3a[:])
+
+	return l
+}
+
+// position returns the latitude, longitude and azimuth at
+// the distance in meters along the line.
+func (l *geodesicLine) position(s12 float64) (lat2, lon2, azi2 float64) {
+	tau12 := s12 / (l.g.b * (1 + l.a1m1))
+	s, c := math.Sincos(tau12)
+
+	b12 := -sinCosSeries(true, l.stau1*c+l.ctau1*s, l.ctau1*c-l.stau1*s, l.c1pa[:])
+	sig12 := tau12 - (b12 - l.b11)
+	ssig12, csig12 := math.Sincos(sig12)
+
+	ssig2 := l.ssig1*csig12 + l.csig1*ssig12
+	csig2 := l.csig1*csig12 - l.ssig1*ssig12
+
+	sbet2 := l.calp0 * ssig2
+	cbet2 := math.Hypot(l.salp0, l.calp0*csig2)
+	if cbet2 == 0 {
+		// the geodesic goes through a pole
+		cbet2, csig2 = tiny, tiny
+	}
+
+	salp2 := l.salp0
+	calp2 := l.calp0 * csig2
+
+	somg2 := l.salp0 * ssig2
+	comg2 := csig2
+	omg12 := math.Atan2(somg2*l.comg1-comg2*l.somg1, comg2*l.comg1+somg2*l.somg1)
+	lam12 := omg12 + l.a3c*(sig12+(sinCosSeries(true, ssig2, csig2, l.c3a[:])-l.b31))
+
+	lon2 = angNormalize(angNormalize(l.lon1) + angNormalize(rad2deg(lam12)))
+	lat2 = atan2d(sbet2, l.g.f1*cbet2)
+	azi2 = atan2d(salp2, calp2)
+
+	return lat2, lon2, azi2
+}
+
+func astroid(x, y float64) float64 {
+	p := x * x
+	q := y * y
+	r := (p + q - 1) / 6
+
+	if q == 0 && r <= 0 {
+		return 0
+	}
+
+	s := p * q / 4
+	r2 := r * r
+	r3 := r * r2
+	disc := s * (s + 2*r3)
+
+	u := r
+	if disc >= 0 {
+		t3 := s + r3
+		if t3 < 0 {
+			t3 -= math.Sqrt(disc)
+		} else {
+			t3 += math.Sqrt(disc)
+		}
+
+		t := math.Cbrt(t3)
+		if t != 0 {
+			u += t + r2/t
+		}
+	} else {
+		ang := math.Atan2(math.Sqrt(-disc), -(s + r3))
+		u += 2 * r * math.Cos(ang/3)
+	}
+
+	v := math.Sqrt(u*u + q)
+
+	var uv float64
+	if u < 0 {
+		uv = q / (v - u)
+	} else {
+		uv = u + v
+	}
+	w := (uv - q) / (2 * v)
+
+	return uv / (math.Sqrt(uv+w*w) + w)
+}
+
+// sinCosSeries evaluates the sum of c[i] * sin(2*i*x) for sinp,
+// or c[i] * cos((2*i+1)*x) otherwise, using Clenshaw summation.
+func sinCosSeries(sinp bool, sinx, cosx float64, c []float64) float64 {
+	k := len(c)
+	n := k
+	if sinp {
+		n--
+	}
+
+	ar := 2 * (cosx - sinx) * (cosx + sinx)
+	y0, y1 := 0.0, 0.0
+	if n&1 == 1 {
+		k--
+		y0 = c[k]
+	}
+
+	for n /= 2; n > 0; n-- {
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+
+	if sinp {
+		return 2 * sinx * cosx * y0
+	}
+
+	return cosx * (y0 - y1)
+}
+
+func polyval(n int, p []float64, x float64) float64 {
+	if n < 0 {
+		return 0
+	}
+
+	y := p[0]
+	for _, v := range p[1 : n+1] {
+		y = y*x + v
+	}
+
+	return y
+}
+
+func a1m1f(eps float64) float64 {
+	coeff := []float64{1, 4, 64, 0, 256}
+	m := geodesicOrder / 2
+	t := polyval(m, coeff, eps*eps) / coeff[m+1]
+
+	return (t + eps) / (1 - eps)
+}
+
+func c1f(eps float64, c []float64) {
+	coeff := []float64{
+		-1, 6, -16, 32,
+		-9, 64, -128, 2048,
+		9, -16, 768,
+		3, -5, 512,
+		-7, 1280,
+		-7, 2048,
+	}
+	seriesCoeff(eps, c, coeff)
+}
+
+func c1pf(eps float64, c []float64) {
+	coeff := []float64{
+		205, -432, 768, 1536,
+		4005, -4736, 3840, 12288,
+		-225, 116, 384,
+		-7173, 2695, 7680,
+		3467, 7680,
+		38081, 61440,
+	}
+	seriesCoeff(eps, c, coeff)
+}
+
+func a2m1f(eps float64) float64 {
+	coeff := []float64{-11, -28, -192, 0, 256}
+	m := geodesicOrder / 2
+	t := polyval(m, coeff, eps*eps) / coeff[m+1]
+
+	return (t - eps) / (1 + eps)
+}
+
+func c2f(eps float64, c []float64) {
+	coeff := []float64{
+		1, 2, 16, 32,
+		35, 64, 384, 2048,
+		15, 80, 768,
+		7, 35, 512,
+		63, 1280,
+		77, 2048,
+	}
+	seriesCoeff(eps, c, coeff)
+}
+
+// seriesCoeff sets c[l], for l >= 1, to eps^l times a polynomial in eps^2.
+func seriesCoeff(eps float64, c, coeff []float64) {
+	eps2 := eps * eps
+	d := eps
+	o := 0
+	for l := 1; l <= geodesicOrder; l++ {
+		m := (geodesicOrder - l) / 2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+func (g *geodesic) a3coeff() {
+	coeff := []float64{
+		-3, 128,
+		-2, -3, 64,
+		-1, -3, -1, 16,
+		3, -1, -2, 8,
+		1, -1, 2,
+		1, 1,
+	}
+
+	o, k := 0, 0
+	for j := geodesicOrder - 1; j >= 0; j-- {
+		m := geodesicOrder - j - 1
+		if j < m {
+			m = j
+		}
+		g.a3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+		k++
+		o += m + 2
+	}
+}
+
+func (g *geodesic) c3coeff() {
+	coeff := []float64{
+		3, 128,
+		2, 5, 128,
+		-1, 3, 3, 64,
+		-1, 0, 1, 8,
+		-1, 1, 4,
+		5, 256,
+		1, 3, 128,
+		-3, -2, 3, 64,
+		1, -3, 2, 32,
+		7, 512,
+		-10, 9, 384,
+		5, -9, 5, 192,
+		7, 512,
+		-14, 7, 512,
+		21, 2560,
+	}
+
+	o, k := 0, 0
+	for l := 1; l < geodesicOrder; l++ {
+		for j := geodesicOrder - 1; j >= l; j-- {
+			m := geodesicOrder - j - 1
+			if j < m {
+				m = j
+			}
+			g.c3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+func (g *geodesic) c4coeff() {
+	coeff := []float64{
+		97, 15015,
+		1088, 156, 45045,
+		-224, -4784, 1573, 45045,
+		-10656, 14144, -4576, -858, 45045,
+		64, 624, -4576, 6864, -3003, 15015,
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		1, 9009,
+		-2944, 468, 135135,
+		5792, 1040, -1287, 135135,
+		5952, -11648, 9152, -2574, 135135,
+		-64, -624, 4576, -6864, 3003, 135135,
+		8, 10725,
+		1856, -936, 225225,
+		-8448, 4992, -1144, 225225,
+		-1440, 4160, -4576, 1716, 225225,
+		-136, 63063,
+		1024, -208, 105105,
+		3584, -3328, 1144, 315315,
+		-128, 135135,
+		-2560, 832, 405405,
+		128, 99099,
+	}
+
+	o, k := 0, 0
+	for l := 0; l < geodesicOrder; l++ {
+		for j := geodesicOrder - 1; j >= l; j-- {
+			m := geodesicOrder - j - 1
+			g.c4x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+func (g *geodesic) a3f(eps float64) float64 {
+	return polyval(geodesicOrder-1, g.a3x[:], eps)
+}
+
+func (g *geodesic) c3f(eps float64, c []float64) {
+	mult := 1.0
+	o := 0
+	for l := 1; l < geodesicOrder; l++ {
+		m := geodesicOrder - l - 1
+		mult *= eps
+		c[l] = mult * polyval(m, g.c3x[o:], eps)
+		o += m + 1
+	}
+}
+
+func (g *geodesic) c4f(eps float64, c []float64) {
+	mult := 1.0
+	o := 0
+	for l := 0; l < geodesicOrder; l++ {
+		m := geodesicOrder - l - 1
+		c[l] = mult * polyval(m, g.c4x[o:], eps)
+		o += m + 1
+		mult *= eps
+	}
+}
+
+// sum returns the sum of the numbers and the roundoff error.
+func sum(u, v float64) (float64, float64) {
+	s := u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+
+	return s, -(up + vpp)
+}
+
+// angRound rounds tiny values so that adding them to 90 or 180 is exact.
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	if x == 0 {
+		return 0
+	}
+
+	y := math.Abs(x)
+	if y < z {
+		y = z - (z - y)
+	}
+
+	return math.Copysign(y, x)
+}
+
+// angNormalize reduces the angle to the range (-180, 180].
+func angNormalize(x float64) float64 {
+	y := math.Remainder(x, 360)
+	if y == -180 {
+		return 180
+	}
+
+	return y
+}
+
+// angDiff returns the exact difference y - x in the range (-180, 180]
+// as the difference and the roundoff error.
+func angDiff(x, y float64) (float64, float64) {
+	d, t := sum(angNormalize(-x), angNormalize(y))
+	d = angNormalize(d)
+	if d == 180 && t > 0 {
+		d = -180
+	}
+
+	return sum(d, t)
+}
+
+func latFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+
+	return x
+}
+
+// sincosd returns the sine and cosine of the angle in degrees, reducing
+// the angle first so multiples of 90 are exact.
+func sincosd(x float64) (float64, float64) {
+	r := math.Mod(x, 360)
+	q := 0
+	if !math.IsNaN(r) {
+		q = int(math.RoundToEven(r / 90))
+	}
+
+	r -= 90 * float64(q)
+	s, c := math.Sincos(deg2rad(r))
+
+	switch ((q % 4) + 4) % 4 {
+	case 1:
+		s, c = c, -s
+	case 2:
+		s, c = -s, -c
+	case 3:
+		s, c = -c, s
+	}
+
+	if x == 0 {
+		return x, c
+	}
+
+	return 0 + s, 0 + c
+}
+
+// atan2d returns atan2(y, x) in degrees, exact for multiples of 45.
+func atan2d(y, x float64) float64 {
+	q := 0
+	if math.Abs(y) > math.Abs(x) {
+		q = 2
+		x, y = y, x
+	}
+
+	if x < 0 {
+		q++
+		x = -x
+	}
+
+	ang := rad2deg(math.Atan2(y, x))
+	switch q {
+	case 1:
+		if y >= 0 {
+			ang = 180 - ang
+		} else {
+			ang = -180 - ang
+		}
+	case 2:
+		ang = 90 - ang
+	case 3:
+		ang = -90 + ang
+	}
+
+	return ang
+}
+
+func norm(x, y float64) (float64, float64) {
+	r := math.Hypot(x, y)
+	return x / r, y / r
+}
@@ -0,0 +1,202 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestGeodesicInverse(t *testing.T) {
+	cases := []struct {
+		name     string
+		p1, p2   orb.Point
+		distance float64
+		forward  float64
+		back     float64
+	}{
+		{
+			name:     "wellington to salamanca",
+			p1:       orb.Point{174.81, -41.32},
+			p2:       orb.Point{-5.50, 40.96},
+			distance: 19959679.267353,
+			forward:  161.067669986160,
+			back:     18.825195123247 - 180,
+		},
+		{
+			name:     "along the equator",
+			p1:       orb.Point{0, 0},
+			p2:       orb.Point{10, 0},
+			distance: 1113194.907933,
+			forward:  90,
+			back:     -90,
+		},
+		{
+			name:     "to the pole",
+			p1:       orb.Point{0, 0},
+			p2:       orb.Point{0, 90},
+			distance: 10001965.729313,
+			forward:  0,
+			back:     180,
+		},
+		{
+			name:     "antipodal on the equator",
+			p1:       orb.Point{0, 0},
+			p2:       orb.Point{180, 0},
+			distance: 20003931.458625,
+			forward:  0,
+			back:     0,
+		},
+		{
+			name:     "nearly antipodal",
+			p1:       orb.Point{0, 0},
+			p2:       orb.Point{179.5, 0.5},
+			distance: 19936288.578965,
+			forward:  25.671872868292,
+			back:     -25.672914530058,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, f, b := GeodesicInverse(tc.p1, tc.p2)
+			if math.Abs(d-tc.distance) > epsilon {
+				t.Errorf("incorrect distance: %v != %v", d, tc.distance)
+			}
+
+			if math.Abs(f-tc.forward) > 1e-9 {
+				t.Errorf("incorrect forward azimuth: %v != %v", f, tc.forward)
+			}
+
+			if math.Abs(b-tc.back) > 1e-9 {
+				t.Errorf("incorrect back azimuth: %v != %v", b, tc.back)
+			}
+
+			if v := GeodesicDistance(tc.p1, tc.p2); v != d {
+				t.Errorf("distance should match inverse: %v != %v", v, d)
+			}
+		})
+	}
+}
+
+func TestGeodesicDirect(t *testing.T) {
+	// JFK airport to near Paris CDG
+	p := GeodesicDirect(orb.Point{-73.77888889, 40.63972222}, 53.5, 5850e3)
+
+	expected := orb.Point{2.56106226, 49.01466893}
+	if math.Abs(p[0]-expected[0]) > 1e-8 || math.Abs(p[1]-expected[1]) > 1e-8 {
+		t.Errorf("incorrect point: %v", p)
+	}
+}
+
+func TestGeodesicDirect_roundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 1000; i++ {
+		p1 := orb.Point{360*r.Float64() - 180, 180*r.Float64() - 90}
+		p2 := orb.Point{360*r.Float64() - 180, 180*r.Float64() - 90}
+
+		d, f, _ := GeodesicInverse(p1, p2)
+		p := GeodesicDirect(p1, f, d)
+
+		if v := GeodesicDistance(p, p2); v > 1e-6 {
+			t.Fatalf("%v to %v ended up %v meters away", p1, p2, v)
+		}
+	}
+}
+
+func TestGeodesicLength(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		GeodesicLength(g)
+	}
+
+	ls := orb.LineString{{0, 0}, {5, 0}, {10, 0}}
+	if v := GeodesicLength(ls); math.Abs(v-1113194.907933) > epsilon {
+		t.Errorf("incorrect length: %v", v)
+	}
+}
+
+func TestGeodesicArea(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		// should not panic with unsupported type
+		GeodesicArea(g)
+	}
+
+	shell := orb.Ring{{0, -1}, {1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+	hole := orb.Ring{{-0.5, -0.5}, {-0.5, 0.5}, {0.5, 0.5}, {0.5, -0.5}, {-0.5, -0.5}}
+
+	holeArea := GeodesicArea(hole)
+	if a := GeodesicArea(orb.Polygon{shell, hole}); math.Abs(a-(24619419146-holeArea)) > 1 {
+		t.Errorf("incorrect polygon area: %v", a)
+	}
+
+	mp := orb.MultiPolygon{{shell}, {{{10, 10}, {11, 10}, {11, 11}, {10, 10}}}}
+	expected := 24619419146 + GeodesicArea(mp[1])
+	if a := GeodesicArea(mp); math.Abs(a-expected) > 1 {
+		t.Errorf("incorrect multi polygon area: %v", a)
+	}
+}
+
+func TestGeodesicSignedArea(t *testing.T) {
+	// reference values from GeographicLib's planimeter tests
+	cases := []struct {
+		name   string
+		ring   orb.Ring
+		result float64
+	}{
+		{
+			name:   "around the north pole",
+			ring:   orb.Ring{{0, 89}, {90, 89}, {180, 89}, {270, 89}},
+			result: 24952305678.0,
+		},
+		{
+			name:   "around the south pole",
+			ring:   orb.Ring{{0, -89}, {90, -89}, {180, -89}, {270, -89}},
+			result: -24952305678.0,
+		},
+		{
+			name:   "diamond",
+			ring:   orb.Ring{{-1, 0}, {0, -1}, {1, 0}, {0, 1}, {-1, 0}},
+			result: 24619419146.0,
+		},
+		{
+			name:   "diamond clockwise",
+			ring:   orb.Ring{{-1, 0}, {0, 1}, {1, 0}, {0, -1}, {-1, 0}},
+			result: -24619419146.0,
+		},
+		{
+			name:   "octant",
+			ring:   orb.Ring{{0, 90}, {0, 0}, {90, 0}},
+			result: 63758202715511.0,
+		},
+		{
+			name:   "crosses the antimeridian",
+			ring:   orb.Ring{{0.1, 89}, {90.1, 89}, {-179.9, 89}},
+			result: 12476152838.5,
+		},
+		{
+			name:   "around the pole twice",
+			ring:   orb.Ring{{-360, 89}, {-240, 89}, {-120, 89}, {0, 89}, {120, 89}, {240, 89}},
+			result: 32415230256.0,
+		},
+		{
+			name:   "degenerate",
+			ring:   orb.Ring{{-0.00000000000001, 9}, {180, 9}, {0, 9}},
+			result: 0,
+		},
+		{
+			name:   "too few points",
+			ring:   orb.Ring{{0, 0}, {1, 1}},
+			result: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if a := GeodesicSignedArea(tc.ring); math.Abs(a-tc.result) > 1 {
+				t.Errorf("incorrect area: %v != %v", a, tc.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/linear"
+)
+
+var measure = linear.Measure{
+	Distance:    DistanceHaversine,
+	Interpolate: Interpolate,
+	Project:     projectOnSegment,
+}
+
+// LocatePoint returns the fraction of the length of the line string to
+// the point on the line string closest to p, along with that point.
+// Segments are great circle paths and lengths use the haversine formula.
+// Will panic if the line string is empty.
+func LocatePoint(ls orb.LineString, p orb.Point) (float64, orb.Point) {
+	return measure.LocatePoint(ls, p)
+}
+
+// PointAtFraction returns the point at the fraction of the length of the
+// line string along the great circle paths between the points.
+// The fraction is clamped to [0, 1]. Will panic if the line string is empty.
+func PointAtFraction(ls orb.LineString, fraction float64) orb.Point {
+	return measure.PointAtFraction(ls, fraction)
+}
+
+// Substring returns the part of the line string between the start and
+// end fractions of its length. If start is greater than end the result
+// is reversed. The fractions are clamped to [0, 1].
+func Substring(ls orb.LineString, start, end float64) orb.LineString {
+	return measure.Substring(ls, start, end)
+}
+
+// projectOnSegment returns the fraction along the great circle segment
+// of the closest point.
+func projectOnSegment(a, b, p orb.Point) float64 {
+	l := DistanceHaversine(a, b)
+	if l == 0 {
+		return 0
+	}
+
+	return math.Max(0, math.Min(1, AlongTrackDistance(a, b, p)/l))
+}
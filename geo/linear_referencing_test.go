@@ -0,0 +1,71 @@
+package geo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestLocatePoint(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+	f, p := LocatePoint(ls, orb.Point{5, 1})
+	if math.Abs(f-0.25) > 1e-9 {
+		t.Errorf("incorrect fraction: %v", f)
+	}
+
+	if math.Abs(p[0]-5) > 1e-9 || math.Abs(p[1]) > 1e-9 {
+		t.Errorf("incorrect point: %v", p)
+	}
+
+	// past the end
+	f, p = LocatePoint(ls, orb.Point{10, 20})
+	if f != 1 || p != ls[2] {
+		t.Errorf("should be the end: %v %v", f, p)
+	}
+
+	// should round trip with PointAtFraction
+	for _, fraction := range []float64{0.1, 0.3, 0.5, 0.75, 0.9} {
+		p := PointAtFraction(ls, fraction)
+		f, _ := LocatePoint(ls, p)
+		if math.Abs(f-fraction) > 1e-9 {
+			t.Errorf("incorrect fraction: %v != %v", f, fraction)
+		}
+	}
+}
+
+func TestPointAtFraction(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+	p := PointAtFraction(ls, 0.5)
+	if math.Abs(p[0]-10) > 1e-9 || math.Abs(p[1]) > 1e-9 {
+		t.Errorf("incorrect point: %v", p)
+	}
+
+	// consistent with PointAtDistanceAlongLine
+	expected, _ := PointAtDistanceAlongLine(ls, 0.6*LengthHaversine(ls))
+	p = PointAtFraction(ls, 0.6)
+	if d := DistanceHaversine(p, expected); d > 1e-6 {
+		t.Errorf("incorrect point: %v != %v", p, expected)
+	}
+}
+
+func TestSubstring(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+	result := Substring(ls, 0.25, 0.75)
+	if len(result) != 3 || result[1] != ls[1] {
+		t.Fatalf("incorrect result: %v", result)
+	}
+
+	if l := LengthHaversine(result); math.Abs(l-LengthHaversine(ls)/2) > 1e-6 {
+		t.Errorf("incorrect length: %v", l)
+	}
+
+	reversed := Substring(ls, 0.75, 0.25)
+	reversed.Reverse()
+	if !reversed.Equal(result) {
+		t.Errorf("reversed should be the same: %v", reversed)
+	}
+}
@@ -41,6 +41,14 @@ func (f *Feature) Point() orb.Point {
 
 var _ orb.Pointer = &Feature{}
 
+// Bound implements the orb.Bounder interface so that Features can be used
+// with the quadtree.Extents index. The bound of the geometry is returned.
+func (f *Feature) Bound() orb.Bound {
+	return f.Geometry.Bound()
+}
+
+var _ orb.Bounder = &Feature{}
+
 // MarshalJSON converts the feature object into the proper JSON.
 // It will handle the encoding of all the child geometries.
 // Alternately one can call json.Marshal(f) directly for the same result.
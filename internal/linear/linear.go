@@ -0,0 +1,137 @@
+// Package linear implements linear referencing, locating points along
+// line strings by their fraction of the total length, given a way to
+// measure, interpolate and project onto segments.
+package linear
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// Measure defines the geometry used along the segments of the line string.
+type Measure struct {
+	// Distance returns the length of the segment.
+	Distance orb.DistanceFunc
+
+	// Interpolate returns the point at the fraction of the way along the segment.
+	Interpolate func(a, b orb.Point, fraction float64) orb.Point
+
+	// Project returns the fraction, in [0, 1], along the segment of the
+	// point on the segment closest to p.
+	Project func(a, b, p orb.Point) float64
+}
+
+// LocatePoint returns the fraction of the length of the line string to the
+// point on the line string closest to p, along with that point.
+func (m Measure) LocatePoint(ls orb.LineString, p orb.Point) (float64, orb.Point) {
+	if len(ls) == 0 {
+		panic("empty LineString")
+	}
+
+	if len(ls) == 1 {
+		return 0, ls[0]
+	}
+
+	var (
+		total, along float64
+		projected    orb.Point
+	)
+
+	dist := math.Inf(1)
+	for i := 0; i < len(ls)-1; i++ {
+		a, b := ls[i], ls[i+1]
+		l := m.Distance(a, b)
+
+		c := a
+		if l > 0 {
+			c = m.Interpolate(a, b, m.Project(a, b, p))
+		}
+
+		if d := m.Distance(c, p); d < dist {
+			dist = d
+			projected = c
+			along = total + m.Distance(a, c)
+		}
+
+		total += l
+	}
+
+	if total == 0 {
+		return 0, projected
+	}
+
+	return math.Min(along/total, 1), projected
+}
+
+// PointAtFraction returns the point at the fraction of the length
+// of the line string. The fraction is clamped to [0, 1].
+func (m Measure) PointAtFraction(ls orb.LineString, fraction float64) orb.Point {
+	if len(ls) == 0 {
+		panic("empty LineString")
+	}
+
+	p, _ := m.pointAt(ls, fraction*m.length(ls))
+	return p
+}
+
+// Substring returns the part of the line string between the fractions
+// of its length. If start is greater than end the result is reversed.
+// The fractions are clamped to [0, 1].
+func (m Measure) Substring(ls orb.LineString, start, end float64) orb.LineString {
+	if len(ls) == 0 {
+		return orb.LineString{}
+	}
+
+	if start > end {
+		result := m.Substring(ls, end, start)
+		result.Reverse()
+		return result
+	}
+
+	total := m.length(ls)
+	startPoint, i := m.pointAt(ls, start*total)
+	endPoint, j := m.pointAt(ls, end*total)
+
+	result := orb.LineString{startPoint}
+	for k := i + 1; k <= j; k++ {
+		if ls[k] != result[len(result)-1] {
+			result = append(result, ls[k])
+		}
+	}
+
+	if endPoint != result[len(result)-1] || len(result) == 1 {
+		result = append(result, endPoint)
+	}
+
+	return result
+}
+
+// pointAt returns the point at the distance along the line string
+// and the index of the segment it's on.
+func (m Measure) pointAt(ls orb.LineString, distance float64) (orb.Point, int) {
+	if distance <= 0 || len(ls) == 1 {
+		return ls[0], 0
+	}
+
+	travelled := 0.0
+	for i := 0; i < len(ls)-1; i++ {
+		l := m.Distance(ls[i], ls[i+1])
+		if travelled+l >= distance && l > 0 {
+			return m.Interpolate(ls[i], ls[i+1], (distance-travelled)/l), i
+		}
+
+		travelled += l
+	}
+
+	return ls[len(ls)-1], len(ls) - 2
+}
+
+func (m Measure) length(ls orb.LineString) float64 {
+	sum := 0.0
+	for i := 0; i < len(ls)-1; i++ {
+		sum += m.Distance(ls[i], ls[i+1])
+	}
+
+	return sum
+}
@@ -0,0 +1,163 @@
+// Package segment implements the line segment primitives shared by the
+// planar, clip and validate packages, so they all agree on when points
+// are on a line and where segments meet.
+package segment
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// snapTolerance is the distance, relative to the magnitude of the coordinates,
+// that points are considered to be on a line.
+const snapTolerance = 1e-12
+
+// Orient returns the cross product of (b-a) and (c-a). It is positive if
+// c is to the left of the directed line a->b, negative if to the right
+// and zero if the points are collinear.
+func Orient(a, b, c orb.Point) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+// Side is Orient but returns zero if c is within roundoff of the line
+// through a and b. This helps with points that should be on a line but
+// are slightly off because they were computed.
+func Side(a, b, c orb.Point) float64 {
+	d := Orient(a, b, c)
+	if d == 0 {
+		return 0
+	}
+
+	scale := 0.0
+	for _, p := range [3]orb.Point{a, b, c} {
+		scale = math.Max(scale, math.Max(math.Abs(p[0]), math.Abs(p[1])))
+	}
+
+	// d is the distance from the line times the length of the segment.
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	if math.Abs(d) <= snapTolerance*scale*math.Sqrt(dx*dx+dy*dy) {
+		return 0
+	}
+
+	return d
+}
+
+// OnSegment checks if p is on the segment [a, b], within roundoff.
+func OnSegment(a, b, p orb.Point) bool {
+	if p == a || p == b {
+		return true
+	}
+
+	return a != b && Side(a, b, p) == 0 && onCollinear(a, b, p)
+}
+
+// Intersection returns the points where the segments [a1, a2] and
+// [b1, b2] touch. The last value is the number of points found: 0 if they
+// don't touch, 1 if they cross or touch at a point, 2 if the segments are
+// collinear and overlap. Endpoints are returned exactly when they are
+// the intersection.
+func Intersection(a1, a2, b1, b2 orb.Point) (orb.Point, orb.Point, int) {
+	d1 := Side(b1, b2, a1)
+	d2 := Side(b1, b2, a2)
+	d3 := Side(a1, a2, b1)
+	d4 := Side(a1, a2, b2)
+
+	if d1 == 0 && d2 == 0 {
+		return collinearIntersection(a1, a2, b1, b2)
+	}
+
+	if (d1 > 0 && d2 > 0) || (d1 < 0 && d2 < 0) ||
+		(d3 > 0 && d4 > 0) || (d3 < 0 && d4 < 0) {
+		return orb.Point{}, orb.Point{}, 0
+	}
+
+	switch {
+	case d1 == 0:
+		return a1, orb.Point{}, 1
+	case d2 == 0:
+		return a2, orb.Point{}, 1
+	case d3 == 0:
+		return b1, orb.Point{}, 1
+	case d4 == 0:
+		return b2, orb.Point{}, 1
+	}
+
+	t := d1 / (d1 - d2)
+	p := orb.Point{
+		a1[0] + t*(a2[0]-a1[0]),
+		a1[1] + t*(a2[1]-a1[1]),
+	}
+
+	return p, orb.Point{}, 1
+}
+
+func collinearIntersection(a1, a2, b1, b2 orb.Point) (orb.Point, orb.Point, int) {
+	var found [2]orb.Point
+	n := 0
+
+	for _, p := range [4]orb.Point{a1, a2, b1, b2} {
+		if !onCollinear(a1, a2, p) || !onCollinear(b1, b2, p) {
+			continue
+		}
+
+		if n == 1 && found[0] == p {
+			continue
+		}
+
+		found[n] = p
+		n++
+		if n == 2 {
+			break
+		}
+	}
+
+	return found[0], found[1], n
+}
+
+// onCollinear checks if p, known to be on the line through a and b,
+// is within the segment.
+func onCollinear(a, b, p orb.Point) bool {
+	if p == a || p == b {
+		return true
+	}
+
+	dx := b[0] - a[0]
+	dy := b[1] - a[1]
+	t := (p[0]-a[0])*dx + (p[1]-a[1])*dy
+
+	return 0 <= t && t <= dx*dx+dy*dy
+}
+
+// Sweep calls the function for every pair of bounds that intersect, with i < j.
+// The bounds are sorted by min x and scanned left to right, so only
+// bounds that overlap in x are compared.
+func Sweep(bounds []orb.Bound, f func(i, j int)) {
+	order := make([]int, len(bounds))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		return bounds[order[i]].Min[0] < bounds[order[j]].Min[0]
+	})
+
+	for k, i := range order {
+		for _, j := range order[k+1:] {
+			if bounds[j].Min[0] > bounds[i].Max[0] {
+				break
+			}
+
+			if !bounds[i].Intersects(bounds[j]) {
+				continue
+			}
+
+			if i < j {
+				f(i, j)
+			} else {
+				f(j, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package segment
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestIntersection(t *testing.T) {
+	cases := []struct {
+		name     string
+		a1, a2   orb.Point
+		b1, b2   orb.Point
+		count    int
+		expected []orb.Point
+	}{
+		{
+			name: "cross",
+			a1:   orb.Point{0, 0}, a2: orb.Point{2, 2},
+			b1: orb.Point{0, 2}, b2: orb.Point{2, 0},
+			count:    1,
+			expected: []orb.Point{{1, 1}},
+		},
+		{
+			name: "touch at end",
+			a1:   orb.Point{0, 0}, a2: orb.Point{1, 1},
+			b1: orb.Point{0, 2}, b2: orb.Point{2, 0},
+			count:    1,
+			expected: []orb.Point{{1, 1}},
+		},
+		{
+			name: "parallel",
+			a1:   orb.Point{0, 0}, a2: orb.Point{1, 0},
+			b1: orb.Point{0, 1}, b2: orb.Point{1, 1},
+			count: 0,
+		},
+		{
+			name: "collinear overlap",
+			a1:   orb.Point{0, 0}, a2: orb.Point{2, 0},
+			b1: orb.Point{3, 0}, b2: orb.Point{1, 0},
+			count:    2,
+			expected: []orb.Point{{2, 0}, {1, 0}},
+		},
+		{
+			name: "collinear disjoint",
+			a1:   orb.Point{0, 0}, a2: orb.Point{1, 0},
+			b1: orb.Point{2, 0}, b2: orb.Point{3, 0},
+			count: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p1, p2, n := Intersection(tc.a1, tc.a2, tc.b1, tc.b2)
+			if n != tc.count {
+				t.Fatalf("incorrect count: %d != %d", n, tc.count)
+			}
+
+			found := []orb.Point{p1, p2}[:n]
+			for i := range found {
+				if !found[i].Equal(tc.expected[i]) {
+					t.Errorf("incorrect point: %v != %v", found[i], tc.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIntersection_roundoff(t *testing.T) {
+	// the computed crossing of two segments is slightly off both of them
+	a1, a2 := orb.Point{0.1, 0.3}, orb.Point{10.7, 3.9}
+	b1, b2 := orb.Point{0.2, 5.3}, orb.Point{9.1, -2.2}
+
+	p, _, n := Intersection(a1, a2, b1, b2)
+	if n != 1 {
+		t.Fatalf("should intersect: %v", n)
+	}
+
+	if !OnSegment(a1, a2, p) || !OnSegment(b1, b2, p) {
+		t.Errorf("computed point should be on both segments: %v", p)
+	}
+
+	// and splitting at it should still touch the other segment
+	if _, _, n := Intersection(a1, p, b1, b2); n != 1 {
+		t.Errorf("split segment should touch: %v", n)
+	}
+}
+
+func TestOnSegment(t *testing.T) {
+	cases := []struct {
+		name     string
+		a, b, p  orb.Point
+		expected bool
+	}{
+		{name: "endpoint", a: orb.Point{0, 0}, b: orb.Point{2, 2}, p: orb.Point{2, 2}, expected: true},
+		{name: "middle", a: orb.Point{0, 0}, b: orb.Point{2, 2}, p: orb.Point{1, 1}, expected: true},
+		{name: "beyond", a: orb.Point{0, 0}, b: orb.Point{2, 2}, p: orb.Point{3, 3}, expected: false},
+		{name: "off line", a: orb.Point{0, 0}, b: orb.Point{2, 2}, p: orb.Point{1, 1.1}, expected: false},
+		{name: "zero length", a: orb.Point{1, 1}, b: orb.Point{1, 1}, p: orb.Point{1, 2}, expected: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := OnSegment(tc.a, tc.b, tc.p); v != tc.expected {
+				t.Errorf("incorrect result: %v != %v", v, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSweep(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	bounds := make([]orb.Bound, 500)
+	for i := range bounds {
+		p := orb.Point{r.Float64(), r.Float64()}
+		bounds[i] = orb.MultiPoint{p, {p[0] + 0.05*r.Float64(), p[1] + 0.05*r.Float64()}}.Bound()
+	}
+
+	expected := map[[2]int]bool{}
+	for i := range bounds {
+		for j := i + 1; j < len(bounds); j++ {
+			if bounds[i].Intersects(bounds[j]) {
+				expected[[2]int{i, j}] = true
+			}
+		}
+	}
+
+	actual := map[[2]int]bool{}
+	Sweep(bounds, func(i, j int) {
+		if i >= j {
+			t.Errorf("should have i < j: %d %d", i, j)
+		}
+
+		if actual[[2]int{i, j}] {
+			t.Errorf("pair called twice: %d %d", i, j)
+		}
+		actual[[2]int{i, j}] = true
+	})
+
+	if len(expected) == 0 {
+		t.Fatalf("should have some intersecting bounds")
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("incorrect pairs: %d != %d", len(actual), len(expected))
+	}
+}
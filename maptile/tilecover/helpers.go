@@ -5,15 +5,41 @@ import (
 	"fmt"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
 	"github.com/paulmach/orb/maptile"
 )
 
 // Geometry returns the covering set of tiles for the given geometry.
+// Geometries that cross the antimeridian are split first using
+// geo.SplitAntimeridian, edges are assumed to go the shorter way
+// around the earth.
 func Geometry(g orb.Geometry, z maptile.Zoom) (maptile.Set, error) {
 	if g == nil {
 		return nil, nil
 	}
 
+	if b, ok := g.(orb.Bound); ok {
+		return Bound(b, z), nil
+	}
+
+	set, err := geometry(geo.SplitAntimeridian(g), z)
+	if err != nil {
+		return nil, err
+	}
+
+	// +180, where the split parts end, is the start of the next world
+	maxX := uint32(1)<<z - 1
+	for t := range set {
+		if t.X > maxX {
+			delete(set, t)
+			set[maptile.Tile{X: maxX, Y: t.Y, Z: t.Z}] = true
+		}
+	}
+
+	return set, nil
+}
+
+func geometry(g orb.Geometry, z maptile.Zoom) (maptile.Set, error) {
 	switch g := g.(type) {
 	case orb.Point:
 		return Point(g, z), nil
@@ -57,20 +83,41 @@ func MultiPoint(mp orb.MultiPoint, z maptile.Zoom) maptile.Set {
 }
 
 // Bound creates a tile cover for the bound. i.e. all the tiles
-// that intersect the bound.
+// that intersect the bound. A bound with Min.Lon > Max.Lon, such as from
+// geo.Bound, is assumed to cross the antimeridian and the tiles on both
+// sides are returned.
 func Bound(b orb.Bound, z maptile.Zoom) maptile.Set {
+	if b.Min[0] > b.Max[0] && b.Min[1] <= b.Max[1] {
+		result := make(maptile.Set)
+		addBound(result, b.Min, orb.Point{180, b.Max[1]}, z)
+		addBound(result, orb.Point{-180, b.Min[1]}, b.Max, z)
+
+		return result
+	}
+
 	lo := maptile.At(b.Min, z)
 	hi := maptile.At(b.Max, z)
 
 	result := make(maptile.Set, (hi.X-lo.X+1)*(lo.Y-hi.Y+1))
+	addBound(result, b.Min, b.Max, z)
+
+	return result
+}
+
+func addBound(set maptile.Set, min, max orb.Point, z maptile.Zoom) {
+	lo := maptile.At(min, z)
+	hi := maptile.At(max, z)
+
+	// +180 is the start of the next world
+	if maxX := uint32(1)<<z - 1; hi.X > maxX {
+		hi.X = maxX
+	}
 
 	for x := lo.X; x <= hi.X; x++ {
 		for y := hi.Y; y <= lo.Y; y++ {
-			result[maptile.Tile{X: x, Y: y, Z: z}] = true
+			set[maptile.Tile{X: x, Y: y, Z: z}] = true
 		}
 	}
-
-	return result
 }
 
 // Collection returns the covering set of tiles for the
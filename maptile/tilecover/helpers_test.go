@@ -1,9 +1,11 @@
 package tilecover
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/maptile"
 )
 
 func TestGeometry(t *testing.T) {
@@ -14,3 +16,68 @@ func TestGeometry(t *testing.T) {
 		}
 	}
 }
+
+func TestGeometry_antimeridian(t *testing.T) {
+	cases := []struct {
+		name  string
+		input orb.Geometry
+	}{
+		{
+			name:  "line string",
+			input: orb.LineString{{170, 1}, {-170, 1}},
+		},
+		{
+			name:  "polygon",
+			input: orb.Polygon{{{170, 1}, {-170, 1}, {-170, 2}, {170, 2}, {170, 1}}},
+		},
+		{
+			name:  "past 180",
+			input: orb.Polygon{{{170, 1}, {190, 1}, {190, 2}, {170, 2}, {170, 1}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tiles, err := Geometry(tc.input, 2)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected := maptile.Set{
+				maptile.New(0, 1, 2): true,
+				maptile.New(3, 1, 2): true,
+			}
+			if !reflect.DeepEqual(tiles, expected) {
+				t.Errorf("incorrect tiles: %v", tiles)
+			}
+		})
+	}
+}
+
+func TestBound(t *testing.T) {
+	b := orb.Bound{Min: orb.Point{-10, -10}, Max: orb.Point{10, 10}}
+	if l := len(Bound(b, 2)); l != 4 {
+		t.Errorf("incorrect number of tiles: %v", l)
+	}
+
+	// crosses the antimeridian
+	b = orb.Bound{Min: orb.Point{170, -10}, Max: orb.Point{-170, 10}}
+	tiles := Bound(b, 2)
+	if l := len(tiles); l != 4 {
+		t.Errorf("incorrect number of tiles: %v", l)
+	}
+
+	for tile := range tiles {
+		if tile.X != 0 && tile.X != 3 {
+			t.Errorf("incorrect tile: %v", tile)
+		}
+	}
+
+	// up to 180
+	b = orb.Bound{Min: orb.Point{170, -10}, Max: orb.Point{180, 10}}
+	for tile := range Bound(b, 2) {
+		if !tile.Valid() {
+			t.Errorf("invalid tile: %v", tile)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 )
 
 // LineString creates a tile cover for the line string.
+// The line must not cross the antimeridian, see Geometry.
 func LineString(ls orb.LineString, z maptile.Zoom) maptile.Set {
 	set := make(maptile.Set)
 	line(set, ls, z, nil)
@@ -16,6 +17,7 @@ func LineString(ls orb.LineString, z maptile.Zoom) maptile.Set {
 }
 
 // MultiLineString creates a tile cover for the line strings.
+// The lines must not cross the antimeridian, see Geometry.
 func MultiLineString(mls orb.MultiLineString, z maptile.Zoom) maptile.Set {
 	set := make(maptile.Set)
 	for _, ls := range mls {
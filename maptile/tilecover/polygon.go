@@ -13,6 +13,7 @@ import (
 var ErrUnevenIntersections = errors.New("tilecover: uneven intersections, ring not closed?")
 
 // Ring creates a tile cover for the ring.
+// The ring must not cross the antimeridian, see Geometry.
 func Ring(r orb.Ring, z maptile.Zoom) (maptile.Set, error) {
 	if len(r) == 0 {
 		return make(maptile.Set), nil
@@ -22,6 +23,7 @@ func Ring(r orb.Ring, z maptile.Zoom) (maptile.Set, error) {
 }
 
 // Polygon creates a tile cover for the polygon.
+// The polygon must not cross the antimeridian, see Geometry.
 func Polygon(p orb.Polygon, z maptile.Zoom) (maptile.Set, error) {
 	set := make(maptile.Set)
 
@@ -34,6 +36,7 @@ func Polygon(p orb.Polygon, z maptile.Zoom) (maptile.Set, error) {
 }
 
 // MultiPolygon creates a tile cover for the multi-polygon.
+// The polygons must not cross the antimeridian, see Geometry.
 func MultiPolygon(mp orb.MultiPolygon, z maptile.Zoom) (maptile.Set, error) {
 	set := make(maptile.Set)
 	for _, p := range mp {
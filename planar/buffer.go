@@ -0,0 +1,404 @@
+package planar
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/segment"
+)
+
+// CapStyle defines how the ends of lines are buffered.
+type CapStyle int
+
+// Possible cap styles.
+const (
+	// CapRound ends the buffer with a half circle.
+	CapRound CapStyle = iota
+
+	// CapFlat ends the buffer exactly at the end of the line.
+	CapFlat
+
+	// CapSquare ends the buffer with half a square centered at the end of the line.
+	CapSquare
+)
+
+// JoinStyle defines how corners are buffered.
+type JoinStyle int
+
+// Possible join styles.
+const (
+	// JoinRound rounds the outside of the corner.
+	JoinRound JoinStyle = iota
+
+	// JoinMitre extends the offset lines until they meet, limited by the mitre limit.
+	JoinMitre
+
+	// JoinBevel cuts off the outside of the corner with a straight line.
+	JoinBevel
+)
+
+type bufferOptions struct {
+	quadrantSegments int
+	cap              CapStyle
+	join             JoinStyle
+	mitreLimit       float64
+}
+
+// A BufferOption is a possible parameter to the buffer operation.
+type BufferOption func(*bufferOptions)
+
+// QuadrantSegments sets the number of segments used to approximate
+// a quarter circle. The default is 8.
+func QuadrantSegments(n int) BufferOption {
+	return func(o *bufferOptions) {
+		if n < 1 {
+			n = 1
+		}
+		o.quadrantSegments = n
+	}
+}
+
+// EndCap sets the style used at the ends of lines. The default is CapRound.
+func EndCap(c CapStyle) BufferOption {
+	return func(o *bufferOptions) {
+		o.cap = c
+	}
+}
+
+// Join sets the style used at the corners. The default is JoinRound.
+func Join(j JoinStyle) BufferOption {
+	return func(o *bufferOptions) {
+		o.join = j
+	}
+}
+
+// MitreLimit sets the max ratio of the mitre length to the buffer distance
+// before a mitre join is beveled. The default is 5.
+func MitreLimit(l float64) BufferOption {
+	return func(o *bufferOptions) {
+		o.mitreLimit = l
+	}
+}
+
+// Buffer returns the area within the distance of the geometry. A negative
+// distance will shrink, or erode, the 2d parts of the geometry. 0d and 1d
+// geometries have no area so they return nil for distances <= 0.
+// The distance is in the units of the geometry, so lon/lat data should
+// be projected first.
+func Buffer(g orb.Geometry, distance float64, opts ...BufferOption) orb.MultiPolygon {
+	o := &bufferOptions{
+		quadrantSegments: 8,
+		cap:              CapRound,
+		join:             JoinRound,
+		mitreLimit:       5,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	b := &bufferer{
+		distance: math.Abs(distance),
+		opts:     o,
+	}
+
+	if distance == 0 {
+		return normalizeMultiPolygon(toMultiPolygon(g))
+	}
+
+	if distance < 0 {
+		areas := normalizeMultiPolygon(toMultiPolygon(g))
+		if len(areas) == 0 {
+			return nil
+		}
+
+		for _, p := range areas {
+			for _, r := range p {
+				b.closedLine(orb.LineString(r))
+			}
+		}
+
+		return overlayNormalized(areas, b.union(), opDifference)
+	}
+
+	b.geometry(g)
+	return b.union()
+}
+
+// bufferer collects the pieces whose union is the buffer.
+type bufferer struct {
+	distance float64
+	opts     *bufferOptions
+	pieces   []orb.MultiPolygon
+}
+
+func (b *bufferer) geometry(g orb.Geometry) {
+	if g == nil {
+		return
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		b.point(g)
+	case orb.MultiPoint:
+		for _, p := range g {
+			b.point(p)
+		}
+	case orb.LineString:
+		b.line(g)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			b.line(ls)
+		}
+	case orb.Ring, orb.Polygon, orb.MultiPolygon, orb.Bound:
+		areas := normalizeMultiPolygon(toMultiPolygon(g))
+		for _, p := range areas {
+			b.pieces = append(b.pieces, orb.MultiPolygon{p})
+			for _, r := range p {
+				b.closedLine(orb.LineString(r))
+			}
+		}
+	case orb.Collection:
+		for _, c := range g {
+			b.geometry(c)
+		}
+	default:
+		panic(fmt.Sprintf("geometry type not supported: %T", g))
+	}
+}
+
+func (b *bufferer) add(r orb.Ring) {
+	if r = normalizeRing(r, orb.CCW); r != nil {
+		b.pieces = append(b.pieces, orb.MultiPolygon{{r}})
+	}
+}
+
+func (b *bufferer) point(p orb.Point) {
+	switch b.opts.cap {
+	case CapRound:
+		r := orb.Ring(b.arc(p, 0, 2*math.Pi))
+		b.add(append(r, r[0]))
+	case CapSquare:
+		b.add(orb.Bound{
+			Min: orb.Point{p[0] - b.distance, p[1] - b.distance},
+			Max: orb.Point{p[0] + b.distance, p[1] + b.distance},
+		}.ToRing())
+	}
+}
+
+func (b *bufferer) line(ls orb.LineString) {
+	ls = removeRepeated(ls)
+	if len(ls) == 0 {
+		return
+	}
+
+	if len(ls) == 1 {
+		b.point(ls[0])
+		return
+	}
+
+	for i := 0; i < len(ls)-1; i++ {
+		b.segment(ls[i], ls[i+1])
+	}
+
+	for i := 1; i < len(ls)-1; i++ {
+		b.join(ls[i-1], ls[i], ls[i+1])
+	}
+
+	b.cap(ls[1], ls[0])
+	b.cap(ls[len(ls)-2], ls[len(ls)-1])
+}
+
+// closedLine buffers the line assuming the last point connects
+// back to the first, so there are joins at every point and no caps.
+func (b *bufferer) closedLine(ls orb.LineString) {
+	ls = removeRepeated(ls)
+	if len(ls) > 1 && ls[0] == ls[len(ls)-1] {
+		ls = ls[:len(ls)-1]
+	}
+
+	if len(ls) < 3 {
+		b.line(ls)
+		return
+	}
+
+	n := len(ls)
+	for i := range ls {
+		b.segment(ls[i], ls[(i+1)%n])
+		b.join(ls[(i+n-1)%n], ls[i], ls[(i+1)%n])
+	}
+}
+
+// segment adds the rectangle around the segment. The segment end points
+// are included so the edges match the join and cap pieces exactly.
+func (b *bufferer) segment(p1, p2 orb.Point) {
+	n := b.normal(p1, p2)
+	b.add(orb.Ring{
+		{p1[0] + n[0], p1[1] + n[1]},
+		p1,
+		{p1[0] - n[0], p1[1] - n[1]},
+		{p2[0] - n[0], p2[1] - n[1]},
+		p2,
+		{p2[0] + n[0], p2[1] + n[1]},
+		{p1[0] + n[0], p1[1] + n[1]},
+	})
+}
+
+// join adds the piece on the outside of the corner at p2.
+// The inside of the corner is covered by the segments.
+func (b *bufferer) join(p1, p2, p3 orb.Point) {
+	turn := segment.Orient(p1, p2, p3)
+
+	n1 := b.normal(p1, p2)
+	n2 := b.normal(p2, p3)
+	if turn == 0 {
+		if n1[0]*n2[0]+n1[1]*n2[1] > 0 {
+			// straight line
+			return
+		}
+
+		// the line doubles back on itself
+		if b.opts.join == JoinRound {
+			start := math.Atan2(n1[1], n1[0])
+			r := append(orb.Ring{p2}, b.arc(p2, start, -math.Pi)...)
+			b.add(append(r, p2))
+		}
+		return
+	}
+
+	if turn > 0 {
+		// left turn, outside of corner is on the right
+		n1 = orb.Point{-n1[0], -n1[1]}
+		n2 = orb.Point{-n2[0], -n2[1]}
+	}
+
+	o1 := orb.Point{p2[0] + n1[0], p2[1] + n1[1]}
+	o2 := orb.Point{p2[0] + n2[0], p2[1] + n2[1]}
+
+	switch b.opts.join {
+	case JoinRound:
+		start := math.Atan2(n1[1], n1[0])
+		sweep := math.Atan2(n2[1], n2[0]) - start
+		if sweep > math.Pi {
+			sweep -= 2 * math.Pi
+		} else if sweep < -math.Pi {
+			sweep += 2 * math.Pi
+		}
+
+		arc := b.arc(p2, start, sweep)
+		arc[0], arc[len(arc)-1] = o1, o2
+
+		r := append(orb.Ring{p2}, arc...)
+		b.add(append(r, p2))
+	case JoinMitre:
+		d2 := b.distance * b.distance
+		dot := (n1[0]*n2[0] + n1[1]*n2[1]) / d2
+		sum := orb.Point{(n1[0] + n2[0]) / (1 + dot), (n1[1] + n2[1]) / (1 + dot)}
+
+		if math.Sqrt(sum[0]*sum[0]+sum[1]*sum[1]) <= b.opts.mitreLimit*b.distance {
+			b.add(orb.Ring{p2, o1, {p2[0] + sum[0], p2[1] + sum[1]}, o2, p2})
+			return
+		}
+
+		b.add(orb.Ring{p2, o1, o2, p2})
+	case JoinBevel:
+		b.add(orb.Ring{p2, o1, o2, p2})
+	}
+}
+
+// cap adds the end piece at p2 for the segment from p1.
+func (b *bufferer) cap(p1, p2 orb.Point) {
+	n := b.normal(p1, p2)
+
+	switch b.opts.cap {
+	case CapRound:
+		arc := b.arc(p2, math.Atan2(n[1], n[0]), -math.Pi)
+		arc[0] = orb.Point{p2[0] + n[0], p2[1] + n[1]}
+		arc[len(arc)-1] = orb.Point{p2[0] - n[0], p2[1] - n[1]}
+
+		r := append(orb.Ring{p2}, arc...)
+		b.add(append(r, p2))
+	case CapSquare:
+		// direction is the normal rotated clockwise
+		d := orb.Point{n[1], -n[0]}
+		b.add(orb.Ring{
+			{p2[0] + n[0], p2[1] + n[1]},
+			{p2[0] + n[0] + d[0], p2[1] + n[1] + d[1]},
+			{p2[0] - n[0] + d[0], p2[1] - n[1] + d[1]},
+			{p2[0] - n[0], p2[1] - n[1]},
+			{p2[0] + n[0], p2[1] + n[1]},
+		})
+	}
+}
+
+// normal returns the vector to the left of the segment with length of the distance.
+func (b *bufferer) normal(p1, p2 orb.Point) orb.Point {
+	dx := p2[0] - p1[0]
+	dy := p2[1] - p1[1]
+	l := math.Sqrt(dx*dx + dy*dy)
+
+	return orb.Point{-dy / l * b.distance, dx / l * b.distance}
+}
+
+// arc returns the points on the circle around the center from the start angle
+// sweeping the given radians, positive is counter-clockwise.
+func (b *bufferer) arc(center orb.Point, start, sweep float64) []orb.Point {
+	n := int(math.Ceil(math.Abs(sweep) / (math.Pi / 2) * float64(b.opts.quadrantSegments)))
+	if n < 1 {
+		n = 1
+	}
+
+	full := math.Abs(sweep) >= 2*math.Pi
+	if full {
+		n--
+	}
+
+	result := make([]orb.Point, 0, n+1)
+	for i := 0; i <= n; i++ {
+		a := start + sweep*float64(i)/float64(n)
+		if full {
+			a = start + sweep*float64(i)/float64(n+1)
+		}
+
+		result = append(result, orb.Point{
+			center[0] + b.distance*math.Cos(a),
+			center[1] + b.distance*math.Sin(a),
+		})
+	}
+
+	return result
+}
+
+// union merges the pieces, pairwise, so the overlays stay small.
+func (b *bufferer) union() orb.MultiPolygon {
+	pieces := b.pieces
+	if len(pieces) == 0 {
+		return nil
+	}
+
+	for len(pieces) > 1 {
+		merged := pieces[:0]
+		for i := 0; i < len(pieces); i += 2 {
+			if i+1 == len(pieces) {
+				merged = append(merged, pieces[i])
+				continue
+			}
+
+			merged = append(merged, overlayNormalized(pieces[i], pieces[i+1], opUnion))
+		}
+		pieces = merged
+	}
+
+	return pieces[0]
+}
+
+func removeRepeated(ls orb.LineString) orb.LineString {
+	result := make(orb.LineString, 0, len(ls))
+	for _, p := range ls {
+		if len(result) == 0 || result[len(result)-1] != p {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
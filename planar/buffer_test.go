@@ -0,0 +1,215 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestBuffer(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		Buffer(g, 1)
+		Buffer(g, -1)
+	}
+}
+
+func TestBuffer_point(t *testing.T) {
+	circle := 0.5 * 32 * math.Sin(2*math.Pi/32)
+
+	cases := []struct {
+		name string
+		g    orb.Geometry
+		opts []BufferOption
+		area float64
+	}{
+		{
+			name: "round",
+			g:    orb.Point{1, 2},
+			area: circle,
+		},
+		{
+			name: "square",
+			g:    orb.Point{1, 2},
+			opts: []BufferOption{EndCap(CapSquare)},
+			area: 4,
+		},
+		{
+			name: "flat",
+			g:    orb.Point{1, 2},
+			opts: []BufferOption{EndCap(CapFlat)},
+			area: 0,
+		},
+		{
+			name: "multi point overlapping",
+			g:    orb.MultiPoint{{0, 0}, {1, 0}},
+			opts: []BufferOption{EndCap(CapSquare)},
+			area: 6,
+		},
+		{
+			name: "quadrant segments",
+			g:    orb.Point{1, 2},
+			opts: []BufferOption{QuadrantSegments(1)},
+			area: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Buffer(tc.g, 1, tc.opts...)
+			if a := Area(result); math.Abs(a-tc.area) > 1e-10 {
+				t.Errorf("incorrect area: %v != %v", a, tc.area)
+			}
+			expectValidOrientation(t, result)
+		})
+	}
+}
+
+func TestBuffer_lineString(t *testing.T) {
+	circle := 0.5 * 32 * math.Sin(2*math.Pi/32)
+	ls := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+
+	// the two segment rectangles overlap by 1x1 at the inside of the corner
+
+	cases := []struct {
+		name string
+		opts []BufferOption
+		area float64
+	}{
+		{
+			name: "flat mitre",
+			opts: []BufferOption{EndCap(CapFlat), Join(JoinMitre)},
+			area: 2*20 - 1 + 1,
+		},
+		{
+			name: "flat bevel",
+			opts: []BufferOption{EndCap(CapFlat), Join(JoinBevel)},
+			area: 2*20 - 1 + 0.5,
+		},
+		{
+			name: "flat round",
+			opts: []BufferOption{EndCap(CapFlat)},
+			area: 2*20 - 1 + circle/4,
+		},
+		{
+			name: "square mitre",
+			opts: []BufferOption{EndCap(CapSquare), Join(JoinMitre)},
+			area: 2*22 - 1 + 1,
+		},
+		{
+			name: "round round",
+			area: 2*20 - 1 + circle/4 + circle,
+		},
+		{
+			name: "mitre limit",
+			opts: []BufferOption{EndCap(CapFlat), Join(JoinMitre), MitreLimit(1)},
+			area: 2*20 - 1 + 0.5,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Buffer(ls, 1, tc.opts...)
+			if len(result) != 1 || len(result[0]) != 1 {
+				t.Fatalf("should be a single polygon: %v", result)
+			}
+
+			if a := Area(result); math.Abs(a-tc.area) > 1e-10 {
+				t.Errorf("incorrect area: %v != %v", a, tc.area)
+			}
+			expectValidOrientation(t, result)
+		})
+	}
+
+	if result := Buffer(ls, -1); result != nil {
+		t.Errorf("negative buffer of line should be nil: %v", result)
+	}
+}
+
+func TestBuffer_polygon(t *testing.T) {
+	square := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	donut := orb.Polygon{square[0], {{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}}}
+
+	cases := []struct {
+		name     string
+		g        orb.Geometry
+		distance float64
+		opts     []BufferOption
+		area     float64
+		rings    int
+	}{
+		{
+			name:     "grow mitre",
+			g:        square,
+			distance: 1,
+			opts:     []BufferOption{Join(JoinMitre)},
+			area:     144,
+			rings:    1,
+		},
+		{
+			name:     "grow bevel",
+			g:        square,
+			distance: 1,
+			opts:     []BufferOption{Join(JoinBevel)},
+			area:     142,
+			rings:    1,
+		},
+		{
+			name:     "shrink",
+			g:        square,
+			distance: -1,
+			area:     64,
+			rings:    1,
+		},
+		{
+			name:     "grow donut",
+			g:        donut,
+			distance: 1,
+			opts:     []BufferOption{Join(JoinMitre)},
+			area:     144 - 16,
+			rings:    2,
+		},
+		{
+			name:     "shrink donut",
+			g:        donut,
+			distance: -1,
+			opts:     []BufferOption{Join(JoinMitre)},
+			area:     64 - 64,
+			rings:    0,
+		},
+		{
+			name:     "shrink donut a bit",
+			g:        donut,
+			distance: -0.5,
+			opts:     []BufferOption{Join(JoinMitre)},
+			area:     81 - 49,
+			rings:    2,
+		},
+		{
+			name:     "zero distance",
+			g:        square,
+			distance: 0,
+			area:     100,
+			rings:    1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Buffer(tc.g, tc.distance, tc.opts...)
+
+			rings := 0
+			for _, p := range result {
+				rings += len(p)
+			}
+			if rings != tc.rings {
+				t.Errorf("incorrect number of rings: %d != %d", rings, tc.rings)
+			}
+
+			if a := Area(result); math.Abs(a-tc.area) > 1e-10 {
+				t.Errorf("incorrect area: %v != %v", a, tc.area)
+			}
+			expectValidOrientation(t, result)
+		})
+	}
+}
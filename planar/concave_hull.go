@@ -0,0 +1,189 @@
+package planar
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// ConcaveHull returns a possibly non-convex polygon that contains all the
+// points of the geometry. The ratio, from 0 to 1, controls how concave the
+// result is. It defines the max edge length as a fraction of the difference
+// between the longest and shortest edges of the Delaunay triangulation of the
+// points. A ratio of 1 is the convex hull. Returns nil if the points do not
+// form an area, ie. there are fewer than 3 unique points or they are collinear.
+func ConcaveHull(g orb.Geometry, ratio float64) orb.Polygon {
+	d := triangulate(g)
+	if d == nil {
+		return nil
+	}
+
+	min, max := math.Inf(1), 0.0
+	for e, o := range d.halfedges {
+		if o > e {
+			// interior edges are visited from both sides
+			continue
+		}
+
+		l := Distance(d.points[d.triangles[e]], d.points[d.triangles[nextHalfedge(e)]])
+		min = math.Min(min, l)
+		max = math.Max(max, l)
+	}
+
+	ratio = math.Max(0, math.Min(1, ratio))
+	return concaveHull(d, min+ratio*(max-min))
+}
+
+// ConcaveHullByLength returns a possibly non-convex polygon that contains all
+// the points of the geometry. Triangles with boundary edges longer than the
+// max edge length are removed from the Delaunay triangulation of the points,
+// as long as the result stays a single polygon with all the points.
+// Returns nil if the points do not form an area.
+func ConcaveHullByLength(g orb.Geometry, maxEdgeLength float64) orb.Polygon {
+	d := triangulate(g)
+	if d == nil {
+		return nil
+	}
+
+	return concaveHull(d, maxEdgeLength)
+}
+
+// triangulate returns the Delaunay triangulation of the unique points
+// of the geometry, or nil if there are no triangles.
+func triangulate(g orb.Geometry) *delaunay {
+	var points []orb.Point
+	eachPoint(g, func(p orb.Point) {
+		points = append(points, p)
+	})
+
+	if len(points) == 0 {
+		return nil
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i][0] != points[j][0] {
+			return points[i][0] < points[j][0]
+		}
+
+		return points[i][1] < points[j][1]
+	})
+
+	unique := points[:1]
+	for _, p := range points[1:] {
+		if p != unique[len(unique)-1] {
+			unique = append(unique, p)
+		}
+	}
+
+	d := newDelaunay(unique)
+	if len(d.triangles) == 0 {
+		return nil
+	}
+
+	return d
+}
+
+// concaveHull erodes the triangulation from the outside, longest border
+// edges first, until all the border edges are shorter than the threshold.
+// A triangle is only removed if it has one border edge and the opposite
+// vertex is not already on the border. This keeps all the points in
+// the result and keeps the polygon simple.
+func concaveHull(d *delaunay, threshold float64) orb.Polygon {
+	removed := make([]bool, len(d.triangles)/3)
+	border := make([]bool, len(d.points))
+	for _, i := range d.hull {
+		border[i] = true
+	}
+
+	isBorder := func(e int) bool {
+		o := d.halfedges[e]
+		return o == -1 || removed[o/3]
+	}
+
+	length := func(e int) float64 {
+		return Distance(d.points[d.triangles[e]], d.points[d.triangles[nextHalfedge(e)]])
+	}
+
+	queue := &edgeQueue{}
+	for e, o := range d.halfedges {
+		if o == -1 {
+			queue.items = append(queue.items, edgeItem{edge: e, length: length(e)})
+		}
+	}
+	heap.Init(queue)
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(edgeItem)
+		if item.length <= threshold {
+			break
+		}
+
+		e := item.edge
+		t := e / 3
+		if removed[t] {
+			continue
+		}
+
+		e1 := nextHalfedge(e)
+		e2 := nextHalfedge(e1)
+		if isBorder(e1) || isBorder(e2) {
+			continue
+		}
+
+		opposite := d.triangles[e2]
+		if border[opposite] {
+			continue
+		}
+
+		removed[t] = true
+		border[opposite] = true
+
+		heap.Push(queue, edgeItem{edge: d.halfedges[e1], length: length(e1)})
+		heap.Push(queue, edgeItem{edge: d.halfedges[e2], length: length(e2)})
+	}
+
+	// link up the border edges of the remaining triangles
+	next := make(map[int]int)
+	start := -1
+	for e := range d.halfedges {
+		if removed[e/3] || !isBorder(e) {
+			continue
+		}
+
+		next[d.triangles[e]] = d.triangles[nextHalfedge(e)]
+		start = d.triangles[e]
+	}
+
+	ring := make(orb.Ring, 0, len(next)+1)
+	for i, v := 0, start; i <= len(next); i++ {
+		ring = append(ring, d.points[v])
+		v = next[v]
+	}
+
+	// triangles are clockwise
+	ring.Reverse()
+
+	return orb.Polygon{removeCollinear(ring)}
+}
+
+type edgeItem struct {
+	edge   int
+	length float64
+}
+
+// edgeQueue is a max heap of edges by length.
+type edgeQueue struct {
+	items []edgeItem
+}
+
+func (q *edgeQueue) Len() int           { return len(q.items) }
+func (q *edgeQueue) Less(i, j int) bool { return q.items[i].length > q.items[j].length }
+func (q *edgeQueue) Swap(i, j int)      { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *edgeQueue) Push(x interface{}) { q.items = append(q.items, x.(edgeItem)) }
+func (q *edgeQueue) Pop() interface{} {
+	item := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return item
+}
@@ -0,0 +1,73 @@
+package planar
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestConcaveHull(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		ConcaveHull(g, 0.5)
+		ConcaveHullByLength(g, 1)
+	}
+
+	// degenerate cases
+	if p := ConcaveHull(orb.MultiPoint{{0, 0}, {1, 1}, {2, 2}}, 0.5); p != nil {
+		t.Errorf("collinear points should be nil: %v", p)
+	}
+
+	if p := ConcaveHull(orb.MultiPoint{{0, 0}, {0, 0}, {1, 1}}, 0.5); p != nil {
+		t.Errorf("two points should be nil: %v", p)
+	}
+}
+
+func TestConcaveHull_shape(t *testing.T) {
+	// points in a "C" shape
+	//  +------+
+	//  |  +---+
+	//  |  |
+	//  |  +---+
+	//  +------+
+	shape := orb.Polygon{{{0, 0}, {10, 0}, {10, 2}, {2, 2}, {2, 8}, {10, 8}, {10, 10}, {0, 10}, {0, 0}}}
+
+	r := rand.New(rand.NewSource(42))
+	var mp orb.MultiPoint
+	for len(mp) < 2000 {
+		p := orb.Point{r.Float64() * 10, r.Float64() * 10}
+		if PolygonContains(shape, p) {
+			mp = append(mp, p)
+		}
+	}
+
+	convex := ConcaveHull(mp, 1)
+	if a, c := Area(convex), Area(ConvexHull(mp)); math.Abs(a-c) > 1e-10 {
+		t.Errorf("ratio 1 should be the convex hull: %v != %v", a, c)
+	}
+
+	concave := ConcaveHull(mp, 0.05)
+	if a := Area(concave); a > 1.1*Area(shape) || a < 0.8*Area(shape) {
+		t.Errorf("area should be close to the shape: %v", a)
+	}
+
+	if o := concave[0].Orientation(); o != orb.CCW {
+		t.Errorf("should be counter-clockwise: %v", o)
+	}
+
+	for _, p := range mp {
+		if !PolygonContains(concave, p) {
+			t.Fatalf("point should be in hull: %v", p)
+		}
+	}
+
+	if PolygonContains(concave, orb.Point{6, 5}) {
+		t.Errorf("center of the C should not be in the hull")
+	}
+
+	byLength := ConcaveHullByLength(mp, 1)
+	if a := Area(byLength); a > 1.1*Area(shape) || a < 0.8*Area(shape) {
+		t.Errorf("area should be close to the shape: %v", a)
+	}
+}
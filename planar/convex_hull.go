@@ -0,0 +1,125 @@
+package planar
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/segment"
+)
+
+// ConvexHull returns the smallest convex geometry that contains all the
+// points of the geometry. The result is a counter-clockwise orb.Polygon.
+// If all the points are collinear the result is a two point orb.LineString
+// and if there is only one unique point the result is an orb.Point.
+// Returns nil if the geometry has no points.
+func ConvexHull(g orb.Geometry) orb.Geometry {
+	var points []orb.Point
+	eachPoint(g, func(p orb.Point) {
+		points = append(points, p)
+	})
+
+	return convexHull(points)
+}
+
+// convexHull uses Andrew's monotone chain algorithm.
+// The points slice will be reordered.
+func convexHull(points []orb.Point) orb.Geometry {
+	if len(points) == 0 {
+		return nil
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i][0] != points[j][0] {
+			return points[i][0] < points[j][0]
+		}
+
+		return points[i][1] < points[j][1]
+	})
+
+	// remove duplicates
+	unique := points[:1]
+	for _, p := range points[1:] {
+		if p != unique[len(unique)-1] {
+			unique = append(unique, p)
+		}
+	}
+	points = unique
+
+	if len(points) == 1 {
+		return points[0]
+	}
+
+	hull := make(orb.Ring, 0, 2*len(points))
+
+	// lower hull
+	for _, p := range points {
+		for len(hull) >= 2 && segment.Orient(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	// upper hull
+	lower := len(hull) + 1
+	for i := len(points) - 2; i >= 0; i-- {
+		p := points[i]
+		for len(hull) >= lower && segment.Orient(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	// all the points are collinear, the "ring" is there and back.
+	if len(hull) < 4 {
+		return orb.LineString{points[0], points[len(points)-1]}
+	}
+
+	return orb.Polygon{hull}
+}
+
+// eachPoint calls the function for every point of the geometry.
+func eachPoint(g orb.Geometry, f func(orb.Point)) {
+	if g == nil {
+		return
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		f(g)
+	case orb.MultiPoint:
+		for _, p := range g {
+			f(p)
+		}
+	case orb.LineString:
+		for _, p := range g {
+			f(p)
+		}
+	case orb.MultiLineString:
+		for _, ls := range g {
+			eachPoint(ls, f)
+		}
+	case orb.Ring:
+		for _, p := range g {
+			f(p)
+		}
+	case orb.Polygon:
+		for _, r := range g {
+			eachPoint(r, f)
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			eachPoint(p, f)
+		}
+	case orb.Collection:
+		for _, c := range g {
+			eachPoint(c, f)
+		}
+	case orb.Bound:
+		if !g.IsEmpty() {
+			eachPoint(g.ToRing(), f)
+		}
+	default:
+		panic(fmt.Sprintf("geometry type not supported: %T", g))
+	}
+}
@@ -0,0 +1,99 @@
+package planar
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestConvexHull(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		ConvexHull(g)
+	}
+
+	cases := []struct {
+		name   string
+		input  orb.Geometry
+		output orb.Geometry
+	}{
+		{
+			name:   "nil",
+			input:  nil,
+			output: nil,
+		},
+		{
+			name:   "empty",
+			input:  orb.MultiPoint{},
+			output: nil,
+		},
+		{
+			name:   "single point",
+			input:  orb.MultiPoint{{1, 2}, {1, 2}},
+			output: orb.Point{1, 2},
+		},
+		{
+			name:   "collinear",
+			input:  orb.MultiPoint{{1, 1}, {0, 0}, {3, 3}, {2, 2}},
+			output: orb.LineString{{0, 0}, {3, 3}},
+		},
+		{
+			name:   "square with interior and edge points",
+			input:  orb.MultiPoint{{0, 0}, {2, 0}, {1, 0}, {1, 1}, {2, 2}, {0, 2}, {0, 1}},
+			output: orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+		},
+		{
+			name: "nested collection",
+			input: orb.Collection{
+				orb.Point{0, 0},
+				orb.Collection{orb.LineString{{2, 0}, {1, 3}}},
+			},
+			output: orb.Polygon{{{0, 0}, {2, 0}, {1, 3}, {0, 0}}},
+		},
+		{
+			name:   "bound",
+			input:  orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}},
+			output: orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := ConvexHull(tc.input)
+			if !reflect.DeepEqual(result, tc.output) {
+				t.Errorf("incorrect hull: %v != %v", result, tc.output)
+			}
+		})
+	}
+}
+
+func TestConvexHull_random(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	mp := make(orb.MultiPoint, 1000)
+	for i := range mp {
+		mp[i] = orb.Point{r.Float64(), r.Float64()}
+	}
+
+	original := mp.Clone()
+	hull, ok := ConvexHull(mp).(orb.Polygon)
+	if !ok {
+		t.Fatalf("should be a polygon")
+	}
+
+	if o := hull[0].Orientation(); o != orb.CCW {
+		t.Errorf("should be counter-clockwise: %v", o)
+	}
+
+	for _, p := range mp {
+		if !PolygonContains(hull, p) {
+			t.Errorf("point not in hull: %v", p)
+		}
+	}
+
+	// the input should not be modified
+	if !mp.Equal(original) {
+		t.Errorf("input should not be modified")
+	}
+}
@@ -0,0 +1,467 @@
+package planar
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/segment"
+)
+
+// Code based on https://github.com/mapbox/delaunator
+
+// delaunay is a Delaunay triangulation stored as half-edges.
+// Triangle t is made of the half-edges 3t, 3t+1 and 3t+2. Half-edge e
+// starts at points[triangles[e]] and halfedges[e] is the opposite half-edge
+// in the adjacent triangle, or -1 if e is on the convex hull.
+// Triangles are in clockwise order.
+type delaunay struct {
+	points    []orb.Point
+	triangles []int
+	halfedges []int
+	hull      []int
+
+	// used during construction
+	hullPrev, hullNext, hullTri, hullHash []int
+	hullStart                             int
+	cx, cy                                float64
+}
+
+// newDelaunay triangulates the points. Duplicate points are skipped.
+// If all the points are collinear there will be no triangles and the hull
+// will be the points in order along the line.
+func newDelaunay(points []orb.Point) *delaunay {
+	n := len(points)
+	maxTriangles := 2*n - 5
+	if maxTriangles < 0 {
+		maxTriangles = 0
+	}
+
+	d := &delaunay{
+		points:    points,
+		triangles: make([]int, 0, maxTriangles*3),
+		halfedges: make([]int, 0, maxTriangles*3),
+	}
+
+	if n == 0 {
+		return d
+	}
+
+	b := orb.MultiPoint(points).Bound()
+	c := b.Center()
+
+	// pick a seed point close to the center
+	i0 := 0
+	minDist := math.Inf(1)
+	for i, p := range points {
+		if dist := DistanceSquared(c, p); dist < minDist {
+			i0 = i
+			minDist = dist
+		}
+	}
+
+	// find the point closest to the seed
+	i1 := -1
+	minDist = math.Inf(1)
+	for i, p := range points {
+		if dist := DistanceSquared(points[i0], p); dist < minDist && dist > 0 {
+			i1 = i
+			minDist = dist
+		}
+	}
+
+	// find the third point which forms the smallest circumcircle with the first two
+	i2 := -1
+	minRadius := math.Inf(1)
+	if i1 != -1 {
+		for i, p := range points {
+			if i == i0 || i == i1 {
+				continue
+			}
+
+			if r := circumradius(points[i0], points[i1], p); r < minRadius {
+				i2 = i
+				minRadius = r
+			}
+		}
+	}
+
+	if math.IsInf(minRadius, 1) {
+		d.collinearHull(i0)
+		return d
+	}
+
+	// swap the order of the seed points for clockwise orientation
+	if segment.Orient(points[i0], points[i1], points[i2]) > 0 {
+		i1, i2 = i2, i1
+	}
+
+	center := circumcenter(points[i0], points[i1], points[i2])
+	d.cx, d.cy = center[0], center[1]
+
+	// sort the points by distance from the seed triangle circumcenter
+	ids := make([]int, n)
+	dists := make([]float64, n)
+	for i, p := range points {
+		ids[i] = i
+		dists[i] = DistanceSquared(p, center)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return dists[ids[i]] < dists[ids[j]]
+	})
+
+	hashSize := int(math.Ceil(math.Sqrt(float64(n))))
+	d.hullPrev = make([]int, n)
+	d.hullNext = make([]int, n)
+	d.hullTri = make([]int, n)
+	d.hullHash = make([]int, hashSize)
+	for i := range d.hullHash {
+		d.hullHash[i] = -1
+	}
+
+	// set up the seed triangle as the starting hull
+	d.hullStart = i0
+	hullSize := 3
+
+	d.hullNext[i0], d.hullPrev[i2] = i1, i1
+	d.hullNext[i1], d.hullPrev[i0] = i2, i2
+	d.hullNext[i2], d.hullPrev[i1] = i0, i0
+
+	d.hullTri[i0] = 0
+	d.hullTri[i1] = 1
+	d.hullTri[i2] = 2
+
+	d.hullHash[d.hashKey(points[i0])] = i0
+	d.hullHash[d.hashKey(points[i1])] = i1
+	d.hullHash[d.hashKey(points[i2])] = i2
+
+	d.addTriangle(i0, i1, i2, -1, -1, -1)
+
+	var prev orb.Point
+	for k, i := range ids {
+		p := points[i]
+
+		// skip duplicate points
+		if k > 0 && p == prev {
+			continue
+		}
+		prev = p
+
+		// skip seed triangle points
+		if i == i0 || i == i1 || i == i2 {
+			continue
+		}
+
+		// find a visible edge on the convex hull using edge hash
+		start := 0
+		key := d.hashKey(p)
+		for j := 0; j < hashSize; j++ {
+			start = d.hullHash[(key+j)%hashSize]
+			if start != -1 && start != d.hullNext[start] {
+				break
+			}
+		}
+
+		start = d.hullPrev[start]
+		e := start
+		for {
+			q := d.hullNext[e]
+			if stableOrient(p, points[e], points[q]) {
+				break
+			}
+
+			e = q
+			if e == start {
+				e = -1
+				break
+			}
+		}
+
+		if e == -1 {
+			// likely a near-duplicate point, skip it
+			continue
+		}
+
+		// add the first triangle from the point
+		t := d.addTriangle(e, i, d.hullNext[e], -1, -1, d.hullTri[e])
+
+		// recursively flip triangles from the point until they satisfy the Delaunay condition
+		d.hullTri[i] = d.legalize(t + 2)
+		d.hullTri[e] = t // keep track of boundary triangles on the hull
+		hullSize++
+
+		// walk forward through the hull, adding more triangles and flipping recursively
+		next := d.hullNext[e]
+		for {
+			q := d.hullNext[next]
+			if !stableOrient(p, points[next], points[q]) {
+				break
+			}
+
+			t = d.addTriangle(next, i, q, d.hullTri[i], -1, d.hullTri[next])
+			d.hullTri[i] = d.legalize(t + 2)
+			d.hullNext[next] = next // mark as removed
+			hullSize--
+			next = q
+		}
+
+		// walk backward from the other side, adding more triangles and flipping
+		if e == start {
+			for {
+				q := d.hullPrev[e]
+				if !stableOrient(p, points[q], points[e]) {
+					break
+				}
+
+				t = d.addTriangle(q, i, e, -1, d.hullTri[e], d.hullTri[q])
+				d.legalize(t + 2)
+				d.hullTri[q] = t
+				d.hullNext[e] = e // mark as removed
+				hullSize--
+				e = q
+			}
+		}
+
+		// update the hull indices
+		d.hullStart = e
+		d.hullPrev[i] = e
+		d.hullNext[e] = i
+		d.hullPrev[next] = i
+		d.hullNext[i] = next
+
+		// save the two new edges in the hash table
+		d.hullHash[d.hashKey(p)] = i
+		d.hullHash[d.hashKey(points[e])] = e
+	}
+
+	d.hull = make([]int, 0, hullSize)
+	for i, e := 0, d.hullStart; i < hullSize; i++ {
+		d.hull = append(d.hull, e)
+		e = d.hullNext[e]
+	}
+
+	d.hullPrev, d.hullNext, d.hullTri, d.hullHash = nil, nil, nil, nil
+	return d
+}
+
+// collinearHull orders the points along the line as the "hull".
+func (d *delaunay) collinearHull(i0 int) {
+	start := d.points[i0]
+
+	dists := make([]float64, len(d.points))
+	ids := make([]int, len(d.points))
+	for i, p := range d.points {
+		ids[i] = i
+		dists[i] = p[0] - start[0]
+		if dists[i] == 0 {
+			dists[i] = p[1] - start[1]
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return dists[ids[i]] < dists[ids[j]]
+	})
+
+	for k, i := range ids {
+		if k > 0 && dists[i] == dists[ids[k-1]] {
+			continue
+		}
+		d.hull = append(d.hull, i)
+	}
+}
+
+func (d *delaunay) hashKey(p orb.Point) int {
+	dx := p[0] - d.cx
+	dy := p[1] - d.cy
+
+	// pseudo angle in the range [0, 1]
+	a := dx / (math.Abs(dx) + math.Abs(dy))
+	if dy > 0 {
+		a = (3 - a) / 4
+	} else {
+		a = (1 + a) / 4
+	}
+
+	if math.IsNaN(a) {
+		a = 0
+	}
+
+	size := len(d.hullHash)
+	return int(math.Floor(a*float64(size))) % size
+}
+
+func (d *delaunay) legalize(a int) int {
+	var stack [512]int
+	i := 0
+	ar := 0
+
+	// recursion eliminated with a fixed-size stack
+	for {
+		b := d.halfedges[a]
+
+		// if the pair of triangles doesn't satisfy the Delaunay condition
+		// (p1 is inside the circumcircle of [p0, pl, pr]), flip them,
+		// then do the same check/flip recursively for the new pair of triangles
+		a0 := a - a%3
+		ar = a0 + (a+2)%3
+
+		if b == -1 {
+			// convex hull edge
+			if i == 0 {
+				break
+			}
+			i--
+			a = stack[i]
+			continue
+		}
+
+		b0 := b - b%3
+		al := a0 + (a+1)%3
+		bl := b0 + (b+2)%3
+
+		p0 := d.triangles[ar]
+		pr := d.triangles[a]
+		pl := d.triangles[al]
+		p1 := d.triangles[bl]
+
+		if !inCircle(d.points[p0], d.points[pr], d.points[pl], d.points[p1]) {
+			if i == 0 {
+				break
+			}
+			i--
+			a = stack[i]
+			continue
+		}
+
+		d.triangles[a] = p1
+		d.triangles[b] = p0
+
+		hbl := d.halfedges[bl]
+
+		// edge swapped on the other side of the hull (rare), fix the halfedge reference
+		if hbl == -1 {
+			e := d.hullStart
+			for {
+				if d.hullTri[e] == bl {
+					d.hullTri[e] = a
+					break
+				}
+
+				e = d.hullPrev[e]
+				if e == d.hullStart {
+					break
+				}
+			}
+		}
+
+		d.link(a, hbl)
+		d.link(b, d.halfedges[ar])
+		d.link(ar, bl)
+
+		// don't worry about hitting the cap, it can only happen on extremely degenerate input
+		if i < len(stack) {
+			stack[i] = b0 + (b+1)%3
+			i++
+		}
+	}
+
+	return ar
+}
+
+func (d *delaunay) link(a, b int) {
+	d.halfedges[a] = b
+	if b != -1 {
+		d.halfedges[b] = a
+	}
+}
+
+// addTriangle adds a new triangle given vertex indices and adjacent half-edge ids.
+func (d *delaunay) addTriangle(i0, i1, i2, a, b, c int) int {
+	t := len(d.triangles)
+
+	d.triangles = append(d.triangles, i0, i1, i2)
+	d.halfedges = append(d.halfedges, -1, -1, -1)
+
+	d.link(t, a)
+	d.link(t+1, b)
+	d.link(t+2, c)
+
+	return t
+}
+
+// nextHalfedge returns the next half-edge in the same triangle.
+func nextHalfedge(e int) int {
+	if e%3 == 2 {
+		return e - 2
+	}
+
+	return e + 1
+}
+
+// orientIfSure returns the orientation sign if we're confident in it
+// through J. Shewchuk's error bound check.
+func orientIfSure(p, r, q orb.Point) float64 {
+	l := (r[1] - p[1]) * (q[0] - p[0])
+	rr := (r[0] - p[0]) * (q[1] - p[1])
+	if math.Abs(l-rr) >= 3.3306690738754716e-16*math.Abs(l+rr) {
+		return l - rr
+	}
+
+	return 0
+}
+
+// stableOrient is a more robust orientation test that's stable in a given
+// triangle. It returns true if the points are in counter-clockwise order.
+func stableOrient(r, q, p orb.Point) bool {
+	sign := orientIfSure(p, r, q)
+	if sign == 0 {
+		sign = orientIfSure(r, q, p)
+	}
+	if sign == 0 {
+		sign = orientIfSure(q, p, r)
+	}
+
+	return sign < 0
+}
+
+// inCircle returns true if p is inside the circumcircle of the clockwise triangle abc.
+func inCircle(a, b, c, p orb.Point) bool {
+	dx := a[0] - p[0]
+	dy := a[1] - p[1]
+	ex := b[0] - p[0]
+	ey := b[1] - p[1]
+	fx := c[0] - p[0]
+	fy := c[1] - p[1]
+
+	ap := dx*dx + dy*dy
+	bp := ex*ex + ey*ey
+	cp := fx*fx + fy*fy
+
+	return dx*(ey*cp-bp*fy)-dy*(ex*cp-bp*fx)+ap*(ex*fy-ey*fx) < 0
+}
+
+// circumradius returns the squared radius of the circle through the points.
+func circumradius(a, b, c orb.Point) float64 {
+	cc := circumcenter(a, b, c)
+	r := DistanceSquared(a, cc)
+	if math.IsNaN(r) {
+		return math.Inf(1)
+	}
+
+	return r
+}
+
+func circumcenter(a, b, c orb.Point) orb.Point {
+	dx := b[0] - a[0]
+	dy := b[1] - a[1]
+	ex := c[0] - a[0]
+	ey := c[1] - a[1]
+
+	bl := dx*dx + dy*dy
+	cl := ex*ex + ey*ey
+	d := 0.5 / (dx*ey - dy*ex)
+
+	return orb.Point{
+		a[0] + (ey*bl-dy*cl)*d,
+		a[1] + (dx*cl-ex*bl)*d,
+	}
+}
@@ -0,0 +1,77 @@
+package planar
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/segment"
+)
+
+func TestDelaunay(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	points := make([]orb.Point, 500)
+	for i := range points {
+		points[i] = orb.Point{r.Float64(), r.Float64()}
+	}
+
+	// some points on a grid to get degenerate cases
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 10; j++ {
+			points = append(points, orb.Point{2 + float64(i)/10, float64(j) / 10})
+		}
+	}
+
+	d := newDelaunay(points)
+
+	if l := len(d.triangles) / 3; l != 2*len(points)-2-len(d.hull) {
+		t.Errorf("incorrect number of triangles: %d", l)
+	}
+
+	for e, o := range d.halfedges {
+		if o != -1 && d.halfedges[o] != e {
+			t.Fatalf("invalid halfedge link: %d", e)
+		}
+	}
+
+	for i := 0; i < len(d.triangles); i += 3 {
+		a := d.points[d.triangles[i]]
+		b := d.points[d.triangles[i+1]]
+		c := d.points[d.triangles[i+2]]
+
+		if segment.Orient(a, b, c) >= 0 {
+			t.Errorf("triangle not clockwise: %v %v %v", a, b, c)
+		}
+	}
+
+	// the Delaunay condition is checked for every pair of triangles
+	for e, o := range d.halfedges {
+		if o == -1 {
+			continue
+		}
+
+		a := d.points[d.triangles[e]]
+		b := d.points[d.triangles[nextHalfedge(e)]]
+		c := d.points[d.triangles[nextHalfedge(nextHalfedge(e))]]
+		p := d.points[d.triangles[nextHalfedge(nextHalfedge(o))]]
+
+		r := circumradius(a, b, c)
+		if DistanceSquared(p, circumcenter(a, b, c)) < r*(1-1e-9) {
+			t.Errorf("point in circumcircle: %v", p)
+		}
+	}
+}
+
+func TestDelaunay_collinear(t *testing.T) {
+	points := []orb.Point{{2, 2}, {0, 0}, {1, 1}, {3, 3}}
+
+	d := newDelaunay(points)
+	if len(d.triangles) != 0 {
+		t.Errorf("should have no triangles: %v", d.triangles)
+	}
+
+	if len(d.hull) != 4 {
+		t.Errorf("hull should have all the points: %v", d.hull)
+	}
+}
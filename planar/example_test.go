@@ -44,3 +44,53 @@ func ExampleLength() {
 	// Output:
 	// 12
 }
+
+func ExampleUnion() {
+	// +---+
+	// |   |
+	// | +-+-+
+	// +-+-+ |
+	//   |   |
+	//   +---+
+
+	a := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{2, 2}}
+	b := orb.Bound{Min: orb.Point{1, -1}, Max: orb.Point{3, 1}}
+
+	fmt.Println(planar.Area(planar.Union(a, b)))
+	fmt.Println(planar.Area(planar.Intersection(a, b)))
+	fmt.Println(planar.Area(planar.Difference(a, b)))
+	// Output:
+	// 7
+	// 1
+	// 3
+}
+
+func ExampleBuffer() {
+	ls := orb.LineString{{0, 0}, {10, 0}}
+
+	buffer := planar.Buffer(ls, 1, planar.EndCap(planar.CapSquare))
+
+	fmt.Println(planar.Area(buffer))
+	// Output:
+	// 24
+}
+
+func ExampleConvexHull() {
+	mp := orb.MultiPoint{{0, 0}, {2, 0}, {1, 1}, {2, 2}, {0, 2}}
+	hull := planar.ConvexHull(mp)
+
+	fmt.Println(hull)
+	// Output:
+	// [[[0 0] [2 0] [2 2] [0 2] [0 0]]]
+}
+
+func ExampleRelate() {
+	square := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{2, 2}}
+	line := orb.LineString{{-1, 1}, {3, 1}}
+
+	fmt.Println(planar.Relate(line, square))
+	fmt.Println(planar.Crosses(line, square))
+	// Output:
+	// 101FF0212
+	// true
+}
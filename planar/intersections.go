@@ -0,0 +1,232 @@
+package planar
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/segment"
+)
+
+// Intersections returns the points where the geometries touch or cross.
+// 2d geometries are compared using their rings, so a line inside a polygon
+// has no intersections. Where segments overlap the ends of the overlap
+// are returned. The result is sorted by x then y and has no duplicates.
+func Intersections(a, b orb.Geometry) []orb.Point {
+	segments := appendSweepSegments(nil, a, 0)
+	segments = appendSweepSegments(segments, b, 1)
+
+	var result []orb.Point
+	sweep(segments, func(s1, s2 *sweepSegment) {
+		if s1.owner == s2.owner {
+			return
+		}
+
+		// same order every time so computed points are consistent
+		if s1.owner > s2.owner {
+			s1, s2 = s2, s1
+		}
+
+		p1, p2, n := intersection(s1.a, s1.b, s2.a, s2.b)
+		found := [2]orb.Point{p1, p2}
+		result = append(result, found[:n]...)
+	})
+
+	return sortUnique(result)
+}
+
+// SelfIntersections returns the points where the line string crosses
+// or touches itself. Consecutive segments that only share their common
+// point are fine, as are the first and last segments of a closed line.
+// If consecutive segments fold back on themselves the end of the overlap
+// is returned. Repeated points are ignored. The result is sorted by x then y.
+func SelfIntersections(ls orb.LineString) []orb.Point {
+	ls = removeRepeated(ls)
+	n := len(ls) - 1
+	if n < 2 {
+		return nil
+	}
+
+	closed := ls[0] == ls[n]
+	segments := make([]sweepSegment, 0, n)
+	for i := 0; i < n; i++ {
+		segments = append(segments, newSweepSegment(ls[i], ls[i+1], i))
+	}
+
+	var result []orb.Point
+	sweep(segments, func(s1, s2 *sweepSegment) {
+		if s1.index > s2.index {
+			s1, s2 = s2, s1
+		}
+
+		p1, p2, c := intersection(s1.a, s1.b, s2.a, s2.b)
+		if c == 0 {
+			return
+		}
+
+		lo, hi := s1.index, s2.index
+
+		adjacent := hi == lo+1
+		shared := ls[hi]
+		if !adjacent && closed && lo == 0 && hi == n-1 {
+			adjacent = true
+			shared = ls[0]
+		}
+
+		if !adjacent {
+			found := [2]orb.Point{p1, p2}
+			result = append(result, found[:c]...)
+			return
+		}
+
+		if c == 1 {
+			// only the shared point
+			return
+		}
+
+		// collinear overlap, return the end that isn't the shared point
+		if p1 == shared {
+			p1 = p2
+		}
+		result = append(result, p1)
+	})
+
+	return sortUnique(result)
+}
+
+// sweepSegment is a segment with the bound and where it came from.
+// Points are zero length segments.
+type sweepSegment struct {
+	a, b  orb.Point
+	bound orb.Bound
+	index int
+	owner int
+}
+
+func newSweepSegment(a, b orb.Point, index int) sweepSegment {
+	return sweepSegment{
+		a:     a,
+		b:     b,
+		bound: orb.MultiPoint{a, b}.Bound(),
+		index: index,
+	}
+}
+
+// sweep calls the function for every pair of segments with intersecting bounds,
+// in the order they were added.
+func sweep(segments []sweepSegment, f func(s1, s2 *sweepSegment)) {
+	bounds := make([]orb.Bound, len(segments))
+	for i, s := range segments {
+		bounds[i] = s.bound
+	}
+
+	segment.Sweep(bounds, func(i, j int) {
+		f(&segments[i], &segments[j])
+	})
+}
+
+// appendSweepSegments adds the segments of the geometry, including the
+// rings of 2d geometries and points as zero length segments.
+func appendSweepSegments(segments []sweepSegment, g orb.Geometry, owner int) []sweepSegment {
+	if g == nil {
+		return segments
+	}
+
+	add := func(a, b orb.Point) {
+		s := newSweepSegment(a, b, len(segments))
+		s.owner = owner
+		segments = append(segments, s)
+	}
+
+	line := func(ls []orb.Point, closed bool) {
+		if len(ls) == 1 {
+			add(ls[0], ls[0])
+		}
+
+		for i := 0; i < len(ls)-1; i++ {
+			add(ls[i], ls[i+1])
+		}
+
+		if closed && len(ls) > 1 && ls[0] != ls[len(ls)-1] {
+			add(ls[len(ls)-1], ls[0])
+		}
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		add(g, g)
+	case orb.MultiPoint:
+		for _, p := range g {
+			add(p, p)
+		}
+	case orb.LineString:
+		line(g, false)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			line(ls, false)
+		}
+	case orb.Ring:
+		line(g, true)
+	case orb.Polygon:
+		for _, r := range g {
+			line(r, true)
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			for _, r := range p {
+				line(r, true)
+			}
+		}
+	case orb.Collection:
+		for _, c := range g {
+			segments = appendSweepSegments(segments, c, owner)
+		}
+	case orb.Bound:
+		line(g.ToRing(), true)
+	default:
+		panic(fmt.Sprintf("geometry type not supported: %T", g))
+	}
+
+	return segments
+}
+
+// intersection is segment.Intersection that also supports zero length
+// segments, ie. points.
+func intersection(a1, a2, b1, b2 orb.Point) (orb.Point, orb.Point, int) {
+	if a1 == a2 {
+		a1, a2, b1, b2 = b1, b2, a1, a2
+	}
+
+	if b1 != b2 {
+		return segment.Intersection(a1, a2, b1, b2)
+	}
+
+	if segment.OnSegment(a1, a2, b1) {
+		return b1, orb.Point{}, 1
+	}
+
+	return orb.Point{}, orb.Point{}, 0
+}
+
+func sortUnique(points []orb.Point) []orb.Point {
+	if len(points) == 0 {
+		return nil
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		if points[i][0] != points[j][0] {
+			return points[i][0] < points[j][0]
+		}
+
+		return points[i][1] < points[j][1]
+	})
+
+	result := points[:1]
+	for _, p := range points[1:] {
+		if p != result[len(result)-1] {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
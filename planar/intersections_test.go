@@ -0,0 +1,170 @@
+package planar
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestIntersections(t *testing.T) {
+	for _, a := range orb.AllGeometries {
+		for _, b := range orb.AllGeometries {
+			Intersections(a, b)
+		}
+	}
+
+	cases := []struct {
+		name   string
+		a, b   orb.Geometry
+		result []orb.Point
+	}{
+		{
+			name:   "crossing lines",
+			a:      orb.LineString{{0, 0}, {2, 2}},
+			b:      orb.LineString{{0, 2}, {2, 0}},
+			result: []orb.Point{{1, 1}},
+		},
+		{
+			name:   "touching at ends",
+			a:      orb.LineString{{0, 0}, {1, 0}},
+			b:      orb.LineString{{1, 0}, {2, 1}},
+			result: []orb.Point{{1, 0}},
+		},
+		{
+			name:   "overlapping lines",
+			a:      orb.LineString{{0, 0}, {2, 0}},
+			b:      orb.LineString{{3, 0}, {1, 0}},
+			result: []orb.Point{{1, 0}, {2, 0}},
+		},
+		{
+			name:   "disjoint",
+			a:      orb.LineString{{0, 0}, {2, 0}},
+			b:      orb.LineString{{0, 1}, {2, 1}},
+			result: nil,
+		},
+		{
+			name:   "line through polygon",
+			a:      orb.LineString{{-1, 1}, {3, 1}},
+			b:      orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{2, 2}},
+			result: []orb.Point{{0, 1}, {2, 1}},
+		},
+		{
+			name:   "line inside polygon",
+			a:      orb.LineString{{0.5, 1}, {1.5, 1}},
+			b:      orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{2, 2}},
+			result: nil,
+		},
+		{
+			name:   "unclosed ring",
+			a:      orb.LineString{{-1, 1}, {1, 1}},
+			b:      orb.Ring{{0, 0}, {2, 0}, {2, 2}, {0, 2}},
+			result: []orb.Point{{0, 1}},
+		},
+		{
+			name:   "points",
+			a:      orb.MultiPoint{{1, 0}, {1, 1}, {5, 5}},
+			b:      orb.Collection{orb.LineString{{0, 0}, {2, 0}}, orb.Point{5, 5}},
+			result: []orb.Point{{1, 0}, {5, 5}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Intersections(tc.a, tc.b)
+			if !reflect.DeepEqual(result, tc.result) {
+				t.Errorf("incorrect result: %v", result)
+				t.Log(tc.result)
+			}
+		})
+	}
+}
+
+func TestIntersections_bruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	randomLine := func(n int) orb.LineString {
+		ls := orb.LineString{}
+		for i := 0; i < n; i++ {
+			ls = append(ls, orb.Point{float64(r.Intn(20)), float64(r.Intn(20))})
+		}
+		return ls
+	}
+
+	for i := 0; i < 100; i++ {
+		a, b := randomLine(20), randomLine(20)
+
+		var expected []orb.Point
+		for i := 0; i < len(a)-1; i++ {
+			for j := 0; j < len(b)-1; j++ {
+				p1, p2, n := intersection(a[i], a[i+1], b[j], b[j+1])
+				found := [2]orb.Point{p1, p2}
+				expected = append(expected, found[:n]...)
+			}
+		}
+
+		result := Intersections(a, b)
+		if !reflect.DeepEqual(result, sortUnique(expected)) {
+			t.Fatalf("incorrect result for %v %v", a, b)
+		}
+	}
+}
+
+func TestSelfIntersections(t *testing.T) {
+	cases := []struct {
+		name   string
+		input  orb.LineString
+		result []orb.Point
+	}{
+		{
+			name:   "simple",
+			input:  orb.LineString{{0, 0}, {1, 0}, {1, 1}, {2, 1}},
+			result: nil,
+		},
+		{
+			name:   "closed",
+			input:  orb.LineString{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+			result: nil,
+		},
+		{
+			name:   "crosses itself",
+			input:  orb.LineString{{0, 0}, {2, 2}, {2, 0}, {0, 2}},
+			result: []orb.Point{{1, 1}},
+		},
+		{
+			name:   "bowtie ring",
+			input:  orb.LineString{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}},
+			result: []orb.Point{{1, 1}},
+		},
+		{
+			name:   "touches itself",
+			input:  orb.LineString{{0, 0}, {4, 0}, {4, 4}, {2, 0}},
+			result: []orb.Point{{2, 0}},
+		},
+		{
+			name:   "end touches start",
+			input:  orb.LineString{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {2, 0}},
+			result: []orb.Point{{2, 0}},
+		},
+		{
+			name:   "folds back",
+			input:  orb.LineString{{0, 0}, {4, 0}, {2, 0}, {2, 2}},
+			result: []orb.Point{{2, 0}},
+		},
+		{
+			name:   "repeated points",
+			input:  orb.LineString{{0, 0}, {1, 0}, {1, 0}, {1, 1}},
+			result: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := SelfIntersections(tc.input)
+			if !reflect.DeepEqual(result, tc.result) {
+				t.Errorf("incorrect result: %v", result)
+				t.Log(tc.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package planar
+
+import (
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/linear"
+)
+
+var measure = linear.Measure{
+	Distance:    Distance,
+	Interpolate: pointOnSegment,
+	Project:     projectOnSegment,
+}
+
+// LocatePoint returns the fraction of the length of the line string to
+// the point on the line string closest to p, along with that point.
+// Will panic if the line string is empty.
+func LocatePoint(ls orb.LineString, p orb.Point) (float64, orb.Point) {
+	return measure.LocatePoint(ls, p)
+}
+
+// PointAtFraction returns the point at the fraction of the length of the
+// line string. The fraction is clamped to [0, 1].
+// Will panic if the line string is empty.
+func PointAtFraction(ls orb.LineString, fraction float64) orb.Point {
+	return measure.PointAtFraction(ls, fraction)
+}
+
+// Substring returns the part of the line string between the start and
+// end fractions of its length. If start is greater than end the result
+// is reversed. The fractions are clamped to [0, 1].
+func Substring(ls orb.LineString, start, end float64) orb.LineString {
+	return measure.Substring(ls, start, end)
+}
+
+func pointOnSegment(a, b orb.Point, fraction float64) orb.Point {
+	if fraction == 1 {
+		return b
+	}
+
+	return orb.Point{
+		a[0] + fraction*(b[0]-a[0]),
+		a[1] + fraction*(b[1]-a[1]),
+	}
+}
+
+// projectOnSegment returns the fraction along the segment of the closest point.
+func projectOnSegment(a, b, p orb.Point) float64 {
+	dx := b[0] - a[0]
+	dy := b[1] - a[1]
+	if dx == 0 && dy == 0 {
+		return 0
+	}
+
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / (dx*dx + dy*dy)
+	if t < 0 {
+		return 0
+	}
+
+	if t > 1 {
+		return 1
+	}
+
+	return t
+}
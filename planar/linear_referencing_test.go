@@ -0,0 +1,169 @@
+package planar
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestLocatePoint(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {2, 0}, {2, 2}}
+
+	cases := []struct {
+		name      string
+		line      orb.LineString
+		point     orb.Point
+		fraction  float64
+		projected orb.Point
+	}{
+		{
+			name:      "on the line",
+			line:      ls,
+			point:     orb.Point{1, 0},
+			fraction:  0.25,
+			projected: orb.Point{1, 0},
+		},
+		{
+			name:      "beside the line",
+			line:      ls,
+			point:     orb.Point{3, 1},
+			fraction:  0.75,
+			projected: orb.Point{2, 1},
+		},
+		{
+			name:      "before the start",
+			line:      ls,
+			point:     orb.Point{-1, -1},
+			fraction:  0,
+			projected: orb.Point{0, 0},
+		},
+		{
+			name:      "after the end",
+			line:      ls,
+			point:     orb.Point{2, 5},
+			fraction:  1,
+			projected: orb.Point{2, 2},
+		},
+		{
+			name:      "at a vertex",
+			line:      ls,
+			point:     orb.Point{3, -1},
+			fraction:  0.5,
+			projected: orb.Point{2, 0},
+		},
+		{
+			name:      "repeated points",
+			line:      orb.LineString{{0, 0}, {0, 0}, {4, 0}},
+			point:     orb.Point{1, 1},
+			fraction:  0.25,
+			projected: orb.Point{1, 0},
+		},
+		{
+			name:      "single point",
+			line:      orb.LineString{{1, 1}},
+			point:     orb.Point{3, 1},
+			fraction:  0,
+			projected: orb.Point{1, 1},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, p := LocatePoint(tc.line, tc.point)
+			if f != tc.fraction {
+				t.Errorf("incorrect fraction: %v != %v", f, tc.fraction)
+			}
+
+			if p != tc.projected {
+				t.Errorf("incorrect point: %v != %v", p, tc.projected)
+			}
+		})
+	}
+}
+
+func TestPointAtFraction(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {2, 0}, {2, 2}}
+
+	cases := []struct {
+		fraction float64
+		point    orb.Point
+	}{
+		{fraction: -1, point: orb.Point{0, 0}},
+		{fraction: 0, point: orb.Point{0, 0}},
+		{fraction: 0.25, point: orb.Point{1, 0}},
+		{fraction: 0.5, point: orb.Point{2, 0}},
+		{fraction: 0.75, point: orb.Point{2, 1}},
+		{fraction: 1, point: orb.Point{2, 2}},
+		{fraction: 2, point: orb.Point{2, 2}},
+	}
+
+	for _, tc := range cases {
+		if p := PointAtFraction(ls, tc.fraction); p != tc.point {
+			t.Errorf("incorrect point for %v: %v != %v", tc.fraction, p, tc.point)
+		}
+	}
+}
+
+func TestSubstring(t *testing.T) {
+	ls := orb.LineString{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+
+	cases := []struct {
+		name       string
+		start, end float64
+		result     orb.LineString
+	}{
+		{
+			name:   "all",
+			start:  0,
+			end:    1,
+			result: ls,
+		},
+		{
+			name:   "within a segment",
+			start:  0.5 / 6,
+			end:    1.0 / 6,
+			result: orb.LineString{{0.5, 0}, {1, 0}},
+		},
+		{
+			name:   "across vertices",
+			start:  0.5 / 6,
+			end:    5.0 / 6,
+			result: orb.LineString{{0.5, 0}, {2, 0}, {2, 2}, {1, 2}},
+		},
+		{
+			name:   "from a vertex",
+			start:  1.0 / 3,
+			end:    0.5,
+			result: orb.LineString{{2, 0}, {2, 1}},
+		},
+		{
+			name:   "reversed",
+			start:  5.0 / 6,
+			end:    0.5 / 6,
+			result: orb.LineString{{1, 2}, {2, 2}, {2, 0}, {0.5, 0}},
+		},
+		{
+			name:   "zero length",
+			start:  0.5,
+			end:    0.5,
+			result: orb.LineString{{2, 1}, {2, 1}},
+		},
+		{
+			name:   "clamped",
+			start:  -1,
+			end:    2,
+			result: ls,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Substring(ls, tc.start, tc.end)
+			if !reflect.DeepEqual(result, tc.result) {
+				t.Errorf("incorrect result: %v", result)
+				t.Log(tc.result)
+			}
+		})
+	}
+}
@@ -0,0 +1,657 @@
+package planar
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/segment"
+)
+
+// Union returns the area covered by either of the geometries.
+// Only the 2d parts of the inputs, ie. rings, polygons, multi polygons
+// and bounds, are considered. Overlapping parts of an input are merged.
+// The result will have outer rings in counter-clockwise order and holes
+// in clockwise order.
+func Union(a, b orb.Geometry) orb.MultiPolygon {
+	return overlay(a, b, opUnion)
+}
+
+// Intersection returns the area covered by both of the geometries.
+// Only the 2d parts of the inputs are considered. The result will have
+// outer rings in counter-clockwise order and holes in clockwise order.
+func Intersection(a, b orb.Geometry) orb.MultiPolygon {
+	return overlay(a, b, opIntersection)
+}
+
+// Difference returns the area covered by the first geometry but not the second.
+// Only the 2d parts of the inputs are considered. The result will have
+// outer rings in counter-clockwise order and holes in clockwise order.
+func Difference(a, b orb.Geometry) orb.MultiPolygon {
+	return overlay(a, b, opDifference)
+}
+
+// SymDifference returns the area covered by exactly one of the geometries,
+// ie. the xor. Only the 2d parts of the inputs are considered. The result
+// will have outer rings in counter-clockwise order and holes in clockwise order.
+func SymDifference(a, b orb.Geometry) orb.MultiPolygon {
+	return overlay(a, b, opSymDifference)
+}
+
+type overlayOp int
+
+const (
+	opUnion overlayOp = iota
+	opIntersection
+	opDifference
+	opSymDifference
+)
+
+func (op overlayOp) include(inA, inB bool) bool {
+	switch op {
+	case opUnion:
+		return inA || inB
+	case opIntersection:
+		return inA && inB
+	case opDifference:
+		return inA && !inB
+	case opSymDifference:
+		return inA != inB
+	}
+
+	panic(fmt.Sprintf("overlay operation not supported: %d", op))
+}
+
+// overlayEdge is a directed edge of the result. The interior
+// is always on the left of the edge.
+type overlayEdge struct {
+	a, b orb.Point
+}
+
+// overlaySegment is a piece of the input edges with its points in
+// a consistent order, see segmentKey. Counts track how many input edges,
+// from each input, run the same and opposite direction.
+type overlaySegment struct {
+	a, b     orb.Point
+	fwd, rev [2]int
+	splits   []orb.Point
+}
+
+// overlay computes the boolean operation between the areas of the geometries.
+// The boundaries of both inputs are noded against each other, the pieces are
+// classified by looking at the "insideness" of both inputs on each side and
+// the pieces that bound the result are reassembled into rings.
+func overlay(a, b orb.Geometry, op overlayOp) orb.MultiPolygon {
+	return overlayNormalized(
+		normalizeMultiPolygon(toMultiPolygon(a)),
+		normalizeMultiPolygon(toMultiPolygon(b)),
+		op,
+	)
+}
+
+// overlayNormalized computes the operation on multi polygons that have
+// already been normalized, see normalizeMultiPolygon.
+func overlayNormalized(a, b orb.MultiPolygon, op overlayOp) orb.MultiPolygon {
+	inputs := [2]orb.MultiPolygon{a, b}
+
+	var segments []*overlaySegment
+	for owner, mp := range inputs {
+		for _, p := range mp {
+			for _, r := range p {
+				for i := 0; i < len(r)-1; i++ {
+					key, forward := segmentKey(r[i], r[i+1])
+
+					s := &overlaySegment{a: key[0], b: key[1]}
+					if forward {
+						s.fwd[owner] = 1
+					} else {
+						s.rev[owner] = 1
+					}
+					segments = append(segments, s)
+				}
+			}
+		}
+	}
+
+	// Collinear edges are split where they overlap first, so a stretch
+	// covered by several edges, like the two sides of a spike, becomes
+	// a single segment. Crossing edges are then intersected with it only once.
+	// Otherwise each of the overlapping edges can get a slightly different
+	// intersection point and the rings of the result have gaps.
+	nodeSegments(segments, true)
+	segments = splitSegments(segments)
+
+	nodeSegments(segments, false)
+	segments = splitSegments(segments)
+
+	// The winding numbers are computed from all the segments of an input,
+	// so members of a multi polygon that overlap each other are merged.
+	var indexes [2]*windingIndex
+	for i := range inputs {
+		var wes []windingEdge
+		for _, s := range segments {
+			if w := s.fwd[i] - s.rev[i]; w != 0 {
+				wes = append(wes, windingEdge{a: s.a, b: s.b, weight: w})
+			}
+		}
+		indexes[i] = newWindingIndexEdges(wes)
+	}
+
+	var result []overlayEdge
+	for _, s := range segments {
+		var left, right [2]bool
+		for i := range inputs {
+			l, r := indexes[i].sides(s, s.fwd[i]-s.rev[i])
+			left[i], right[i] = l > 0, r > 0
+		}
+
+		l := op.include(left[0], left[1])
+		r := op.include(right[0], right[1])
+		if l == r {
+			continue
+		}
+
+		if l {
+			result = append(result, overlayEdge{a: s.a, b: s.b})
+		} else {
+			result = append(result, overlayEdge{a: s.b, b: s.a})
+		}
+	}
+
+	return buildPolygons(result)
+}
+
+// windingIndex speeds up point in polygon tests by bucketing
+// the edges of normalized polygons by their y range.
+type windingIndex struct {
+	minY, step float64
+	buckets    [][]windingEdge
+}
+
+// windingEdge is a directed edge that counts weight times
+// towards the winding number.
+type windingEdge struct {
+	a, b   orb.Point
+	weight int
+}
+
+func newWindingIndex(mp orb.MultiPolygon) *windingIndex {
+	var edges []windingEdge
+	for _, p := range mp {
+		for _, r := range p {
+			for i := 0; i < len(r)-1; i++ {
+				edges = append(edges, windingEdge{a: r[i], b: r[i+1], weight: 1})
+			}
+		}
+	}
+
+	return newWindingIndexEdges(edges)
+}
+
+func newWindingIndexEdges(edges []windingEdge) *windingIndex {
+	if len(edges) == 0 {
+		return &windingIndex{}
+	}
+
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, e := range edges {
+		minY = math.Min(minY, math.Min(e.a[1], e.b[1]))
+		maxY = math.Max(maxY, math.Max(e.a[1], e.b[1]))
+	}
+
+	n := int(math.Sqrt(float64(len(edges)))) + 1
+	index := &windingIndex{
+		minY:    minY,
+		step:    (maxY - minY) / float64(n),
+		buckets: make([][]windingEdge, n),
+	}
+
+	for _, e := range edges {
+		lo := index.bucket(math.Min(e.a[1], e.b[1]))
+		hi := index.bucket(math.Max(e.a[1], e.b[1]))
+		for j := lo; j <= hi; j++ {
+			index.buckets[j] = append(index.buckets[j], e)
+		}
+	}
+
+	return index
+}
+
+func (wi *windingIndex) bucket(y float64) int {
+	if wi.step == 0 {
+		return 0
+	}
+
+	i := int((y - wi.minY) / wi.step)
+	if i < 0 {
+		return 0
+	}
+
+	if i >= len(wi.buckets) {
+		return len(wi.buckets) - 1
+	}
+
+	return i
+}
+
+// Contains returns true if the point has a positive winding number.
+// Points on the boundary may be in or out.
+func (wi *windingIndex) Contains(p orb.Point) bool {
+	return wi.winding(p, nil) > 0
+}
+
+// sides returns the winding numbers on the left and right of the segment,
+// directed from a to b, that counts weight times in the index.
+func (wi *windingIndex) sides(s *overlaySegment, weight int) (int, int) {
+	mid := orb.Point{(s.a[0] + s.b[0]) / 2, (s.a[1] + s.b[1]) / 2}
+
+	// The ray to +x does not cross the segment itself, so skipping it gives
+	// the winding on the +x side. Horizontal edges are counted as if the
+	// point was slightly above them, which is the left side.
+	w := wi.winding(mid, s)
+	if s.b[1] <= s.a[1] {
+		return w, w - weight
+	}
+
+	return w + weight, w
+}
+
+// winding returns the winding number of the point. The edge
+// of the skip segment, if not nil, is not counted.
+func (wi *windingIndex) winding(p orb.Point, skip *overlaySegment) int {
+	if len(wi.buckets) == 0 {
+		return 0
+	}
+
+	if p[1] < wi.minY || p[1] > wi.minY+wi.step*float64(len(wi.buckets)) {
+		return 0
+	}
+
+	wn := 0
+	for _, e := range wi.buckets[wi.bucket(p[1])] {
+		if skip != nil && e.a == skip.a && e.b == skip.b {
+			continue
+		}
+
+		if e.a[1] <= p[1] {
+			if e.b[1] > p[1] && segment.Orient(e.a, e.b, p) > 0 {
+				wn += e.weight
+			}
+		} else if e.b[1] <= p[1] && segment.Orient(e.a, e.b, p) < 0 {
+			wn -= e.weight
+		}
+	}
+
+	return wn
+}
+
+// toMultiPolygon returns the 2d parts of the geometry as a multi polygon.
+func toMultiPolygon(g orb.Geometry) orb.MultiPolygon {
+	if g == nil {
+		return nil
+	}
+
+	switch g := g.(type) {
+	case orb.Point, orb.MultiPoint, orb.LineString, orb.MultiLineString:
+		return nil
+	case orb.Ring:
+		return orb.MultiPolygon{{g}}
+	case orb.Polygon:
+		return orb.MultiPolygon{g}
+	case orb.MultiPolygon:
+		return g
+	case orb.Collection:
+		var mp orb.MultiPolygon
+		for _, c := range g {
+			mp = append(mp, toMultiPolygon(c)...)
+		}
+
+		return mp
+	case orb.Bound:
+		if g.IsEmpty() {
+			return nil
+		}
+		return orb.MultiPolygon{g.ToPolygon()}
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+// normalizeMultiPolygon returns a copy of the multi polygon with closed rings,
+// no repeated points, outer rings counter-clockwise and inner rings clockwise.
+// Degenerate rings are removed.
+func normalizeMultiPolygon(mp orb.MultiPolygon) orb.MultiPolygon {
+	var result orb.MultiPolygon
+	for _, p := range mp {
+		if len(p) == 0 {
+			continue
+		}
+
+		shell := normalizeRing(p[0], orb.CCW)
+		if shell == nil {
+			continue
+		}
+
+		np := orb.Polygon{shell}
+		for _, r := range p[1:] {
+			if h := normalizeRing(r, orb.CW); h != nil {
+				np = append(np, h)
+			}
+		}
+		result = append(result, np)
+	}
+
+	return result
+}
+
+func normalizeRing(r orb.Ring, o orb.Orientation) orb.Ring {
+	nr := make(orb.Ring, 0, len(r)+1)
+	for _, p := range r {
+		if len(nr) == 0 || nr[len(nr)-1] != p {
+			nr = append(nr, p)
+		}
+	}
+
+	if len(nr) > 1 && nr[0] != nr[len(nr)-1] {
+		nr = append(nr, nr[0])
+	}
+
+	if len(nr) < 4 {
+		return nil
+	}
+
+	ro := nr.Orientation()
+	if ro == 0 {
+		return nil
+	}
+
+	if ro != o {
+		nr.Reverse()
+	}
+
+	return nr
+}
+
+// nodeSegments finds all the places the segments touch each other and
+// records them as split points on the segments. If overlaps is true only
+// the parts where collinear segments overlap are recorded. Segments are sorted
+// by their min x so only segments with overlapping x ranges are compared.
+func nodeSegments(segments []*overlaySegment, overlaps bool) {
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].a[0] < segments[j].a[0]
+	})
+
+	for i, s1 := range segments {
+		b1 := orb.MultiPoint{s1.a, s1.b}.Bound()
+		for _, s2 := range segments[i+1:] {
+			if s2.a[0] > b1.Max[0] {
+				break
+			}
+
+			if !b1.Intersects(orb.MultiPoint{s2.a, s2.b}.Bound()) {
+				continue
+			}
+
+			p1, p2, n := segment.Intersection(s1.a, s1.b, s2.a, s2.b)
+			if overlaps && n < 2 {
+				continue
+			}
+
+			if n > 0 {
+				s1.addSplit(p1)
+				s2.addSplit(p1)
+			}
+			if n > 1 {
+				s1.addSplit(p2)
+				s2.addSplit(p2)
+			}
+		}
+	}
+
+	for _, s := range segments {
+		s.sortSplits()
+	}
+}
+
+func (s *overlaySegment) addSplit(p orb.Point) {
+	if p == s.a || p == s.b {
+		return
+	}
+
+	s.splits = append(s.splits, p)
+}
+
+func (s *overlaySegment) sortSplits() {
+	if len(s.splits) < 2 {
+		return
+	}
+
+	dx := s.b[0] - s.a[0]
+	dy := s.b[1] - s.a[1]
+	sort.Slice(s.splits, func(i, j int) bool {
+		ti := (s.splits[i][0]-s.a[0])*dx + (s.splits[i][1]-s.a[1])*dy
+		tj := (s.splits[j][0]-s.a[0])*dx + (s.splits[j][1]-s.a[1])*dy
+		return ti < tj
+	})
+
+	// remove duplicates
+	splits := s.splits[:1]
+	for _, p := range s.splits[1:] {
+		if p != splits[len(splits)-1] {
+			splits = append(splits, p)
+		}
+	}
+	s.splits = splits
+}
+
+// splitSegments breaks the segments at their split points. Pieces that
+// are the same are merged and their counts added up.
+func splitSegments(segments []*overlaySegment) []*overlaySegment {
+	index := make(map[[2]orb.Point]*overlaySegment, len(segments))
+
+	var result []*overlaySegment
+	for _, s := range segments {
+		prev := s.a
+		for _, p := range append(s.splits, s.b) {
+			key, forward := segmentKey(prev, p)
+
+			m := index[key]
+			if m == nil {
+				m = &overlaySegment{a: key[0], b: key[1]}
+				index[key] = m
+				result = append(result, m)
+			}
+
+			for i := range m.fwd {
+				if forward {
+					m.fwd[i] += s.fwd[i]
+					m.rev[i] += s.rev[i]
+				} else {
+					m.fwd[i] += s.rev[i]
+					m.rev[i] += s.fwd[i]
+				}
+			}
+			prev = p
+		}
+	}
+
+	return result
+}
+
+// segmentKey returns the points of the segment in a consistent order
+// and if that order matches the given direction.
+func segmentKey(a, b orb.Point) ([2]orb.Point, bool) {
+	if a[0] < b[0] || (a[0] == b[0] && a[1] < b[1]) {
+		return [2]orb.Point{a, b}, true
+	}
+
+	return [2]orb.Point{b, a}, false
+}
+
+// buildPolygons links the directed edges, with the interior on the left,
+// into rings and assigns the holes to their outer rings.
+func buildPolygons(edges []overlayEdge) orb.MultiPolygon {
+	outgoing := make(map[orb.Point][]int, len(edges))
+	for i, e := range edges {
+		outgoing[e.a] = append(outgoing[e.a], i)
+	}
+
+	var shells, holes []orb.Ring
+	used := make([]bool, len(edges))
+	for i := range edges {
+		if used[i] {
+			continue
+		}
+
+		start := edges[i].a
+		path := []orb.Point{start}
+
+		current := i
+		for current != -1 {
+			used[current] = true
+			e := edges[current]
+			path = append(path, e.b)
+
+			if e.b == start {
+				break
+			}
+
+			current = nextEdge(edges, outgoing[e.b], used, e)
+		}
+
+		// The path can end at a dangling edge with invalid input,
+		// only the closed loops in it are kept.
+		for _, r := range splitRing(path) {
+			r = removeCollinear(r)
+			if len(r) < 4 {
+				continue
+			}
+
+			switch r.Orientation() {
+			case orb.CCW:
+				shells = append(shells, r)
+			case orb.CW:
+				holes = append(holes, r)
+			}
+		}
+	}
+
+	if len(shells) == 0 {
+		return nil
+	}
+
+	result := make(orb.MultiPolygon, len(shells))
+	areas := make([]float64, len(shells))
+	for i, s := range shells {
+		result[i] = orb.Polygon{s}
+		_, areas[i] = ringCentroidArea(s)
+	}
+
+	for _, h := range holes {
+		mid := orb.Point{(h[0][0] + h[1][0]) / 2, (h[0][1] + h[1][1]) / 2}
+
+		best := -1
+		for i, s := range shells {
+			if best != -1 && areas[i] >= areas[best] {
+				continue
+			}
+
+			if RingContains(s, mid) {
+				best = i
+			}
+		}
+
+		if best != -1 {
+			result[best] = append(result[best], h)
+		}
+	}
+
+	return result
+}
+
+// nextEdge returns the unused outgoing edge that makes the sharpest
+// left turn coming from the given edge.
+func nextEdge(edges []overlayEdge, candidates []int, used []bool, from overlayEdge) int {
+	back := math.Atan2(from.a[1]-from.b[1], from.a[0]-from.b[0])
+
+	best := -1
+	bestAngle := 0.0
+	for _, c := range candidates {
+		if used[c] {
+			continue
+		}
+
+		e := edges[c]
+		angle := back - math.Atan2(e.b[1]-e.a[1], e.b[0]-e.a[0])
+		for angle <= 0 {
+			angle += 2 * math.Pi
+		}
+		for angle > 2*math.Pi {
+			angle -= 2 * math.Pi
+		}
+
+		if best == -1 || angle < bestAngle {
+			best = c
+			bestAngle = angle
+		}
+	}
+
+	return best
+}
+
+// splitRing breaks up a path at the points it visits more than once.
+// If the path is not closed the points after the last loop are dropped.
+func splitRing(path []orb.Point) []orb.Ring {
+	var result []orb.Ring
+
+	stack := make([]orb.Point, 0, len(path))
+	index := make(map[orb.Point]int, len(path))
+	for _, p := range path {
+		j, ok := index[p]
+		if !ok {
+			index[p] = len(stack)
+			stack = append(stack, p)
+			continue
+		}
+
+		r := make(orb.Ring, 0, len(stack)-j+1)
+		r = append(r, stack[j:]...)
+		r = append(r, p)
+		result = append(result, r)
+
+		for _, q := range stack[j+1:] {
+			delete(index, q)
+		}
+		stack = stack[:j+1]
+	}
+
+	return result
+}
+
+// removeCollinear removes the points of the closed ring that are in
+// the middle of a straight line.
+func removeCollinear(r orb.Ring) orb.Ring {
+	if len(r) < 4 {
+		return r
+	}
+
+	pts := r[:len(r)-1]
+	result := make(orb.Ring, 0, len(r))
+	for i, p := range pts {
+		prev := pts[(i+len(pts)-1)%len(pts)]
+		next := pts[(i+1)%len(pts)]
+
+		if segment.Orient(prev, p, next) == 0 {
+			continue
+		}
+
+		result = append(result, p)
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return append(result, result[0])
+}
@@ -0,0 +1,381 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestOverlay(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		Union(g, g)
+		Intersection(g, orb.Bound{Max: orb.Point{1, 1}})
+		Difference(orb.Bound{Max: orb.Point{1, 1}}, g)
+		SymDifference(g, nil)
+	}
+}
+
+func TestOverlay_overlappingSquares(t *testing.T) {
+	a := orb.Polygon{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}}
+	b := orb.Polygon{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}}
+
+	cases := []struct {
+		name   string
+		f      func(a, b orb.Geometry) orb.MultiPolygon
+		count  int
+		area   float64
+		points int
+	}{
+		{
+			name:   "union",
+			f:      Union,
+			count:  1,
+			area:   7,
+			points: 9,
+		},
+		{
+			name:   "intersection",
+			f:      Intersection,
+			count:  1,
+			area:   1,
+			points: 5,
+		},
+		{
+			name:   "difference",
+			f:      Difference,
+			count:  1,
+			area:   3,
+			points: 7,
+		},
+		{
+			name:   "sym difference",
+			f:      SymDifference,
+			count:  2,
+			area:   6,
+			points: 14,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := tc.f(a, b)
+			if len(result) != tc.count {
+				t.Fatalf("incorrect number of polygons: %d != %d", len(result), tc.count)
+			}
+
+			if a := Area(result); math.Abs(a-tc.area) > 1e-10 {
+				t.Errorf("incorrect area: %v != %v", a, tc.area)
+			}
+
+			points := 0
+			for _, p := range result {
+				for _, r := range p {
+					points += len(r)
+				}
+			}
+			if points != tc.points {
+				t.Errorf("incorrect number of points: %d != %d", points, tc.points)
+			}
+
+			expectValidOrientation(t, result)
+		})
+	}
+}
+
+func TestOverlay_sharedEdge(t *testing.T) {
+	a := orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}
+	b := orb.Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}}
+
+	result := Union(a, b)
+	expected := orb.MultiPolygon{{{{0, 0}, {2, 0}, {2, 1}, {0, 1}, {0, 0}}}}
+	if !equalIgnoreStart(result, expected) {
+		t.Errorf("incorrect union: %v", result)
+	}
+
+	if result := Intersection(a, b); len(result) != 0 {
+		t.Errorf("should be empty: %v", result)
+	}
+
+	if result := Difference(a, b); !equalIgnoreStart(result, orb.MultiPolygon{a}) {
+		t.Errorf("incorrect difference: %v", result)
+	}
+}
+
+func TestOverlay_holes(t *testing.T) {
+	outer := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	inner := orb.Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}}
+
+	t.Run("difference creates hole", func(t *testing.T) {
+		result := Difference(outer, inner)
+		if len(result) != 1 || len(result[0]) != 2 {
+			t.Fatalf("should be one polygon with a hole: %v", result)
+		}
+
+		if a := Area(result); a != 64 {
+			t.Errorf("incorrect area: %v", a)
+		}
+		expectValidOrientation(t, result)
+	})
+
+	t.Run("union fills hole", func(t *testing.T) {
+		donut := orb.Polygon{outer[0], inner[0]}
+		result := Union(donut, inner)
+		if !equalIgnoreStart(result, orb.MultiPolygon{outer}) {
+			t.Errorf("incorrect union: %v", result)
+		}
+	})
+
+	t.Run("intersection with hole", func(t *testing.T) {
+		donut := orb.Polygon{outer[0], inner[0]}
+		result := Intersection(donut, orb.Bound{Min: orb.Point{-1, 4}, Max: orb.Point{11, 6}})
+		if len(result) != 2 {
+			t.Fatalf("should be 2 polygons: %v", result)
+		}
+
+		if a := Area(result); a != 8 {
+			t.Errorf("incorrect area: %v", a)
+		}
+	})
+
+	t.Run("hole touching shell", func(t *testing.T) {
+		notch := orb.Polygon{{{5, 0}, {6, 2}, {4, 2}, {5, 0}}}
+		result := Difference(outer, notch)
+		if len(result) != 1 || len(result[0]) != 2 {
+			t.Fatalf("should be one polygon with a hole: %v", result)
+		}
+
+		if a := Area(result); a != 98 {
+			t.Errorf("incorrect area: %v", a)
+		}
+		expectValidOrientation(t, result)
+	})
+}
+
+func TestOverlay_overlappingMembers(t *testing.T) {
+	// members of the same input that overlap are valid input, e.g. service areas
+	sq1 := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	sq2 := orb.Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}}
+
+	cases := []struct {
+		name  string
+		input orb.Geometry
+	}{
+		{name: "collection", input: orb.Collection{sq1, sq2}},
+		{name: "multi polygon", input: orb.MultiPolygon{sq1, sq2}},
+		{name: "clockwise", input: orb.MultiPolygon{sq1, {sq2[0].Clone()}}},
+	}
+	cases[2].input.(orb.MultiPolygon)[1][0].Reverse()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Union(tc.input, nil)
+			if len(result) != 1 || len(result[0]) != 1 {
+				t.Fatalf("should be one shell: %v", result)
+			}
+
+			if a := Area(result); a != 175 {
+				t.Errorf("incorrect union area: %v", a)
+			}
+			expectValidOrientation(t, result)
+
+			result = Intersection(tc.input, orb.Bound{Min: orb.Point{2, 2}, Max: orb.Point{12, 12}})
+			if len(result) != 1 || len(result[0]) != 1 {
+				t.Fatalf("should be one shell: %v", result)
+			}
+
+			if a := Area(result); a != 100-2*3*2 {
+				t.Errorf("incorrect intersection area: %v", a)
+			}
+
+			result = Difference(orb.Bound{Min: orb.Point{-5, -5}, Max: orb.Point{20, 20}}, tc.input)
+			if a := Area(result); a != 625-175 {
+				t.Errorf("incorrect difference area: %v", a)
+			}
+		})
+	}
+
+	t.Run("overlapping hole", func(t *testing.T) {
+		// the second member covers part of the hole of the first
+		donut := orb.Polygon{
+			{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+			{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}},
+		}
+		patch := orb.Polygon{{{1, 4}, {9, 4}, {9, 6}, {1, 6}, {1, 4}}}
+
+		result := Union(orb.Collection{donut, patch}, nil)
+		if a := Area(result); a != 64+12 {
+			t.Errorf("incorrect area: %v", a)
+		}
+
+		if len(result) != 1 || len(result[0]) != 3 {
+			t.Errorf("should be one polygon with 2 holes: %v", result)
+		}
+		expectValidOrientation(t, result)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		result := Union(orb.Collection{sq1, sq1}, nil)
+		if !equalIgnoreStart(result, orb.MultiPolygon{sq1}) {
+			t.Errorf("incorrect union: %v", result)
+		}
+	})
+
+	t.Run("shared edge", func(t *testing.T) {
+		right := orb.Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 0}}}
+		result := Union(orb.Collection{sq1, right}, nil)
+		expected := orb.MultiPolygon{{{{0, 0}, {20, 0}, {20, 10}, {0, 10}, {0, 0}}}}
+		if !equalIgnoreStart(result, expected) {
+			t.Errorf("incorrect union: %v", result)
+		}
+	})
+}
+
+func TestOverlay_spike(t *testing.T) {
+	// the two sides of a spike overlap, a crossing edge should
+	// split them at the same point
+	cases := []struct {
+		name string
+		a, b orb.Polygon
+	}{
+		{
+			name: "boundary",
+			a:    orb.Polygon{{{3, 0}, {2, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 0}, {1, 0}, {3, 0}}},
+			b:    orb.Polygon{{{0.5, -0.5}, {1.5, 5}, {3.5, -2.5}, {0.5, -0.5}}},
+		},
+		{
+			name: "interior",
+			a:    orb.Polygon{{{0, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 3}, {1, 3}, {3, 3}, {2, 3}, {0, 3}, {0, 0}}},
+			b:    orb.Polygon{{{2, 7}, {0, 0.5}, {3.5, 2.5}, {2, 7}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			areaA := Area(Union(tc.a, nil))
+			areaB := Area(Union(tc.b, nil))
+			inter := Area(Intersection(tc.a, tc.b))
+			if inter == 0 {
+				t.Fatalf("should overlap")
+			}
+
+			result := Union(tc.a, tc.b)
+			if len(result) != 1 {
+				t.Fatalf("should be one polygon: %v", result)
+			}
+
+			if a := Area(result); math.Abs(a-(areaA+areaB-inter)) > 1e-9 {
+				t.Errorf("incorrect union area: %v != %v", a, areaA+areaB-inter)
+			}
+			expectValidOrientation(t, result)
+
+			if a := Area(Difference(tc.a, tc.b)); math.Abs(a-(areaA-inter)) > 1e-9 {
+				t.Errorf("incorrect difference area: %v != %v", a, areaA-inter)
+			}
+		})
+	}
+}
+
+func TestOverlay_disjoint(t *testing.T) {
+	a := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}}
+	b := orb.Bound{Min: orb.Point{2, 2}, Max: orb.Point{3, 3}}
+
+	if result := Union(a, b); len(result) != 2 {
+		t.Errorf("should have 2 polygons: %v", result)
+	}
+
+	if result := Intersection(a, b); result != nil {
+		t.Errorf("should be nil: %v", result)
+	}
+
+	if result := Difference(a, b); !equalIgnoreStart(result, orb.MultiPolygon{a.ToPolygon()}) {
+		t.Errorf("should be the first input: %v", result)
+	}
+}
+
+func TestOverlay_orientation(t *testing.T) {
+	// clockwise input should be handled
+	a := orb.Ring{{0, 0}, {0, 2}, {2, 2}, {2, 0}, {0, 0}}
+	b := orb.Ring{{1, 0}, {1, 2}, {3, 2}, {3, 0}, {1, 0}}
+
+	result := Union(a, b)
+	expected := orb.MultiPolygon{{{{0, 0}, {3, 0}, {3, 2}, {0, 2}, {0, 0}}}}
+	if !equalIgnoreStart(result, expected) {
+		t.Errorf("incorrect union: %v", result)
+	}
+
+	// input should not be modified
+	if a[1] != (orb.Point{0, 2}) {
+		t.Errorf("input was modified: %v", a)
+	}
+}
+
+func expectValidOrientation(t testing.TB, mp orb.MultiPolygon) {
+	t.Helper()
+
+	for _, p := range mp {
+		for i, r := range p {
+			if !r.Closed() {
+				t.Errorf("ring not closed: %v", r)
+			}
+
+			expected := orb.CW
+			if i == 0 {
+				expected = orb.CCW
+			}
+
+			if o := r.Orientation(); o != expected {
+				t.Errorf("incorrect orientation for ring %d: %v", i, o)
+			}
+		}
+	}
+}
+
+// equalIgnoreStart compares multi polygons where the rings
+// can start at any point.
+func equalIgnoreStart(mp1, mp2 orb.MultiPolygon) bool {
+	if len(mp1) != len(mp2) {
+		return false
+	}
+
+	for i := range mp1 {
+		if len(mp1[i]) != len(mp2[i]) {
+			return false
+		}
+
+		for j := range mp1[i] {
+			if !ringEqualIgnoreStart(mp1[i][j], mp2[i][j]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func ringEqualIgnoreStart(r1, r2 orb.Ring) bool {
+	if len(r1) != len(r2) {
+		return false
+	}
+
+	if len(r1) == 0 {
+		return true
+	}
+
+	n := len(r1) - 1
+	for offset := 0; offset < n; offset++ {
+		match := true
+		for i := 0; i < n; i++ {
+			if r1[(i+offset)%n] != r2[i] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return true
+		}
+	}
+
+	return false
+}
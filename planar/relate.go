@@ -0,0 +1,520 @@
+package planar
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// Relate returns the DE-9IM intersection matrix of the two geometries as
+// a 9 character string. The rows are the interior, boundary and exterior of a,
+// the columns the same for b. Each entry is the dimension of the intersection,
+// 0, 1 or 2, or F if they don't intersect. For example, two squares sharing an
+// edge are "FF2F11212".
+//
+// The boundary of lines are the end points that appear an odd number of times,
+// so closed lines have no boundary. Points have no boundary. The 2d parts of
+// a collection are merged before comparing.
+func Relate(a, b orb.Geometry) string {
+	r := relate(a, b)
+	return r.matrix.String()
+}
+
+// Intersects returns true if the geometries have at least one point in common.
+func Intersects(a, b orb.Geometry) bool {
+	return !Disjoint(a, b)
+}
+
+// Disjoint returns true if the geometries have no points in common.
+func Disjoint(a, b orb.Geometry) bool {
+	m := relate(a, b).matrix
+	return m[locInterior][locInterior] < 0 && m[locInterior][locBoundary] < 0 &&
+		m[locBoundary][locInterior] < 0 && m[locBoundary][locBoundary] < 0
+}
+
+// Contains returns true if no points of b are outside of a and at least
+// one point of the interior of b is in the interior of a. A polygon does not
+// contain its boundary, see Covers.
+func Contains(a, b orb.Geometry) bool {
+	m := relate(a, b).matrix
+	return m[locInterior][locInterior] >= 0 &&
+		m[locExterior][locInterior] < 0 && m[locExterior][locBoundary] < 0
+}
+
+// Within returns true if a is within b, ie. b contains a.
+func Within(a, b orb.Geometry) bool {
+	return Contains(b, a)
+}
+
+// Covers returns true if no points of b are outside of a.
+// Unlike Contains, a polygon covers its boundary.
+func Covers(a, b orb.Geometry) bool {
+	m := relate(a, b).matrix
+	if m[locExterior][locInterior] >= 0 || m[locExterior][locBoundary] >= 0 {
+		return false
+	}
+
+	return m[locInterior][locInterior] >= 0 || m[locInterior][locBoundary] >= 0 ||
+		m[locBoundary][locInterior] >= 0 || m[locBoundary][locBoundary] >= 0
+}
+
+// Touches returns true if the geometries have at least one point in common,
+// but their interiors do not intersect. Always false if both are points.
+func Touches(a, b orb.Geometry) bool {
+	r := relate(a, b)
+	if r.dims[0] == 0 && r.dims[1] == 0 {
+		return false
+	}
+
+	m := r.matrix
+	return m[locInterior][locInterior] < 0 && (m[locInterior][locBoundary] >= 0 ||
+		m[locBoundary][locInterior] >= 0 || m[locBoundary][locBoundary] >= 0)
+}
+
+// Crosses returns true if the interiors of the geometries intersect with a lower
+// dimension than the higher dimension geometry, and part of the lower
+// dimension geometry is outside the other. For example, a line that goes in
+// and out of a polygon, or two lines that cross at a point.
+func Crosses(a, b orb.Geometry) bool {
+	r := relate(a, b)
+	m := r.matrix
+
+	switch da, db := r.dims[0], r.dims[1]; {
+	case da < db:
+		return m[locInterior][locInterior] >= 0 && m[locInterior][locExterior] >= 0
+	case da > db:
+		return m[locInterior][locInterior] >= 0 && m[locExterior][locInterior] >= 0
+	case da == 1:
+		return m[locInterior][locInterior] == 0
+	}
+
+	return false
+}
+
+// Overlaps returns true if the geometries have the same dimension, their
+// interiors intersect with that dimension and each has a part outside the other.
+func Overlaps(a, b orb.Geometry) bool {
+	r := relate(a, b)
+	if r.dims[0] != r.dims[1] || r.dims[0] < 0 {
+		return false
+	}
+
+	m := r.matrix
+	return m[locInterior][locInterior] == r.dims[0] &&
+		m[locInterior][locExterior] >= 0 && m[locExterior][locInterior] >= 0
+}
+
+type location int
+
+const (
+	locInterior location = iota
+	locBoundary
+	locExterior
+	locUnknown
+)
+
+// relateMatrix is the DE-9IM matrix indexed by the location in a and b.
+// Entries are the dimension of the intersection or -1 for none.
+type relateMatrix [3][3]int
+
+func (m *relateMatrix) add(a, b location, dim int) {
+	if dim > m[a][b] {
+		m[a][b] = dim
+	}
+}
+
+func (m relateMatrix) String() string {
+	buf := make([]byte, 0, 9)
+	for _, row := range m {
+		for _, d := range row {
+			if d < 0 {
+				buf = append(buf, 'F')
+			} else {
+				buf = append(buf, byte('0'+d))
+			}
+		}
+	}
+
+	return string(buf)
+}
+
+type relation struct {
+	matrix relateMatrix
+	dims   [2]int
+}
+
+type segmentKind int
+
+const (
+	kindPoint segmentKind = iota
+	kindLine
+	kindRing
+)
+
+// relateSegment is a segment of one of the inputs. Points are
+// included as zero length segments so they can be found the same way.
+type relateSegment struct {
+	a, b     orb.Point
+	kind     segmentKind
+	owner    int
+	splits   []orb.Point
+	overlaps []relateOverlap
+}
+
+// relateOverlap is the part of a segment that is collinear
+// with a segment of the other geometry.
+type relateOverlap struct {
+	a, b orb.Point
+	kind segmentKind
+}
+
+// relateGeometry is one of the inputs split by dimension.
+type relateGeometry struct {
+	dim      int
+	points   map[orb.Point]bool
+	boundary map[orb.Point]bool
+	area     orb.MultiPolygon
+	index    *windingIndex
+	segments []*relateSegment
+}
+
+func relate(a, b orb.Geometry) relation {
+	g := [2]*relateGeometry{newRelateGeometry(a, 0), newRelateGeometry(b, 1)}
+
+	r := relation{dims: [2]int{g[0].dim, g[1].dim}}
+	for i := range r.matrix {
+		for j := range r.matrix[i] {
+			r.matrix[i][j] = -1
+		}
+	}
+	r.matrix[locExterior][locExterior] = 2
+
+	if g[0].dim < 0 || g[1].dim < 0 || !g[0].bound().Intersects(g[1].bound()) {
+		// nothing in common, so everything is in the exterior of the other
+		r.matrix.add(locInterior, locExterior, g[0].dim)
+		r.matrix.add(locBoundary, locExterior, g[0].boundaryDim())
+		r.matrix.add(locExterior, locInterior, g[1].dim)
+		r.matrix.add(locExterior, locBoundary, g[1].boundaryDim())
+		return r
+	}
+
+	nodes := make(map[orb.Point]*[2]location)
+	set := func(p orb.Point, owner int, loc location) {
+		n := nodes[p]
+		if n == nil {
+			n = &[2]location{locUnknown, locUnknown}
+			nodes[p] = n
+		}
+		n[owner] = loc
+	}
+
+	for i := range g {
+		for _, s := range g[i].segments {
+			set(s.a, i, g[i].locateOn(s.a, s.kind))
+			set(s.b, i, g[i].locateOn(s.b, s.kind))
+		}
+	}
+
+	// find where the segments of a and b touch
+	segments := append(append([]*relateSegment{}, g[0].segments...), g[1].segments...)
+	sort.Slice(segments, func(i, j int) bool {
+		return math.Min(segments[i].a[0], segments[i].b[0]) < math.Min(segments[j].a[0], segments[j].b[0])
+	})
+
+	for i, s1 := range segments {
+		b1 := orb.MultiPoint{s1.a, s1.b}.Bound()
+		for _, s2 := range segments[i+1:] {
+			if math.Min(s2.a[0], s2.b[0]) > b1.Max[0] {
+				break
+			}
+
+			if s1.owner == s2.owner || (s1.kind == kindPoint && s2.kind == kindPoint) {
+				continue
+			}
+
+			if !b1.Intersects(orb.MultiPoint{s2.a, s2.b}.Bound()) {
+				continue
+			}
+
+			p1, p2, n := intersection(s1.a, s1.b, s2.a, s2.b)
+			found := [2]orb.Point{p1, p2}
+			for _, p := range found[:n] {
+				s1.addSplit(p)
+				s2.addSplit(p)
+				set(p, s1.owner, g[s1.owner].locateOn(p, s1.kind))
+				set(p, s2.owner, g[s2.owner].locateOn(p, s2.kind))
+			}
+
+			if n == 2 {
+				s1.overlaps = append(s1.overlaps, relateOverlap{a: p1, b: p2, kind: s2.kind})
+				s2.overlaps = append(s2.overlaps, relateOverlap{a: p1, b: p2, kind: s1.kind})
+			}
+		}
+	}
+
+	// points
+	for p, n := range nodes {
+		for i := range n {
+			if n[i] == locUnknown {
+				n[i] = g[i].locateOff(p)
+			}
+		}
+		r.matrix.add(n[0], n[1], 0)
+	}
+
+	// lines, each piece between the nodes is entirely in, on or out of the other
+	for _, s := range segments {
+		if s.kind == kindPoint {
+			continue
+		}
+
+		s.sortSplits()
+		other := 1 - s.owner
+
+		prev := s.a
+		for _, p := range append(s.splits, s.b) {
+			mid := orb.Point{(prev[0] + p[0]) / 2, (prev[1] + p[1]) / 2}
+			prev = p
+
+			var loc [2]location
+			loc[s.owner] = locInterior
+			if s.kind == kindRing {
+				loc[s.owner] = locBoundary
+			}
+
+			loc[other] = g[other].locateOff(mid)
+			for _, o := range s.overlaps {
+				if s.within(o, mid) {
+					loc[other] = g[other].locateOn(mid, o.kind)
+					break
+				}
+			}
+
+			r.matrix.add(loc[0], loc[1], 1)
+		}
+	}
+
+	// areas
+	switch {
+	case len(g[0].area) > 0 && len(g[1].area) > 0:
+		if len(overlayNormalized(g[0].area, g[1].area, opIntersection)) > 0 {
+			r.matrix.add(locInterior, locInterior, 2)
+		}
+
+		if len(overlayNormalized(g[0].area, g[1].area, opDifference)) > 0 {
+			r.matrix.add(locInterior, locExterior, 2)
+		}
+
+		if len(overlayNormalized(g[1].area, g[0].area, opDifference)) > 0 {
+			r.matrix.add(locExterior, locInterior, 2)
+		}
+	case len(g[0].area) > 0:
+		r.matrix.add(locInterior, locExterior, 2)
+	case len(g[1].area) > 0:
+		r.matrix.add(locExterior, locInterior, 2)
+	}
+
+	return r
+}
+
+func newRelateGeometry(g orb.Geometry, owner int) *relateGeometry {
+	rg := &relateGeometry{
+		dim:      -1,
+		points:   make(map[orb.Point]bool),
+		boundary: make(map[orb.Point]bool),
+	}
+
+	var (
+		lines []orb.LineString
+		areas []orb.MultiPolygon
+	)
+
+	var walk func(g orb.Geometry)
+	walk = func(g orb.Geometry) {
+		if g == nil {
+			return
+		}
+
+		switch g := g.(type) {
+		case orb.Point:
+			rg.points[g] = true
+		case orb.MultiPoint:
+			for _, p := range g {
+				rg.points[p] = true
+			}
+		case orb.LineString:
+			lines = append(lines, g)
+		case orb.MultiLineString:
+			lines = append(lines, g...)
+		case orb.Ring, orb.Polygon, orb.MultiPolygon:
+			areas = append(areas, normalizeMultiPolygon(toMultiPolygon(g)))
+		case orb.Collection:
+			for _, c := range g {
+				walk(c)
+			}
+		case orb.Bound:
+			if g.IsEmpty() {
+				return
+			}
+
+			if g.Min[0] == g.Max[0] || g.Min[1] == g.Max[1] {
+				// degenerate bound is a line or point
+				lines = append(lines, orb.LineString{g.Min, g.Max})
+				return
+			}
+
+			areas = append(areas, normalizeMultiPolygon(toMultiPolygon(g)))
+		default:
+			panic(fmt.Sprintf("geometry type not supported: %T", g))
+		}
+	}
+	walk(g)
+
+	for _, a := range areas {
+		if rg.area == nil {
+			rg.area = a
+		} else if len(a) > 0 {
+			rg.area = overlayNormalized(rg.area, a, opUnion)
+		}
+	}
+
+	if len(rg.area) > 0 {
+		rg.dim = 2
+		rg.index = newWindingIndex(rg.area)
+		for _, p := range rg.area {
+			for _, r := range p {
+				for i := 0; i < len(r)-1; i++ {
+					rg.segments = append(rg.segments, &relateSegment{a: r[i], b: r[i+1], kind: kindRing, owner: owner})
+				}
+			}
+		}
+	}
+
+	// end points that appear an odd number of times are the boundary
+	for _, ls := range lines {
+		ls = removeRepeated(ls)
+		if len(ls) == 0 {
+			continue
+		}
+
+		if len(ls) == 1 {
+			rg.points[ls[0]] = true
+			continue
+		}
+
+		if rg.dim < 1 {
+			rg.dim = 1
+		}
+
+		for _, p := range [2]orb.Point{ls[0], ls[len(ls)-1]} {
+			if rg.boundary[p] {
+				delete(rg.boundary, p)
+			} else {
+				rg.boundary[p] = true
+			}
+		}
+
+		for i := 0; i < len(ls)-1; i++ {
+			rg.segments = append(rg.segments, &relateSegment{a: ls[i], b: ls[i+1], kind: kindLine, owner: owner})
+		}
+	}
+
+	for p := range rg.points {
+		if rg.dim < 0 {
+			rg.dim = 0
+		}
+
+		rg.segments = append(rg.segments, &relateSegment{a: p, b: p, kind: kindPoint, owner: owner})
+	}
+
+	return rg
+}
+
+func (rg *relateGeometry) bound() orb.Bound {
+	b := orb.Bound{Min: orb.Point{math.Inf(1), math.Inf(1)}, Max: orb.Point{math.Inf(-1), math.Inf(-1)}}
+	for _, s := range rg.segments {
+		b = b.Extend(s.a).Extend(s.b)
+	}
+
+	return b
+}
+
+// boundaryDim returns the dimension of the boundary of the geometry.
+func (rg *relateGeometry) boundaryDim() int {
+	switch {
+	case rg.dim == 2:
+		return 1
+	case len(rg.boundary) > 0:
+		return 0
+	}
+
+	return -1
+}
+
+// locateOn returns the location of a point known to be on a segment
+// of the given kind of this geometry.
+func (rg *relateGeometry) locateOn(p orb.Point, kind segmentKind) location {
+	switch kind {
+	case kindRing:
+		return locBoundary
+	case kindLine:
+		if rg.boundary[p] && (rg.index == nil || !rg.index.Contains(p)) {
+			return locBoundary
+		}
+	}
+
+	return locInterior
+}
+
+// locateOff returns the location of a point known to not be on
+// any segment of this geometry.
+func (rg *relateGeometry) locateOff(p orb.Point) location {
+	if rg.points[p] || (rg.index != nil && rg.index.Contains(p)) {
+		return locInterior
+	}
+
+	return locExterior
+}
+
+func (s *relateSegment) addSplit(p orb.Point) {
+	if p == s.a || p == s.b {
+		return
+	}
+
+	s.splits = append(s.splits, p)
+}
+
+func (s *relateSegment) sortSplits() {
+	if len(s.splits) < 2 {
+		return
+	}
+
+	sort.Slice(s.splits, func(i, j int) bool {
+		return s.project(s.splits[i]) < s.project(s.splits[j])
+	})
+
+	// remove duplicates
+	splits := s.splits[:1]
+	for _, p := range s.splits[1:] {
+		if p != splits[len(splits)-1] {
+			splits = append(splits, p)
+		}
+	}
+	s.splits = splits
+}
+
+// project returns the position of the point along the segment,
+// scaled by the length of the segment squared.
+func (s *relateSegment) project(p orb.Point) float64 {
+	return (p[0]-s.a[0])*(s.b[0]-s.a[0]) + (p[1]-s.a[1])*(s.b[1]-s.a[1])
+}
+
+// within checks if the point, on the segment, is within the overlap.
+func (s *relateSegment) within(o relateOverlap, p orb.Point) bool {
+	t1, t2 := s.project(o.a), s.project(o.b)
+	t := s.project(p)
+
+	return math.Min(t1, t2) < t && t < math.Max(t1, t2)
+}
@@ -0,0 +1,302 @@
+package planar
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestRelate(t *testing.T) {
+	for _, a := range orb.AllGeometries {
+		for _, b := range orb.AllGeometries {
+			Relate(a, b)
+		}
+	}
+
+	square := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{2, 2}}
+	donut := orb.Polygon{
+		{{0, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 0}},
+		{{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}},
+	}
+
+	cases := []struct {
+		name   string
+		a, b   orb.Geometry
+		result string
+	}{
+		{
+			name:   "same point",
+			a:      orb.Point{1, 1},
+			b:      orb.Point{1, 1},
+			result: "0FFFFFFF2",
+		},
+		{
+			name:   "different points",
+			a:      orb.Point{1, 1},
+			b:      orb.Point{2, 1},
+			result: "FF0FFF0F2",
+		},
+		{
+			name:   "multi points overlap",
+			a:      orb.MultiPoint{{1, 1}, {2, 2}},
+			b:      orb.MultiPoint{{2, 2}, {3, 3}},
+			result: "0F0FFF0F2",
+		},
+		{
+			name:   "point in polygon",
+			a:      orb.Point{1, 1},
+			b:      square,
+			result: "0FFFFF212",
+		},
+		{
+			name:   "point on polygon boundary",
+			a:      orb.Point{0, 1},
+			b:      square,
+			result: "F0FFFF212",
+		},
+		{
+			name:   "point in hole",
+			a:      orb.Point{3, 3},
+			b:      donut,
+			result: "FF0FFF212",
+		},
+		{
+			name:   "point on line end",
+			a:      orb.Point{0, 0},
+			b:      orb.LineString{{0, 0}, {2, 0}},
+			result: "F0FFFF102",
+		},
+		{
+			name:   "point on line",
+			a:      orb.Point{1, 0},
+			b:      orb.LineString{{0, 0}, {2, 0}},
+			result: "0FFFFF102",
+		},
+		{
+			name:   "point on closed line",
+			a:      orb.Point{0, 0},
+			b:      orb.LineString{{0, 0}, {2, 0}, {2, 2}, {0, 0}},
+			result: "0FFFFF1F2",
+		},
+		{
+			name:   "crossing lines",
+			a:      orb.LineString{{0, 0}, {2, 2}},
+			b:      orb.LineString{{0, 2}, {2, 0}},
+			result: "0F1FF0102",
+		},
+		{
+			name:   "lines touching at ends",
+			a:      orb.LineString{{0, 0}, {1, 0}},
+			b:      orb.LineString{{1, 0}, {2, 0}},
+			result: "FF1F00102",
+		},
+		{
+			name:   "overlapping lines",
+			a:      orb.LineString{{0, 0}, {2, 0}},
+			b:      orb.LineString{{1, 0}, {3, 0}},
+			result: "1010F0102",
+		},
+		{
+			name:   "equal lines",
+			a:      orb.LineString{{0, 0}, {1, 0}, {2, 0}},
+			b:      orb.LineString{{2, 0}, {0, 0}},
+			result: "1FFF0FFF2",
+		},
+		{
+			name:   "line in polygon",
+			a:      orb.LineString{{0.5, 0.5}, {1.5, 0.5}},
+			b:      square,
+			result: "1FF0FF212",
+		},
+		{
+			name:   "line crossing polygon",
+			a:      orb.LineString{{-1, 1}, {3, 1}},
+			b:      square,
+			result: "101FF0212",
+		},
+		{
+			name:   "line along polygon edge",
+			a:      orb.LineString{{0, 0}, {2, 0}},
+			b:      square,
+			result: "F1FF0F212",
+		},
+		{
+			name:   "polygons sharing an edge",
+			a:      orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}},
+			b:      orb.Bound{Min: orb.Point{1, 0}, Max: orb.Point{2, 1}},
+			result: "FF2F11212",
+		},
+		{
+			name:   "polygons touching at a corner",
+			a:      orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}},
+			b:      orb.Bound{Min: orb.Point{1, 1}, Max: orb.Point{2, 2}},
+			result: "FF2F01212",
+		},
+		{
+			name:   "overlapping polygons",
+			a:      square,
+			b:      orb.Bound{Min: orb.Point{1, 1}, Max: orb.Point{3, 3}},
+			result: "212101212",
+		},
+		{
+			name:   "polygon in polygon",
+			a:      orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{4, 4}},
+			b:      orb.Bound{Min: orb.Point{1, 1}, Max: orb.Point{2, 2}},
+			result: "212FF1FF2",
+		},
+		{
+			name:   "polygon in hole",
+			a:      orb.Bound{Min: orb.Point{2, 2}, Max: orb.Point{4, 4}},
+			b:      donut,
+			result: "FF2F1F212",
+		},
+		{
+			name:   "equal polygons",
+			a:      square,
+			b:      orb.Ring{{2, 2}, {0, 2}, {0, 0}, {2, 0}, {2, 2}},
+			result: "2FFF1FFF2",
+		},
+		{
+			name:   "disjoint polygons",
+			a:      square,
+			b:      orb.Bound{Min: orb.Point{5, 5}, Max: orb.Point{6, 6}},
+			result: "FF2FF1212",
+		},
+		{
+			name:   "empty",
+			a:      orb.MultiPolygon{},
+			b:      square,
+			result: "FFFFFF212",
+		},
+		{
+			name:   "collection",
+			a:      orb.Collection{orb.Point{5, 5}, square},
+			b:      orb.Point{5, 5},
+			result: "0F2FF1FF2",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if v := Relate(tc.a, tc.b); v != tc.result {
+				t.Errorf("incorrect result: %v != %v", v, tc.result)
+			}
+
+			// the transpose
+			expected := make([]byte, 9)
+			for i := 0; i < 3; i++ {
+				for j := 0; j < 3; j++ {
+					expected[3*j+i] = tc.result[3*i+j]
+				}
+			}
+
+			if v := Relate(tc.b, tc.a); v != string(expected) {
+				t.Errorf("incorrect transpose: %v != %v", v, string(expected))
+			}
+		})
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	square := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{2, 2}}
+
+	type predicates struct {
+		intersects, contains, within, covers, touches, crosses, overlaps bool
+	}
+
+	cases := []struct {
+		name     string
+		a, b     orb.Geometry
+		expected predicates
+	}{
+		{
+			name:     "point in polygon",
+			a:        square,
+			b:        orb.Point{1, 1},
+			expected: predicates{intersects: true, contains: true, covers: true},
+		},
+		{
+			name:     "point on boundary",
+			a:        square,
+			b:        orb.Point{0, 1},
+			expected: predicates{intersects: true, covers: true, touches: true},
+		},
+		{
+			name:     "point outside",
+			a:        square,
+			b:        orb.Point{3, 1},
+			expected: predicates{},
+		},
+		{
+			name:     "polygon within polygon",
+			a:        orb.Bound{Min: orb.Point{0.5, 0.5}, Max: orb.Point{1, 1}},
+			b:        square,
+			expected: predicates{intersects: true, within: true},
+		},
+		{
+			name:     "overlapping polygons",
+			a:        square,
+			b:        orb.Bound{Min: orb.Point{1, 1}, Max: orb.Point{3, 3}},
+			expected: predicates{intersects: true, overlaps: true},
+		},
+		{
+			name:     "touching polygons",
+			a:        square,
+			b:        orb.Bound{Min: orb.Point{2, 0}, Max: orb.Point{3, 3}},
+			expected: predicates{intersects: true, touches: true},
+		},
+		{
+			name:     "line crossing polygon",
+			a:        orb.LineString{{-1, 1}, {3, 1}},
+			b:        square,
+			expected: predicates{intersects: true, crosses: true},
+		},
+		{
+			name:     "crossing lines",
+			a:        orb.LineString{{0, 0}, {2, 2}},
+			b:        orb.LineString{{0, 2}, {2, 0}},
+			expected: predicates{intersects: true, crosses: true},
+		},
+		{
+			name:     "overlapping lines",
+			a:        orb.LineString{{0, 0}, {2, 0}},
+			b:        orb.LineString{{1, 0}, {3, 0}},
+			expected: predicates{intersects: true, overlaps: true},
+		},
+		{
+			name:     "polygon covers its edge",
+			a:        square,
+			b:        orb.LineString{{0, 0}, {2, 0}},
+			expected: predicates{intersects: true, covers: true, touches: true},
+		},
+		{
+			name:     "same points",
+			a:        orb.Point{1, 1},
+			b:        orb.Point{1, 1},
+			expected: predicates{intersects: true, contains: true, within: true, covers: true},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := predicates{
+				intersects: Intersects(tc.a, tc.b),
+				contains:   Contains(tc.a, tc.b),
+				within:     Within(tc.a, tc.b),
+				covers:     Covers(tc.a, tc.b),
+				touches:    Touches(tc.a, tc.b),
+				crosses:    Crosses(tc.a, tc.b),
+				overlaps:   Overlaps(tc.a, tc.b),
+			}
+
+			if result != tc.expected {
+				t.Errorf("incorrect predicates: %+v", result)
+				t.Logf("expected: %+v", tc.expected)
+			}
+
+			if Disjoint(tc.a, tc.b) == result.intersects {
+				t.Errorf("disjoint should be the opposite of intersects")
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package project
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// LambertConformalConic returns the forward and inverse ellipsoidal Lambert
+// Conformal Conic projection with standard parallels Lat1 and Lat2. If Lat2
+// is zero it is the same as Lat1 and if both are zero Lat0 is used, ie. the
+// one standard parallel variant, with the scale factor K0.
+func LambertConformalConic(params Parameters) (forward, inverse orb.Projection) {
+	p := params.withDefaults()
+	a := p.Ellipsoid.A
+	e2 := p.Ellipsoid.e2()
+	e := math.Sqrt(e2)
+
+	lat1, lat2 := standardParallels(p)
+	phi1, phi2r := deg2rad(lat1), deg2rad(lat2)
+
+	m1, t1 := msfn(phi1, e2), tsfn(phi1, e)
+
+	n := math.Sin(phi1)
+	if phi1 != phi2r {
+		m2, t2 := msfn(phi2r, e2), tsfn(phi2r, e)
+		n = (math.Log(m1) - math.Log(m2)) / (math.Log(t1) - math.Log(t2))
+	}
+
+	aF := a * p.K0 * m1 / (n * math.Pow(t1, n))
+	rho0 := aF * math.Pow(tsfn(deg2rad(p.Lat0), e), n)
+
+	forward = func(g orb.Point) orb.Point {
+		var rho float64
+		if phi := deg2rad(g[1]); math.Abs(math.Abs(phi)-math.Pi/2) > 1e-12 {
+			rho = aF * math.Pow(tsfn(phi, e), n)
+		} else if phi*n <= 0 {
+			// the opposite pole is at infinity
+			rho = math.Inf(1)
+		}
+
+		sinTheta, cosTheta := math.Sincos(n * lonDiff(g[0], p.Lon0))
+		return orb.Point{
+			p.FalseEasting + rho*sinTheta,
+			p.FalseNorthing + rho0 - rho*cosTheta,
+		}
+	}
+
+	inverse = func(g orb.Point) orb.Point {
+		x := g[0] - p.FalseEasting
+		y := rho0 - (g[1] - p.FalseNorthing)
+
+		rho := math.Copysign(math.Hypot(x, y), n)
+		if n < 0 {
+			x, y = -x, -y
+		}
+
+		phi := math.Copysign(math.Pi/2, n)
+		if rho != 0 {
+			phi = phi2(math.Pow(rho/aF, 1/n), e)
+		}
+
+		return orb.Point{lonSum(p.Lon0, math.Atan2(x, y)/n), rad2deg(phi)}
+	}
+
+	return forward, inverse
+}
+
+// AlbersEqualArea returns the forward and inverse ellipsoidal Albers
+// Equal Area conic projection with standard parallels Lat1 and Lat2.
+// If Lat2 is zero it is the same as Lat1.
+func AlbersEqualArea(params Parameters) (forward, inverse orb.Projection) {
+	p := params.withDefaults()
+	a := p.Ellipsoid.A
+	e2 := p.Ellipsoid.e2()
+	e := math.Sqrt(e2)
+
+	lat1, lat2 := standardParallels(p)
+	phi1, phi2r := deg2rad(lat1), deg2rad(lat2)
+
+	m1, q1 := msfn(phi1, e2), qsfn(phi1, e)
+
+	n := math.Sin(phi1)
+	if phi1 != phi2r {
+		m2, q2 := msfn(phi2r, e2), qsfn(phi2r, e)
+		n = (m1*m1 - m2*m2) / (q2 - q1)
+	}
+
+	c := m1*m1 + n*q1
+	rho0 := a * math.Sqrt(c-n*qsfn(deg2rad(p.Lat0), e)) / n
+
+	// q at the pole
+	qp := qsfn(math.Pi/2, e)
+
+	forward = func(g orb.Point) orb.Point {
+		rho := a * math.Sqrt(math.Max(0, c-n*qsfn(deg2rad(g[1]), e))) / n
+
+		sinTheta, cosTheta := math.Sincos(n * lonDiff(g[0], p.Lon0))
+		return orb.Point{
+			p.FalseEasting + rho*sinTheta,
+			p.FalseNorthing + rho0 - rho*cosTheta,
+		}
+	}
+
+	inverse = func(g orb.Point) orb.Point {
+		x := g[0] - p.FalseEasting
+		y := rho0 - (g[1] - p.FalseNorthing)
+		if n < 0 {
+			x, y = -x, -y
+		}
+
+		rho := math.Hypot(x, y)
+		q := (c - rho*rho*n*n/(a*a)) / n
+
+		var phi float64
+		if math.Abs(q) >= qp {
+			phi = math.Copysign(math.Pi/2, q)
+		} else {
+			phi = authalicInverse(q, e)
+		}
+
+		return orb.Point{lonSum(p.Lon0, math.Atan2(x, y)/n), rad2deg(phi)}
+	}
+
+	return forward, inverse
+}
+
+func standardParallels(p Parameters) (float64, float64) {
+	lat1, lat2 := p.Lat1, p.Lat2
+	if lat1 == 0 && lat2 == 0 {
+		lat1 = p.Lat0
+	}
+
+	if lat2 == 0 {
+		lat2 = lat1
+	}
+
+	return lat1, lat2
+}
+
+// qsfn is the function q used by the equal area projections.
+func qsfn(phi, e float64) float64 {
+	s := math.Sin(phi)
+	if e == 0 {
+		return 2 * s
+	}
+
+	es := e * s
+	return (1 - e*e) * (s/(1-es*es) - math.Log((1-es)/(1+es))/(2*e))
+}
+
+// authalicInverse returns the latitude for q by newton's method.
+func authalicInverse(q, e float64) float64 {
+	e2 := e * e
+	phi := math.Asin(q / 2)
+	if e == 0 {
+		return phi
+	}
+
+	for i := 0; i < 15; i++ {
+		sinPhi, cosPhi := math.Sincos(phi)
+		es := e * sinPhi
+		one := 1 - es*es
+
+		dphi := one * one / (2 * cosPhi) *
+			(q/(1-e2) - sinPhi/one + math.Log((1-es)/(1+es))/(2*e))
+		phi += dphi
+
+		if math.Abs(dphi) < 1e-14 {
+			break
+		}
+	}
+
+	return phi
+}
@@ -0,0 +1,89 @@
+package project
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+	"github.com/paulmach/orb/planar"
+)
+
+const usFoot = 1200.0 / 3937.0
+
+func TestLambertConformalConic(t *testing.T) {
+	t.Run("two standard parallels", func(t *testing.T) {
+		// NAD27 / Texas South Central, from EPSG guidance note 7-2
+		forward, inverse := LambertConformalConic(Parameters{
+			Ellipsoid:    EllipsoidClarke1866,
+			Lon0:         -99,
+			Lat0:         27 + 50.0/60,
+			Lat1:         28 + 23.0/60,
+			Lat2:         30 + 17.0/60,
+			FalseEasting: 2000000 * usFoot,
+		})
+
+		expected := orb.Point{2963503.91 * usFoot, 254759.80 * usFoot}
+		checkProjection(t, forward, inverse, orb.Point{-96, 28.5}, expected, 0.01)
+	})
+
+	t.Run("one standard parallel", func(t *testing.T) {
+		// JAD69 / Jamaica National Grid, from EPSG guidance note 7-2
+		forward, inverse := LambertConformalConic(Parameters{
+			Ellipsoid:     EllipsoidClarke1866,
+			Lon0:          -77,
+			Lat0:          18,
+			K0:            1,
+			FalseEasting:  250000,
+			FalseNorthing: 150000,
+		})
+
+		ll := orb.Point{-(76 + 56.0/60 + 37.26/3600), 17 + 55.0/60 + 55.80/3600}
+		checkProjection(t, forward, inverse, ll, orb.Point{255966.58, 142493.51}, 0.01)
+	})
+
+	t.Run("southern hemisphere", func(t *testing.T) {
+		forward, inverse := LambertConformalConic(Parameters{
+			Lon0: 135,
+			Lat0: -32,
+			Lat1: -28,
+			Lat2: -36,
+		})
+
+		p := forward(orb.Point{135, -32})
+		if math.Abs(p[0]) > 1e-6 || math.Abs(p[1]) > 1e-6 {
+			t.Errorf("origin should be at zero: %v", p)
+		}
+
+		checkProjection(t, forward, inverse, orb.Point{140, -40}, forward(orb.Point{140, -40}), 0)
+	})
+}
+
+func TestAlbersEqualArea(t *testing.T) {
+	// NAD83 / Conus Albers
+	forward, inverse := AlbersEqualArea(Parameters{
+		Ellipsoid: EllipsoidGRS80,
+		Lon0:      -96,
+		Lat0:      23,
+		Lat1:      29.5,
+		Lat2:      45.5,
+	})
+
+	p := forward(orb.Point{-96, 23})
+	if math.Abs(p[0]) > 1e-6 || math.Abs(p[1]) > 1e-6 {
+		t.Errorf("origin should be at zero: %v", p)
+	}
+
+	for _, ll := range []orb.Point{{-122.4, 37.8}, {-74, 40.7}, {-80.2, 25.8}, {-96, 89.9}} {
+		checkProjection(t, forward, inverse, ll, forward(ll), 0)
+	}
+
+	// the area should be the same as on the ellipsoid
+	cell := orb.Ring{{-100, 40}, {-99, 40}, {-99, 41}, {-100, 41}, {-100, 40}}
+	cell = geo.Densify(cell, 100).(orb.Ring)
+
+	projected := Ring(cell.Clone(), forward)
+	if a, e := planar.Area(projected), geo.GeodesicArea(cell); math.Abs(a-e)/e > 1e-6 {
+		t.Errorf("area should be preserved: %v != %v", a, e)
+	}
+}
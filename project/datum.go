@@ -0,0 +1,177 @@
+package project
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// Helmert defines a 7 parameter transformation between geocentric
+// coordinates using the position vector convention, the same as
+// +towgs84 in PROJ strings.
+type Helmert struct {
+	// Tx, Ty and Tz are the translations in meters.
+	Tx, Ty, Tz float64
+
+	// Rx, Ry and Rz are the rotations in arc-seconds.
+	Rx, Ry, Rz float64
+
+	// S is the scale difference in parts per million.
+	S float64
+}
+
+// A Datum is an ellipsoid positioned relative to WGS84.
+type Datum struct {
+	Ellipsoid Ellipsoid
+
+	// ToWGS84 transforms geocentric coordinates on this datum to WGS84.
+	ToWGS84 Helmert
+}
+
+// Common datums. The transformations to WGS84 are the usual approximations
+// for the whole area of use and are good to a few meters.
+var (
+	DatumWGS84  = Datum{Ellipsoid: EllipsoidWGS84}
+	DatumNAD83  = Datum{Ellipsoid: EllipsoidGRS80}
+	DatumETRS89 = Datum{Ellipsoid: EllipsoidGRS80}
+	DatumNAD27  = Datum{
+		Ellipsoid: EllipsoidClarke1866,
+		ToWGS84:   Helmert{Tx: -8, Ty: 160, Tz: 176},
+	}
+	DatumOSGB36 = Datum{
+		Ellipsoid: EllipsoidAiry1830,
+		ToWGS84: Helmert{
+			Tx: 446.448, Ty: -125.157, Tz: 542.06,
+			Rx: 0.15, Ry: 0.247, Rz: 0.842,
+			S: -20.489,
+		},
+	}
+	DatumED50 = Datum{
+		Ellipsoid: EllipsoidInternational1924,
+		ToWGS84:   Helmert{Tx: -87, Ty: -98, Tz: -121},
+	}
+	DatumDHDN = Datum{
+		Ellipsoid: EllipsoidBessel1841,
+		ToWGS84: Helmert{
+			Tx: 598.1, Ty: 73.7, Tz: 418.2,
+			Rx: 0.202, Ry: 0.045, Rz: -2.455,
+			S: 6.7,
+		},
+	}
+	DatumTokyo = Datum{
+		Ellipsoid: EllipsoidBessel1841,
+		ToWGS84:   Helmert{Tx: -146.414, Ty: 507.337, Tz: 680.507},
+	}
+)
+
+// DatumTransform returns a projection that shifts lon/lat points from one
+// datum to another. The points are converted to geocentric coordinates,
+// transformed to WGS84, then to the other datum and back to lon/lat.
+// Heights are assumed to be zero and are dropped. This composes with the
+// other projections, e.g. to go from British National Grid to WGS84 use
+// the inverse of the grid projection and then DatumTransform(DatumOSGB36, DatumWGS84).
+func DatumTransform(from, to Datum) orb.Projection {
+	fromWGS84 := to.ToWGS84.inverse()
+
+	return func(p orb.Point) orb.Point {
+		x, y, z := toGeocentric(from.Ellipsoid, p[0], p[1], 0)
+		x, y, z = from.ToWGS84.transform(x, y, z)
+		x, y, z = fromWGS84(x, y, z)
+
+		lon, lat, _ := fromGeocentric(to.Ellipsoid, x, y, z)
+		return orb.Point{lon, lat}
+	}
+}
+
+// transform applies the helmert transformation to the geocentric coordinates.
+func (h Helmert) transform(x, y, z float64) (float64, float64, float64) {
+	if h == (Helmert{}) {
+		return x, y, z
+	}
+
+	m := 1 + h.S*1e-6
+	rx, ry, rz := h.rotations()
+
+	return h.Tx + m*(x-rz*y+ry*z),
+		h.Ty + m*(rz*x+y-rx*z),
+		h.Tz + m*(-ry*x+rx*y+z)
+}
+
+// inverse returns the exact inverse of the transformation.
+func (h Helmert) inverse() func(x, y, z float64) (float64, float64, float64) {
+	if h == (Helmert{}) {
+		return func(x, y, z float64) (float64, float64, float64) { return x, y, z }
+	}
+
+	m := 1 + h.S*1e-6
+	rx, ry, rz := h.rotations()
+
+	// the inverse of the rotation matrix
+	// [  1  -rz  ry ]
+	// [  rz  1  -rx ]
+	// [ -ry  rx  1  ]
+	det := 1 + rx*rx + ry*ry + rz*rz
+	inv := [3][3]float64{
+		{1 + rx*rx, rz + rx*ry, rx*rz - ry},
+		{rx*ry - rz, 1 + ry*ry, rx + ry*rz},
+		{ry + rx*rz, ry*rz - rx, 1 + rz*rz},
+	}
+
+	return func(x, y, z float64) (float64, float64, float64) {
+		x = (x - h.Tx) / m / det
+		y = (y - h.Ty) / m / det
+		z = (z - h.Tz) / m / det
+
+		return inv[0][0]*x + inv[0][1]*y + inv[0][2]*z,
+			inv[1][0]*x + inv[1][1]*y + inv[1][2]*z,
+			inv[2][0]*x + inv[2][1]*y + inv[2][2]*z
+	}
+}
+
+// rotations returns the rotations in radians.
+func (h Helmert) rotations() (float64, float64, float64) {
+	const arcsec = math.Pi / 180 / 3600
+	return h.Rx * arcsec, h.Ry * arcsec, h.Rz * arcsec
+}
+
+// toGeocentric converts lon/lat in degrees and height in meters to
+// earth centered, earth fixed coordinates.
+func toGeocentric(e Ellipsoid, lon, lat, h float64) (float64, float64, float64) {
+	e2 := e.e2()
+	sinPhi, cosPhi := math.Sincos(deg2rad(lat))
+	sinLambda, cosLambda := math.Sincos(deg2rad(lon))
+
+	n := e.A / math.Sqrt(1-e2*sinPhi*sinPhi)
+	return (n + h) * cosPhi * cosLambda,
+		(n + h) * cosPhi * sinLambda,
+		(n*(1-e2) + h) * sinPhi
+}
+
+// fromGeocentric converts earth centered, earth fixed coordinates to
+// lon/lat in degrees and height in meters.
+func fromGeocentric(e Ellipsoid, x, y, z float64) (lon, lat, h float64) {
+	e2 := e.e2()
+	p := math.Hypot(x, y)
+
+	lambda := math.Atan2(y, x)
+	phi := math.Atan2(z, p*(1-e2))
+	for i := 0; i < 10; i++ {
+		sinPhi, cosPhi := math.Sincos(phi)
+		n := e.A / math.Sqrt(1-e2*sinPhi*sinPhi)
+
+		if math.Abs(cosPhi) > 1e-10 {
+			h = p/cosPhi - n
+		} else {
+			h = math.Abs(z) - n*(1-e2)
+		}
+
+		next := math.Atan2(z, p*(1-e2*n/(n+h)))
+		if math.Abs(next-phi) < 1e-14 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+
+	return rad2deg(lambda), rad2deg(phi), h
+}
@@ -0,0 +1,132 @@
+package project
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+	"github.com/paulmach/orb/internal/mercator"
+)
+
+func TestDatumTransform(t *testing.T) {
+	datums := map[string]Datum{
+		"NAD27":  DatumNAD27,
+		"OSGB36": DatumOSGB36,
+		"ED50":   DatumED50,
+		"DHDN":   DatumDHDN,
+		"Tokyo":  DatumTokyo,
+	}
+
+	for name, d := range datums {
+		t.Run(name, func(t *testing.T) {
+			toWGS84 := DatumTransform(d, DatumWGS84)
+			fromWGS84 := DatumTransform(DatumWGS84, d)
+
+			for _, city := range mercator.Cities {
+				ll := orb.Point{city[1], city[0]}
+
+				shifted := toWGS84(ll)
+				if dist := geo.Distance(ll, shifted); dist > 1000 {
+					t.Errorf("shift too large for %v: %v", ll, dist)
+				}
+
+				// heights are dropped so it is only good to about a meter
+				back := fromWGS84(shifted)
+				if math.Abs(back[0]-ll[0]) > 1e-5 || math.Abs(back[1]-ll[1]) > 1e-5 {
+					t.Errorf("incorrect round trip: %v != %v", back, ll)
+				}
+			}
+		})
+	}
+
+	t.Run("same datum", func(t *testing.T) {
+		p := orb.Point{-1.5, 52.5}
+		result := DatumTransform(DatumOSGB36, DatumOSGB36)(p)
+		if math.Abs(result[0]-p[0]) > 1e-12 || math.Abs(result[1]-p[1]) > 1e-12 {
+			t.Errorf("should not change: %v != %v", result, p)
+		}
+	})
+
+	t.Run("osgb36 in england", func(t *testing.T) {
+		// OSGB36 coordinates are between 80 and 150 meters east of WGS84,
+		// mostly in longitude.
+		p := orb.Point{-1.5, 52.5}
+		result := DatumTransform(DatumOSGB36, DatumWGS84)(p)
+
+		if d := geo.Distance(p, result); d < 80 || d > 150 {
+			t.Errorf("incorrect shift: %v", d)
+		}
+
+		if result[0] >= p[0] {
+			t.Errorf("should shift west: %v", result)
+		}
+	})
+
+	t.Run("through a projection", func(t *testing.T) {
+		_, toLonLat, err := FromEPSG(27700)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+
+		ls := orb.LineString{{577274.99, 69740.50}, {400000, 300000}}
+		result := Geometry(Geometry(ls.Clone(), toLonLat), DatumTransform(DatumOSGB36, DatumWGS84)).(orb.LineString)
+
+		if len(result) != 2 {
+			t.Fatalf("incorrect length: %v", len(result))
+		}
+
+		if d := geo.Distance(result[0], orb.Point{0.5, 50.5}); d < 80 || d > 150 {
+			t.Errorf("incorrect shift: %v", d)
+		}
+	})
+}
+
+func TestHelmert(t *testing.T) {
+	// WGS 72 to WGS 84, from EPSG guidance note 7-2
+	h := Helmert{Tz: 4.5, Rz: 0.554, S: 0.219}
+
+	x, y, z := h.transform(3657660.66, 255768.55, 5201382.11)
+	expected := [3]float64{3657660.78, 255778.43, 5201387.75}
+	if math.Abs(x-expected[0]) > 0.01 || math.Abs(y-expected[1]) > 0.01 || math.Abs(z-expected[2]) > 0.01 {
+		t.Errorf("incorrect transform: %v %v %v != %v", x, y, z, expected)
+	}
+
+	x, y, z = h.inverse()(x, y, z)
+	if math.Abs(x-3657660.66) > 1e-6 || math.Abs(y-255768.55) > 1e-6 || math.Abs(z-5201382.11) > 1e-6 {
+		t.Errorf("incorrect inverse: %v %v %v", x, y, z)
+	}
+
+	// the inverse must be exact with all the rotations
+	h = DatumOSGB36.ToWGS84
+	x, y, z = h.inverse()(h.transform(3874938.849, 116218.624, 5047168.208))
+	if math.Abs(x-3874938.849) > 1e-6 || math.Abs(y-116218.624) > 1e-6 || math.Abs(z-5047168.208) > 1e-6 {
+		t.Errorf("incorrect inverse: %v %v %v", x, y, z)
+	}
+}
+
+func TestGeocentric(t *testing.T) {
+	// from EPSG guidance note 7-2
+	lon := 2 + 7/60.0 + 46.38/3600
+	lat := 53 + 48/60.0 + 33.82/3600
+
+	x, y, z := toGeocentric(EllipsoidWGS84, lon, lat, 73)
+	expected := [3]float64{3771793.968, 140253.342, 5124304.349}
+	if math.Abs(x-expected[0]) > 0.001 || math.Abs(y-expected[1]) > 0.001 || math.Abs(z-expected[2]) > 0.001 {
+		t.Errorf("incorrect geocentric: %v %v %v != %v", x, y, z, expected)
+	}
+
+	rLon, rLat, h := fromGeocentric(EllipsoidWGS84, x, y, z)
+	if math.Abs(rLon-lon) > 1e-10 || math.Abs(rLat-lat) > 1e-10 || math.Abs(h-73) > 1e-4 {
+		t.Errorf("incorrect geodetic: %v %v %v", rLon, rLat, h)
+	}
+
+	// the poles
+	for _, lat := range []float64{90, -90} {
+		x, y, z := toGeocentric(EllipsoidWGS84, 0, lat, 0)
+		_, rLat, h := fromGeocentric(EllipsoidWGS84, x, y, z)
+		if math.Abs(rLat-lat) > 1e-10 || math.Abs(h) > 1e-4 {
+			t.Errorf("incorrect pole: %v %v", rLat, h)
+		}
+	}
+}
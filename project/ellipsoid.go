@@ -0,0 +1,100 @@
+package project
+
+import "math"
+
+// An Ellipsoid approximates the shape of the earth.
+type Ellipsoid struct {
+	// A is the semi-major axis, the equatorial radius, in meters.
+	A float64
+
+	// F is the flattening, (a-b)/a where b is the polar radius.
+	F float64
+}
+
+// Common ellipsoids.
+var (
+	EllipsoidWGS84             = Ellipsoid{A: 6378137, F: 1 / 298.257223563}
+	EllipsoidGRS80             = Ellipsoid{A: 6378137, F: 1 / 298.257222101}
+	EllipsoidAiry1830          = Ellipsoid{A: 6377563.396, F: 1 / 299.3249646}
+	EllipsoidBessel1841        = Ellipsoid{A: 6377397.155, F: 1 / 299.1528128}
+	EllipsoidClarke1866        = Ellipsoid{A: 6378206.4, F: 1 / 294.978698213898}
+	EllipsoidInternational1924 = Ellipsoid{A: 6378388, F: 1 / 297.0}
+)
+
+// e2 returns the square of the eccentricity.
+func (e Ellipsoid) e2() float64 {
+	return e.F * (2 - e.F)
+}
+
+// Parameters define a projection. Each projection uses the subset of
+// values it needs. Angles are in degrees and distances in meters.
+type Parameters struct {
+	// Ellipsoid defaults to WGS84 if zero.
+	Ellipsoid Ellipsoid
+
+	// Lon0 is the central meridian and Lat0 the latitude of origin.
+	Lon0, Lat0 float64
+
+	// Lat1 and Lat2 are the standard parallels, or latitude of true scale.
+	Lat1, Lat2 float64
+
+	// K0 is the scale factor at the origin, defaults to 1 if zero.
+	K0 float64
+
+	FalseEasting, FalseNorthing float64
+}
+
+func (p Parameters) withDefaults() Parameters {
+	if p.Ellipsoid == (Ellipsoid{}) {
+		p.Ellipsoid = EllipsoidWGS84
+	}
+
+	if p.K0 == 0 {
+		p.K0 = 1
+	}
+
+	return p
+}
+
+// lonDiff returns the difference in longitude in radians in the range [-pi, pi].
+func lonDiff(lon, lon0 float64) float64 {
+	return deg2rad(math.Remainder(lon-lon0, 360))
+}
+
+// lonSum returns lon0 + lambda, in radians, as degrees in the range [-180, 180].
+func lonSum(lon0, lambda float64) float64 {
+	lon := lon0 + rad2deg(lambda)
+	if lon < -180 || lon > 180 {
+		lon = math.Remainder(lon, 360)
+	}
+
+	return lon
+}
+
+// tsfn is the function t used by the conformal projections,
+// tan(pi/4 - phi/2) / ((1 - e*sin(phi)) / (1 + e*sin(phi)))^(e/2).
+func tsfn(phi, e float64) float64 {
+	s := e * math.Sin(phi)
+	return math.Tan(math.Pi/4-phi/2) / math.Pow((1-s)/(1+s), e/2)
+}
+
+// phi2 is the inverse of tsfn, found by iteration.
+func phi2(t, e float64) float64 {
+	phi := math.Pi/2 - 2*math.Atan(t)
+	for i := 0; i < 15; i++ {
+		s := e * math.Sin(phi)
+		next := math.Pi/2 - 2*math.Atan(t*math.Pow((1-s)/(1+s), e/2))
+		if math.Abs(next-phi) < 1e-14 {
+			return next
+		}
+		phi = next
+	}
+
+	return phi
+}
+
+// msfn returns cos(phi) / sqrt(1 - e^2 * sin^2(phi)).
+func msfn(phi, e2 float64) float64 {
+	s := math.Sin(phi)
+	return math.Cos(phi) / math.Sqrt(1-e2*s*s)
+}
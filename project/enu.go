@@ -0,0 +1,66 @@
+package project
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// LocalENU returns the forward and inverse projections for a local east,
+// north, up tangent plane at the origin on the WGS84 ellipsoid. The
+// forward projection returns meters east and north of the origin so
+// planar functions, e.g. planar.Area or simplify.DouglasPeucker, work in
+// meters. Points are projected straight down onto the plane so the scale
+// error grows with the square of the distance, about one part per million
+// at 10km, making this a good fit for small areas such as a site survey.
+// The inverse of points that do not lie above the ellipsoid, over about a
+// quarter of the earth away, is NaN.
+func LocalENU(origin orb.Point) (forward, inverse orb.Projection) {
+	e := EllipsoidWGS84
+	b := e.A * (1 - e.F)
+
+	ox, oy, oz := toGeocentric(e, origin[0], origin[1], 0)
+
+	sinPhi, cosPhi := math.Sincos(deg2rad(origin[1]))
+	sinLambda, cosLambda := math.Sincos(deg2rad(origin[0]))
+
+	east := [3]float64{-sinLambda, cosLambda, 0}
+	north := [3]float64{-sinPhi * cosLambda, -sinPhi * sinLambda, cosPhi}
+	up := [3]float64{cosPhi * cosLambda, cosPhi * sinLambda, sinPhi}
+
+	forward = func(p orb.Point) orb.Point {
+		x, y, z := toGeocentric(e, p[0], p[1], 0)
+		x, y, z = x-ox, y-oy, z-oz
+
+		return orb.Point{
+			east[0]*x + east[1]*y + east[2]*z,
+			north[0]*x + north[1]*y + north[2]*z,
+		}
+	}
+
+	// the quadratic coefficient for moving along the up vector
+	qa := (up[0]*up[0]+up[1]*up[1])/(e.A*e.A) + up[2]*up[2]/(b*b)
+
+	inverse = func(p orb.Point) orb.Point {
+		// the point on the plane
+		x := ox + p[0]*east[0] + p[1]*north[0]
+		y := oy + p[0]*east[1] + p[1]*north[1]
+		z := oz + p[0]*east[2] + p[1]*north[2]
+
+		// find how far along the up vector it meets the ellipsoid,
+		// using the root closest to the plane.
+		qb := 2 * ((x*up[0]+y*up[1])/(e.A*e.A) + z*up[2]/(b*b))
+		qc := (x*x+y*y)/(e.A*e.A) + z*z/(b*b) - 1
+
+		disc := qb*qb - 4*qa*qc
+		if disc < 0 {
+			return orb.Point{math.NaN(), math.NaN()}
+		}
+
+		u := -2 * qc / (qb + math.Sqrt(disc))
+		lon, lat, _ := fromGeocentric(e, x+u*up[0], y+u*up[1], z+u*up[2])
+		return orb.Point{lon, lat}
+	}
+
+	return forward, inverse
+}
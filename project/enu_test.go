@@ -0,0 +1,79 @@
+package project
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+	"github.com/paulmach/orb/internal/mercator"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestLocalENU(t *testing.T) {
+	for _, city := range mercator.Cities {
+		origin := orb.Point{city[1], city[0]}
+		forward, inverse := LocalENU(origin)
+
+		p := forward(origin)
+		if math.Abs(p[0]) > 1e-6 || math.Abs(p[1]) > 1e-6 {
+			t.Errorf("origin should be zero: %v", p)
+		}
+
+		for _, offset := range []orb.Point{{0.01, 0}, {0, 0.01}, {-0.05, 0.03}} {
+			ll := orb.Point{origin[0] + offset[0], origin[1] + offset[1]}
+			if ll[1] > 90 || ll[1] < -90 {
+				continue
+			}
+
+			p := forward(ll)
+
+			// orthogonal directions
+			if offset[0] == 0 && math.Abs(p[0]) > 1 {
+				t.Errorf("should be north: %v", p)
+			}
+
+			if offset[1] == 0 && math.Abs(p[1]) > 10 {
+				t.Errorf("should be east: %v", p)
+			}
+
+			// distances are preserved near the origin
+			d := math.Hypot(p[0], p[1])
+			if g := geo.GeodesicDistance(origin, ll); math.Abs(d-g)/g > 1e-6 {
+				t.Errorf("incorrect distance: %v != %v", d, g)
+			}
+
+			back := inverse(p)
+			if math.Abs(back[0]-ll[0]) > 1e-9 || math.Abs(back[1]-ll[1]) > 1e-9 {
+				t.Errorf("incorrect round trip: %v != %v", back, ll)
+			}
+		}
+	}
+
+	t.Run("area", func(t *testing.T) {
+		poly := orb.Polygon{{
+			{-122.4163816, 37.7792782},
+			{-122.4162786, 37.7787626},
+			{-122.4151027, 37.7789118},
+			{-122.4152143, 37.7794274},
+			{-122.4163816, 37.7792782},
+		}}
+
+		forward, _ := LocalENU(poly[0][0])
+		area := planar.Area(Polygon(poly.Clone(), forward))
+
+		expected := math.Abs(geo.GeodesicArea(poly))
+		if math.Abs(math.Abs(area)-expected) > 1e-3 {
+			t.Errorf("incorrect area: %v != %v", area, expected)
+		}
+	})
+
+	t.Run("off the globe", func(t *testing.T) {
+		_, inverse := LocalENU(orb.Point{0, 0})
+
+		p := inverse(orb.Point{1e8, 0})
+		if !math.IsNaN(p[0]) || !math.IsNaN(p[1]) {
+			t.Errorf("should be NaN: %v", p)
+		}
+	})
+}
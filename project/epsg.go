@@ -0,0 +1,88 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/paulmach/orb"
+)
+
+// FromEPSG returns the forward and inverse projections for the EPSG code,
+// e.g. the SRID of EWKB data. The forward projection takes lon/lat on the
+// datum of the coordinate reference system. Geographic systems, such as
+// 4326, return the identity projection. Returns ErrUnknownEPSG if the
+// code is not in the built in table of common definitions.
+func FromEPSG(code int) (forward, inverse orb.Projection, err error) {
+	def, ok := epsgDefinition(code)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %d", ErrUnknownEPSG, code)
+	}
+
+	return ParsePROJ(def)
+}
+
+// epsgDefinition returns the PROJ string for the EPSG code.
+func epsgDefinition(code int) (string, bool) {
+	if def, ok := epsg[code]; ok {
+		return def, true
+	}
+
+	// UTM zones are computed instead of listed
+	for _, z := range epsgUTM {
+		if code >= z.code+z.first && code <= z.code+z.last {
+			def := fmt.Sprintf("+proj=utm +zone=%d %s", code-z.code, z.datum)
+			if z.south {
+				def += " +south"
+			}
+			return def, true
+		}
+	}
+
+	return "", false
+}
+
+var epsgUTM = []struct {
+	code        int
+	first, last int
+	datum       string
+	south       bool
+}{
+	{code: 32600, first: 1, last: 60, datum: "+datum=WGS84"},              // WGS 84 / UTM zone N
+	{code: 32700, first: 1, last: 60, datum: "+datum=WGS84", south: true}, // WGS 84 / UTM zone S
+	{code: 26900, first: 1, last: 23, datum: "+datum=NAD83"},              // NAD83 / UTM zone N
+	{code: 26700, first: 1, last: 22, datum: "+datum=NAD27"},              // NAD27 / UTM zone N
+	{code: 25800, first: 28, last: 38, datum: "+ellps=GRS80"},             // ETRS89 / UTM zone N
+	{code: 23000, first: 28, last: 38, datum: "+ellps=intl"},              // ED50 / UTM zone N
+}
+
+// epsg is a table of common coordinate reference systems as PROJ strings.
+var epsg = map[int]string{
+	// geographic
+	4326: "+proj=longlat +datum=WGS84",  // WGS 84
+	4269: "+proj=longlat +datum=NAD83",  // NAD83
+	4267: "+proj=longlat +datum=NAD27",  // NAD27
+	4258: "+proj=longlat +ellps=GRS80",  // ETRS89
+	4277: "+proj=longlat +datum=OSGB36", // OSGB 1936
+	4230: "+proj=longlat +ellps=intl",   // ED50
+
+	// world
+	3857: "+proj=merc +a=6378137 +b=6378137 +lat_ts=0 +lon_0=0 +x_0=0 +y_0=0 +k=1", // WGS 84 / Pseudo-Mercator
+	3395: "+proj=merc +lon_0=0 +k=1 +x_0=0 +y_0=0 +datum=WGS84",                    // WGS 84 / World Mercator
+	4087: "+proj=eqc +lat_ts=0 +lat_0=0 +lon_0=0 +x_0=0 +y_0=0 +datum=WGS84",       // WGS 84 / World Equidistant Cylindrical
+
+	// polar
+	32661: "+proj=stere +lat_0=90 +lat_ts=90 +lon_0=0 +k=0.994 +x_0=2000000 +y_0=2000000 +datum=WGS84",   // WGS 84 / UPS North
+	32761: "+proj=stere +lat_0=-90 +lat_ts=-90 +lon_0=0 +k=0.994 +x_0=2000000 +y_0=2000000 +datum=WGS84", // WGS 84 / UPS South
+	3031:  "+proj=stere +lat_0=-90 +lat_ts=-71 +lon_0=0 +k=1 +x_0=0 +y_0=0 +datum=WGS84",                 // WGS 84 / Antarctic Polar Stereographic
+	3413:  "+proj=stere +lat_0=90 +lat_ts=70 +lon_0=-45 +k=1 +x_0=0 +y_0=0 +datum=WGS84",                 // WGS 84 / NSIDC Sea Ice Polar Stereographic North
+	3995:  "+proj=stere +lat_0=90 +lat_ts=71 +lon_0=0 +k=1 +x_0=0 +y_0=0 +datum=WGS84",                   // WGS 84 / Arctic Polar Stereographic
+
+	// national grids
+	27700: "+proj=tmerc +lat_0=49 +lon_0=-2 +k=0.9996012717 +x_0=400000 +y_0=-100000 +ellps=airy",                                                      // OSGB 1936 / British National Grid
+	2157:  "+proj=tmerc +lat_0=53.5 +lon_0=-8 +k=0.99982 +x_0=600000 +y_0=750000 +ellps=GRS80",                                                         // IRENET95 / Irish Transverse Mercator
+	2154:  "+proj=lcc +lat_0=46.5 +lon_0=3 +lat_1=49 +lat_2=44 +x_0=700000 +y_0=6600000 +ellps=GRS80",                                                  // RGF93 / Lambert-93
+	3006:  "+proj=tmerc +lat_0=0 +lon_0=15 +k=0.9996 +x_0=500000 +y_0=0 +ellps=GRS80",                                                                  // SWEREF99 TM
+	2193:  "+proj=tmerc +lat_0=0 +lon_0=173 +k=0.9996 +x_0=1600000 +y_0=10000000 +ellps=GRS80",                                                         // NZGD2000 / New Zealand Transverse Mercator 2000
+	5070:  "+proj=aea +lat_0=23 +lon_0=-96 +lat_1=29.5 +lat_2=45.5 +x_0=0 +y_0=0 +datum=NAD83",                                                         // NAD83 / Conus Albers
+	2263:  "+proj=lcc +lat_0=40.1666666666667 +lon_0=-74 +lat_1=41.0333333333333 +lat_2=40.6666666666667 +x_0=300000 +y_0=0 +datum=NAD83 +units=us-ft", // NAD83 / New York Long Island (ftUS)
+	3577:  "+proj=aea +lat_0=0 +lon_0=132 +lat_1=-18 +lat_2=-36 +x_0=0 +y_0=0 +ellps=GRS80",                                                            // GDA94 / Australian Albers
+}
@@ -0,0 +1,34 @@
+package project
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// Equirectangular returns the forward and inverse Equirectangular, or
+// Equidistant Cylindrical, projection on a sphere with the radius of the
+// ellipsoid's semi-major axis. Lat1 is the standard parallel where the scale
+// is true and Lat0 the latitude of origin.
+func Equirectangular(params Parameters) (forward, inverse orb.Projection) {
+	p := params.withDefaults()
+	r := p.Ellipsoid.A
+	cosPhi1 := math.Cos(deg2rad(p.Lat1))
+
+	forward = func(g orb.Point) orb.Point {
+		return orb.Point{
+			p.FalseEasting + r*lonDiff(g[0], p.Lon0)*cosPhi1,
+			p.FalseNorthing + r*deg2rad(g[1]-p.Lat0),
+		}
+	}
+
+	inverse = func(g orb.Point) orb.Point {
+		lambda := (g[0] - p.FalseEasting) / (r * cosPhi1)
+		return orb.Point{
+			lonSum(p.Lon0, lambda),
+			p.Lat0 + rad2deg((g[1]-p.FalseNorthing)/r),
+		}
+	}
+
+	return forward, inverse
+}
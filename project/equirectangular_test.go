@@ -0,0 +1,16 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestEquirectangular(t *testing.T) {
+	forward, inverse := Equirectangular(Parameters{})
+	checkProjection(t, forward, inverse, orb.Point{10, 55}, orb.Point{1113194.908, 6122571.994}, 1e-3)
+
+	// standard parallel at 60 degrees halves the width
+	forward, inverse = Equirectangular(Parameters{Lat1: 60, Lon0: 10, FalseEasting: 100})
+	checkProjection(t, forward, inverse, orb.Point{20, 55}, orb.Point{100 + 1113194.908/2, 6122571.994}, 1e-3)
+}
@@ -2,10 +2,12 @@ package project_test
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/paulmach/orb"
 	"github.com/paulmach/orb/planar"
 	"github.com/paulmach/orb/project"
+	"github.com/paulmach/orb/simplify"
 )
 
 func ExamplePoint_toMercator() {
@@ -36,3 +38,141 @@ func ExamplePolygon() {
 	// Output:
 	// [-122.41574403384001 37.77909471899779]
 }
+
+func ExampleUTM() {
+	sf := orb.Point{-122.416667, 37.783333}
+
+	forward, inverse := project.UTM(project.UTMZone(sf))
+
+	utm := forward(sf)
+	fmt.Printf("%0.2f %0.2f\n", utm[0], utm[1])
+
+	ll := inverse(utm)
+	fmt.Printf("%0.6f %0.6f\n", ll[0], ll[1])
+	// Output:
+	// 551365.62 4181936.01
+	// -122.416667 37.783333
+}
+
+func ExampleTransverseMercator() {
+	// British National Grid
+	forward, _ := project.TransverseMercator(project.Parameters{
+		Ellipsoid:     project.EllipsoidAiry1830,
+		Lon0:          -2,
+		Lat0:          49,
+		K0:            0.9996012717,
+		FalseEasting:  400000,
+		FalseNorthing: -100000,
+	})
+
+	p := forward(orb.Point{0.5, 50.5})
+	fmt.Printf("%0.1f %0.1f", p[0], p[1])
+	// Output:
+	// 577275.0 69740.5
+}
+
+func ExampleFromEPSG() {
+	// e.g. the srid from ewkb.Unmarshal
+	srid := 27700
+
+	_, toLonLat, err := project.FromEPSG(srid)
+	if err != nil {
+		panic(err)
+	}
+
+	p := toLonLat(orb.Point{577274.99, 69740.50})
+	fmt.Printf("%0.6f %0.6f", p[0], p[1])
+	// Output:
+	// 0.500000 50.500000
+}
+
+func ExampleParsePROJ() {
+	forward, _, err := project.ParsePROJ("+proj=utm +zone=10 +datum=WGS84 +units=m +no_defs")
+	if err != nil {
+		panic(err)
+	}
+
+	p := forward(orb.Point{-122.416667, 37.783333})
+	fmt.Printf("%0.2f %0.2f", p[0], p[1])
+	// Output:
+	// 551365.62 4181936.01
+}
+
+func ExampleDatumTransform() {
+	// British National Grid is on the OSGB36 datum
+	_, toLonLat, err := project.FromEPSG(27700)
+	if err != nil {
+		panic(err)
+	}
+
+	p := toLonLat(orb.Point{530000, 180000})
+	p = project.DatumTransform(project.DatumOSGB36, project.DatumWGS84)(p)
+
+	fmt.Printf("%0.5f %0.5f", p[0], p[1])
+	// Output:
+	// -0.12835 51.50399
+}
+
+func ExampleCompose() {
+	// British National Grid to WGS84 lon/lat
+	forward, inverse, err := project.FromEPSG(27700)
+	if err != nil {
+		panic(err)
+	}
+
+	t := project.Compose(
+		project.Invert(project.Pair(forward, inverse)),
+		project.Pair(
+			project.DatumTransform(project.DatumOSGB36, project.DatumWGS84),
+			project.DatumTransform(project.DatumWGS84, project.DatumOSGB36),
+		),
+	)
+
+	p := t.Forward(orb.Point{530000, 180000})
+	fmt.Printf("%0.5f %0.5f", p[0], p[1])
+	// Output:
+	// -0.12835 51.50399
+}
+
+func ExampleBoundDensified() {
+	// NAD83 / Conus Albers
+	forward, inverse, err := project.FromEPSG(5070)
+	if err != nil {
+		panic(err)
+	}
+
+	conus := orb.Bound{Min: orb.Point{-125, 24}, Max: orb.Point{-66, 50}}
+
+	corners := project.Bound(conus, forward)
+	densified := project.BoundDensified(conus, project.Pair(forward, inverse), 100)
+
+	fmt.Printf("corners:   %0.0f\n", corners.Min[1])
+	fmt.Printf("densified: %0.0f\n", densified.Min[1])
+	// Output:
+	// corners:   562556
+	// densified: 108873
+}
+
+func ExampleLocalENU() {
+	// a drone survey area
+	survey := orb.Polygon{{
+		{-122.4163816, 37.7792782},
+		{-122.4162786, 37.7787626},
+		{-122.4151027, 37.7789118},
+		{-122.4152143, 37.7794274},
+		{-122.4163816, 37.7792782},
+	}}
+
+	toENU, fromENU := project.LocalENU(survey[0][0])
+
+	local := project.Polygon(survey.Clone(), toENU)
+	fmt.Printf("area: %0.1f m^2\n", math.Abs(planar.Area(local)))
+
+	// simplify with a 1 meter threshold, then back to lon/lat
+	simplified := simplify.DouglasPeucker(1).Simplify(local).(orb.Polygon)
+	simplified = project.Polygon(simplified, fromENU)
+	fmt.Printf("points: %d\n", len(simplified[0]))
+	// Output:
+	// area: 6063.1 m^2
+	// points: 5
+}
@@ -1,5 +1,6 @@
-// Package project defines projections to and from Mercator and WGS84
-// along with helpers to apply them to orb geometry types.
+// Package project defines projections to and from Mercator and WGS84,
+// parametric ellipsoidal projections such as Transverse Mercator, UTM and
+// Lambert Conformal Conic, along with helpers to apply them to orb geometry types.
 package project
 
 import "github.com/paulmach/orb"
@@ -94,7 +95,8 @@ func Collection(c orb.Collection, proj orb.Projection) orb.Collection {
 	return c
 }
 
-// Bound is a helper to project a rectangle.
+// Bound is a helper to project a rectangle. Only the corners are projected,
+// use BoundDensified for projections that do not preserve the axes.
 func Bound(bound orb.Bound, proj orb.Projection) orb.Bound {
 	min := proj(bound.Min)
 	return orb.Bound{Min: min, Max: min}.Extend(proj(bound.Max))
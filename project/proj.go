@@ -0,0 +1,260 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+var (
+	// ErrUnsupportedProjection is returned when parsing a PROJ string
+	// for a projection that is not implemented.
+	ErrUnsupportedProjection = errors.New("project: unsupported projection")
+
+	// ErrUnknownEPSG is returned by FromEPSG if the code is not in the table.
+	ErrUnknownEPSG = errors.New("project: unknown epsg code")
+)
+
+// ellipsoids by their PROJ +ellps name.
+var projEllipsoids = map[string]Ellipsoid{
+	"WGS84":    EllipsoidWGS84,
+	"GRS80":    EllipsoidGRS80,
+	"airy":     EllipsoidAiry1830,
+	"mod_airy": {A: 6377340.189, F: 1 - 6356034.446/6377340.189},
+	"bessel":   EllipsoidBessel1841,
+	"clrk66":   EllipsoidClarke1866,
+	"intl":     EllipsoidInternational1924,
+}
+
+// ellipsoids of the datums by their PROJ +datum name.
+var projDatums = map[string]string{
+	"WGS84":   "WGS84",
+	"NAD83":   "GRS80",
+	"NAD27":   "clrk66",
+	"OSGB36":  "airy",
+	"potsdam": "bessel",
+	"ire65":   "mod_airy",
+	"nzgd49":  "intl",
+}
+
+// units by their PROJ +units name and their size in meters.
+var projUnits = map[string]float64{
+	"m":     1,
+	"km":    1000,
+	"ft":    0.3048,
+	"us-ft": 1200.0 / 3937.0,
+}
+
+// ParsePROJ returns the forward and inverse projections for a PROJ string,
+// e.g. "+proj=utm +zone=33 +ellps=GRS80". The projections tmerc, utm, lcc,
+// aea, stere for the poles, ups, eqc, merc and longlat are supported, along
+// with the ellipsoid, +datum for its ellipsoid, and +units. Datum shifts,
+// e.g. +towgs84, are ignored, use DatumTransform. The forward projection
+// takes lon/lat on the datum of the definition.
+func ParsePROJ(s string) (forward, inverse orb.Projection, err error) {
+	args := make(map[string]string)
+	for _, f := range strings.Fields(s) {
+		f = strings.TrimPrefix(f, "+")
+		if i := strings.IndexByte(f, '='); i >= 0 {
+			args[f[:i]] = f[i+1:]
+		} else if f != "" {
+			args[f] = ""
+		}
+	}
+
+	pa := &projArgs{args: args}
+	params := Parameters{
+		Ellipsoid:     pa.ellipsoid(),
+		Lon0:          pa.float("lon_0", 0),
+		Lat0:          pa.float("lat_0", 0),
+		Lat1:          pa.float("lat_1", 0),
+		K0:            pa.float("k_0", pa.float("k", 1)),
+		FalseEasting:  pa.float("x_0", 0),
+		FalseNorthing: pa.float("y_0", 0),
+	}
+	params.Lat2 = pa.float("lat_2", params.Lat1)
+
+	toMeter := pa.float("to_meter", 1)
+	if u, ok := args["units"]; ok {
+		if toMeter, ok = projUnits[u]; !ok {
+			pa.fail("units", fmt.Errorf("unknown units"))
+		}
+	}
+
+	switch proj := args["proj"]; proj {
+	case "longlat", "latlong", "lonlat", "latlon":
+		identity := func(p orb.Point) orb.Point { return p }
+		forward, inverse = identity, identity
+	case "tmerc", "etmerc":
+		forward, inverse = TransverseMercator(params)
+	case "utm":
+		zone := int(pa.float("zone", 0))
+		if zone < 1 || zone > 60 {
+			pa.fail("zone", fmt.Errorf("must be between 1 and 60"))
+			break
+		}
+
+		params.Lon0 = float64(6*zone - 183)
+		params.Lat0 = 0
+		params.K0 = 0.9996
+		params.FalseEasting = 500000
+		params.FalseNorthing = 0
+		if _, ok := args["south"]; ok {
+			params.FalseNorthing = 10000000
+		}
+
+		forward, inverse = TransverseMercator(params)
+	case "lcc":
+		if _, ok := args["lat_1"]; !ok {
+			params.Lat1, params.Lat2 = params.Lat0, params.Lat0
+		}
+		forward, inverse = LambertConformalConic(params)
+	case "aea":
+		forward, inverse = AlbersEqualArea(params)
+	case "stere", "ups":
+		if proj == "ups" {
+			params.Lat0 = 90
+			if _, ok := args["south"]; ok {
+				params.Lat0 = -90
+			}
+			params.Lon0 = 0
+			params.K0 = 0.994
+			params.FalseEasting = 2000000
+			params.FalseNorthing = 2000000
+		}
+
+		if math.Abs(params.Lat0) != 90 {
+			return nil, nil, fmt.Errorf("%w: only polar stereographic is supported", ErrUnsupportedProjection)
+		}
+
+		params.Lat1 = pa.float("lat_ts", 0)
+		forward, inverse = PolarStereographic(params)
+	case "eqc":
+		params.Lat1 = pa.float("lat_ts", 0)
+		forward, inverse = Equirectangular(params)
+	case "merc":
+		if _, ok := args["lat_ts"]; ok {
+			e2 := params.withDefaults().Ellipsoid.e2()
+			params.K0 = msfn(deg2rad(pa.float("lat_ts", 0)), e2)
+		}
+		forward, inverse = mercatorProjection(params)
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnsupportedProjection, proj)
+	}
+
+	if pa.err != nil {
+		return nil, nil, pa.err
+	}
+
+	if toMeter != 1 {
+		forward, inverse = scaleUnits(forward, inverse, toMeter)
+	}
+
+	return forward, inverse, nil
+}
+
+// projArgs parses the values of a PROJ string keeping the first error.
+type projArgs struct {
+	args map[string]string
+	err  error
+}
+
+func (pa *projArgs) fail(key string, err error) {
+	if pa.err == nil {
+		pa.err = fmt.Errorf("project: invalid +%s=%s: %v", key, pa.args[key], err)
+	}
+}
+
+func (pa *projArgs) float(key string, def float64) float64 {
+	v, ok := pa.args[key]
+	if !ok {
+		return def
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		pa.fail(key, err)
+		return def
+	}
+
+	return f
+}
+
+func (pa *projArgs) ellipsoid() Ellipsoid {
+	e := EllipsoidWGS84
+	if d, ok := pa.args["datum"]; ok {
+		name, ok := projDatums[d]
+		if !ok {
+			pa.fail("datum", fmt.Errorf("unknown datum"))
+		}
+		e = projEllipsoids[name]
+	}
+
+	if name, ok := pa.args["ellps"]; ok {
+		if e, ok = projEllipsoids[name]; !ok {
+			pa.fail("ellps", fmt.Errorf("unknown ellipsoid"))
+		}
+	}
+
+	if _, ok := pa.args["R"]; ok {
+		return Ellipsoid{A: pa.float("R", 0)}
+	}
+
+	if _, ok := pa.args["a"]; ok {
+		e.A = pa.float("a", e.A)
+		switch {
+		case pa.args["rf"] != "":
+			e.F = 1 / pa.float("rf", 0)
+		case pa.args["f"] != "":
+			e.F = pa.float("f", 0)
+		case pa.args["b"] != "":
+			e.F = 1 - pa.float("b", 0)/e.A
+		}
+	}
+
+	return e
+}
+
+// scaleUnits wraps the projections so the projected units are not meters.
+func scaleUnits(forward, inverse orb.Projection, toMeter float64) (orb.Projection, orb.Projection) {
+	f := func(p orb.Point) orb.Point {
+		p = forward(p)
+		return orb.Point{p[0] / toMeter, p[1] / toMeter}
+	}
+
+	i := func(p orb.Point) orb.Point {
+		return inverse(orb.Point{p[0] * toMeter, p[1] * toMeter})
+	}
+
+	return f, i
+}
+
+// mercatorProjection is the ellipsoidal Mercator projection. If the
+// ellipsoid is a sphere this is the same as WGS84.ToMercator without
+// clamping the latitude.
+func mercatorProjection(params Parameters) (forward, inverse orb.Projection) {
+	p := params.withDefaults()
+	ak0 := p.Ellipsoid.A * p.K0
+	e := math.Sqrt(p.Ellipsoid.e2())
+
+	forward = func(g orb.Point) orb.Point {
+		return orb.Point{
+			p.FalseEasting + ak0*lonDiff(g[0], p.Lon0),
+			p.FalseNorthing - ak0*math.Log(tsfn(deg2rad(g[1]), e)),
+		}
+	}
+
+	inverse = func(g orb.Point) orb.Point {
+		t := math.Exp(-(g[1] - p.FalseNorthing) / ak0)
+		return orb.Point{
+			lonSum(p.Lon0, (g[0]-p.FalseEasting)/ak0),
+			rad2deg(phi2(t, e)),
+		}
+	}
+
+	return forward, inverse
+}
@@ -0,0 +1,169 @@
+package project
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestParsePROJ(t *testing.T) {
+	cases := []struct {
+		name     string
+		proj     string
+		point    orb.Point
+		expected orb.Point
+	}{
+		{
+			name:     "british national grid",
+			proj:     "+proj=tmerc +lat_0=49 +lon_0=-2 +k=0.9996012717 +x_0=400000 +y_0=-100000 +ellps=airy +units=m +no_defs",
+			point:    orb.Point{0.5, 50.5},
+			expected: orb.Point{577274.99, 69740.50},
+		},
+		{
+			name:     "utm",
+			proj:     "+proj=utm +zone=31 +south +datum=WGS84",
+			point:    orb.Point{3, 0},
+			expected: orb.Point{500000, 10000000},
+		},
+		{
+			name:     "lambert conformal conic in us feet",
+			proj:     "+proj=lcc +lat_0=27.83333333333333 +lon_0=-99 +lat_1=28.38333333333333 +lat_2=30.28333333333333 +x_0=609601.2192024384 +y_0=0 +ellps=clrk66 +units=us-ft",
+			point:    orb.Point{-96, 28.5},
+			expected: orb.Point{2963503.91, 254759.80},
+		},
+		{
+			name:     "lambert conformal conic one standard parallel",
+			proj:     "+proj=lcc +lat_0=18 +lon_0=-77 +k_0=1 +x_0=250000 +y_0=150000 +a=6378206.4 +b=6356583.8",
+			point:    orb.Point{-(76 + 56.0/60 + 37.26/3600), 17 + 55.0/60 + 55.80/3600},
+			expected: orb.Point{255966.58, 142493.51},
+		},
+		{
+			name:     "ups north",
+			proj:     "+proj=ups +datum=WGS84",
+			point:    orb.Point{44, 73},
+			expected: orb.Point{3320416.75, 632668.43},
+		},
+		{
+			name:     "polar stereographic",
+			proj:     "+proj=stere +lat_0=-90 +lat_ts=-71 +lon_0=70 +x_0=6000000 +y_0=6000000 +datum=WGS84",
+			point:    orb.Point{120, -75},
+			expected: orb.Point{7255380.79, 7053389.56},
+		},
+		{
+			name:     "spherical mercator",
+			proj:     "+proj=merc +a=6378137 +b=6378137 +lat_ts=0 +lon_0=0 +x_0=0 +y_0=0 +k=1",
+			point:    orb.Point{-122.416667, 37.783333},
+			expected: WGS84.ToMercator(orb.Point{-122.416667, 37.783333}),
+		},
+		{
+			name:     "sphere radius",
+			proj:     "+proj=eqc +R=6378137",
+			point:    orb.Point{10, 55},
+			expected: orb.Point{1113194.908, 6122571.994},
+		},
+		{
+			name:     "longlat",
+			proj:     "+proj=longlat +datum=WGS84 +no_defs",
+			point:    orb.Point{1, 2},
+			expected: orb.Point{1, 2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			forward, inverse, err := ParsePROJ(tc.proj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			checkProjection(t, forward, inverse, tc.point, tc.expected, 0.01)
+		})
+	}
+}
+
+func TestParsePROJ_errors(t *testing.T) {
+	cases := []struct {
+		name        string
+		proj        string
+		unsupported bool
+	}{
+		{name: "unknown projection", proj: "+proj=robin", unsupported: true},
+		{name: "no projection", proj: "+ellps=WGS84", unsupported: true},
+		{name: "oblique stereographic", proj: "+proj=stere +lat_0=52", unsupported: true},
+		{name: "invalid number", proj: "+proj=tmerc +lon_0=abc"},
+		{name: "invalid zone", proj: "+proj=utm +zone=61"},
+		{name: "unknown ellipsoid", proj: "+proj=tmerc +ellps=abc"},
+		{name: "unknown datum", proj: "+proj=tmerc +datum=abc"},
+		{name: "unknown units", proj: "+proj=tmerc +units=abc"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := ParsePROJ(tc.proj)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+
+			if errors.Is(err, ErrUnsupportedProjection) != tc.unsupported {
+				t.Errorf("incorrect error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFromEPSG(t *testing.T) {
+	sf := orb.Point{-122.416667, 37.783333}
+
+	forward, inverse, err := FromEPSG(32610)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	utmForward, _ := UTM(10, true)
+	checkProjection(t, forward, inverse, sf, utmForward(sf), 0)
+
+	forward, _, err = FromEPSG(4326)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p := forward(sf); p != sf {
+		t.Errorf("should be the identity: %v", p)
+	}
+
+	_, _, err = FromEPSG(1234)
+	if !errors.Is(err, ErrUnknownEPSG) {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	// all the definitions should parse and round trip
+	// points within the area of use of each
+	points := map[int]orb.Point{
+		4326: {1, 2}, 4269: {-100, 40}, 4267: {-100, 40}, 4258: {10, 50}, 4277: {-1, 52}, 4230: {10, 50},
+		3857: {-122, 37}, 3395: {-122, 37}, 4087: {-122, 37},
+		32661: {10, 85}, 32761: {10, -85}, 3031: {10, -75}, 3413: {-45, 75}, 3995: {10, 80},
+		27700: {-1, 52}, 2157: {-8, 53}, 2154: {2, 47}, 3006: {15, 60}, 2193: {174, -41},
+		5070: {-100, 40}, 2263: {-73.9, 40.7}, 3577: {135, -25},
+		32633: {16, 40}, 32733: {16, -40}, 26910: {-122, 40}, 26710: {-122, 40}, 25832: {10, 50}, 23031: {4, 45},
+	}
+
+	for code := range epsg {
+		if _, ok := points[code]; !ok {
+			t.Errorf("missing test point for %d", code)
+		}
+	}
+
+	for code, ll := range points {
+		forward, inverse, err := FromEPSG(code)
+		if err != nil {
+			t.Fatalf("error for %d: %v", code, err)
+		}
+
+		back := inverse(forward(ll))
+		if math.Abs(back[0]-ll[0]) > 1e-9 || math.Abs(back[1]-ll[1]) > 1e-9 {
+			t.Errorf("incorrect round trip for %d: %v", code, back)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package project
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// PolarStereographic returns the forward and inverse ellipsoidal Polar
+// Stereographic projection. The pole is north if Lat0 is positive, e.g. 90,
+// otherwise south. If Lat1 is set it is the latitude of true scale, else
+// the scale factor at the pole is K0. In the north, Lon0 is the meridian
+// pointing down from the pole and, in the south, the one pointing up.
+func PolarStereographic(params Parameters) (forward, inverse orb.Projection) {
+	p := params.withDefaults()
+	a := p.Ellipsoid.A
+	e2 := p.Ellipsoid.e2()
+	e := math.Sqrt(e2)
+
+	// flip the sign of the latitudes for the south pole
+	sign := 1.0
+	if p.Lat0 < 0 || (p.Lat0 == 0 && p.Lat1 < 0) {
+		sign = -1
+	}
+
+	// rho = scale * t
+	scale := 2 * a * p.K0 / math.Sqrt(math.Pow(1+e, 1+e)*math.Pow(1-e, 1-e))
+	if phic := deg2rad(sign * p.Lat1); p.Lat1 != 0 && math.Abs(phic-math.Pi/2) > 1e-12 {
+		scale = a * msfn(phic, e2) / tsfn(phic, e)
+	}
+
+	forward = func(g orb.Point) orb.Point {
+		rho := scale * tsfn(deg2rad(sign*g[1]), e)
+
+		sinLambda, cosLambda := math.Sincos(lonDiff(g[0], p.Lon0))
+		return orb.Point{
+			p.FalseEasting + rho*sinLambda,
+			p.FalseNorthing - sign*rho*cosLambda,
+		}
+	}
+
+	inverse = func(g orb.Point) orb.Point {
+		x := g[0] - p.FalseEasting
+		y := g[1] - p.FalseNorthing
+
+		phi := phi2(math.Hypot(x, y)/scale, e)
+		lambda := math.Atan2(x, -sign*y)
+
+		return orb.Point{lonSum(p.Lon0, lambda), sign * rad2deg(phi)}
+	}
+
+	return forward, inverse
+}
@@ -0,0 +1,41 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestPolarStereographic(t *testing.T) {
+	t.Run("north with scale factor", func(t *testing.T) {
+		// WGS 84 / UPS North, from EPSG guidance note 7-2
+		forward, inverse := PolarStereographic(Parameters{
+			Lat0:          90,
+			K0:            0.994,
+			FalseEasting:  2000000,
+			FalseNorthing: 2000000,
+		})
+
+		checkProjection(t, forward, inverse, orb.Point{44, 73}, orb.Point{3320416.75, 632668.43}, 0.01)
+	})
+
+	t.Run("south with latitude of true scale", func(t *testing.T) {
+		// WGS 84 / Australian Antarctic Polar Stereographic, from EPSG guidance note 7-2
+		forward, inverse := PolarStereographic(Parameters{
+			Lat0:          -90,
+			Lat1:          -71,
+			Lon0:          70,
+			FalseEasting:  6000000,
+			FalseNorthing: 6000000,
+		})
+
+		checkProjection(t, forward, inverse, orb.Point{120, -75}, orb.Point{7255380.79, 7053389.56}, 0.01)
+	})
+
+	t.Run("the pole", func(t *testing.T) {
+		forward, _ := PolarStereographic(Parameters{Lat0: -90})
+		if p := forward(orb.Point{10, -90}); !p.Equal(orb.Point{0, 0}) {
+			t.Errorf("pole should be at the origin: %v", p)
+		}
+	})
+}
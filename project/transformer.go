@@ -0,0 +1,98 @@
+package project
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// A Transformer converts points between two coordinate systems
+// in both directions.
+type Transformer interface {
+	Forward(orb.Point) orb.Point
+	Inverse(orb.Point) orb.Point
+}
+
+// Pair returns a Transformer for the forward and inverse projections,
+// e.g. the ones returned by UTM or FromEPSG.
+func Pair(forward, inverse orb.Projection) Transformer {
+	return &pair{forward: forward, inverse: inverse}
+}
+
+type pair struct {
+	forward, inverse orb.Projection
+}
+
+func (p *pair) Forward(point orb.Point) orb.Point {
+	return p.forward(point)
+}
+
+func (p *pair) Inverse(point orb.Point) orb.Point {
+	return p.inverse(point)
+}
+
+// Invert returns a Transformer with the directions swapped.
+func Invert(t Transformer) Transformer {
+	return Pair(t.Inverse, t.Forward)
+}
+
+// Compose returns a Transformer that applies a then b going forward,
+// and the inverse of b then the inverse of a going back. For example
+// to go from one projection to another compose the inverse of the first
+// with the second.
+func Compose(a, b Transformer) Transformer {
+	return Pair(
+		func(p orb.Point) orb.Point {
+			return b.Forward(a.Forward(p))
+		},
+		func(p orb.Point) orb.Point {
+			return a.Inverse(b.Inverse(p))
+		},
+	)
+}
+
+// BoundDensified projects the rectangle forward by sampling points along
+// its edges and returns the bound of the result. Projections that do not
+// preserve the axes, such as UTM or the conics, turn the edges into curves
+// so projecting the corners, like Bound does, is not enough. Each edge is
+// divided into the given number of segments, at least one. Points that
+// project to infinity or NaN, e.g. the poles in Mercator, are skipped.
+func BoundDensified(b orb.Bound, t Transformer, samples int) orb.Bound {
+	if samples < 1 {
+		samples = 1
+	}
+
+	result := orb.Bound{
+		Min: orb.Point{math.Inf(1), math.Inf(1)},
+		Max: orb.Point{math.Inf(-1), math.Inf(-1)},
+	}
+
+	add := func(p orb.Point) {
+		p = t.Forward(p)
+		if math.IsInf(p[0], 0) || math.IsInf(p[1], 0) ||
+			math.IsNaN(p[0]) || math.IsNaN(p[1]) {
+			return
+		}
+
+		result = result.Extend(p)
+	}
+
+	dx := (b.Max[0] - b.Min[0]) / float64(samples)
+	dy := (b.Max[1] - b.Min[1]) / float64(samples)
+	for i := 0; i < samples; i++ {
+		x := b.Min[0] + float64(i)*dx
+		y := b.Min[1] + float64(i)*dy
+
+		add(orb.Point{x, b.Min[1]})
+		add(orb.Point{b.Max[0], y})
+		add(orb.Point{b.Max[0] - float64(i)*dx, b.Max[1]})
+		add(orb.Point{b.Min[0], b.Max[1] - float64(i)*dy})
+	}
+
+	if result.Min[0] > result.Max[0] {
+		// nothing projected to a finite point
+		return orb.Bound{}
+	}
+
+	return result
+}
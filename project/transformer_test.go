@@ -0,0 +1,101 @@
+package project
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestCompose(t *testing.T) {
+	// UTM zone 32 to zone 33 via lon/lat
+	z32 := Pair(UTM(32, true))
+	z33 := Pair(UTM(33, true))
+	tr := Compose(Invert(z32), z33)
+
+	ll := orb.Point{12, 48}
+	p := tr.Forward(z32.Forward(ll))
+
+	expected := z33.Forward(ll)
+	if math.Abs(p[0]-expected[0]) > 1e-6 || math.Abs(p[1]-expected[1]) > 1e-6 {
+		t.Errorf("incorrect forward: %v != %v", p, expected)
+	}
+
+	back := z32.Inverse(tr.Inverse(p))
+	if math.Abs(back[0]-ll[0]) > 1e-9 || math.Abs(back[1]-ll[1]) > 1e-9 {
+		t.Errorf("incorrect inverse: %v != %v", back, ll)
+	}
+}
+
+func TestBoundDensified(t *testing.T) {
+	forward, inverse, err := FromEPSG(5070)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	albers := Pair(forward, inverse)
+
+	b := orb.Bound{Min: orb.Point{-120, 30}, Max: orb.Point{-70, 50}}
+
+	t.Run("finds the bulge", func(t *testing.T) {
+		corners := Bound(b, forward)
+		result := BoundDensified(b, albers, 50)
+
+		// the southern edge curves down and is lowest at the central meridian
+		bottom := forward(orb.Point{-96, 30})
+		if corners.Min[1] <= bottom[1] {
+			t.Errorf("corners should miss the bottom: %v", corners)
+		}
+
+		if math.Abs(result.Min[1]-bottom[1]) > 1 {
+			t.Errorf("incorrect min: %v != %v", result.Min[1], bottom[1])
+		}
+
+		if !result.Contains(corners.Min) || !result.Contains(corners.Max) {
+			t.Errorf("should contain the corners: %v", result)
+		}
+	})
+
+	t.Run("converges", func(t *testing.T) {
+		fine := BoundDensified(b, albers, 1000)
+		result := BoundDensified(b, albers, 21)
+
+		for i := 0; i < 2; i++ {
+			if math.Abs(result.Min[i]-fine.Min[i]) > 100 || math.Abs(result.Max[i]-fine.Max[i]) > 100 {
+				t.Errorf("not close: %v != %v", result, fine)
+			}
+		}
+	})
+
+	t.Run("one sample is the corners", func(t *testing.T) {
+		expected := orb.MultiPoint{
+			forward(b.Min), forward(b.Max),
+			forward(orb.Point{b.Min[0], b.Max[1]}),
+			forward(orb.Point{b.Max[0], b.Min[1]}),
+		}.Bound()
+
+		result := BoundDensified(b, albers, 0)
+		if !result.Equal(expected) {
+			t.Errorf("should be the corners: %v", result)
+		}
+	})
+
+	t.Run("skips infinity", func(t *testing.T) {
+		forward, inverse, err := FromEPSG(3395)
+		if err != nil {
+			t.Fatalf("error: %v", err)
+		}
+
+		world := orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, 90}}
+		result := BoundDensified(world, Pair(forward, inverse), 10)
+
+		for _, v := range []float64{result.Min[0], result.Min[1], result.Max[0], result.Max[1]} {
+			if math.IsInf(v, 0) || math.IsNaN(v) {
+				t.Errorf("should be finite: %v", result)
+			}
+		}
+
+		if result.Max[1] < 1e7 {
+			t.Errorf("should include the high latitudes: %v", result)
+		}
+	})
+}
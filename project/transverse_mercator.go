@@ -0,0 +1,140 @@
+package project
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// TransverseMercator returns the forward and inverse ellipsoidal Transverse
+// Mercator projection, as used by UTM and many national grids, given the
+// central meridian, latitude of origin, scale factor and false easting/northing.
+// It uses the Krüger series to 6th order in n, see C. F. F. Karney,
+// Transverse Mercator with an accuracy of a few nanometers, J. Geodesy 85 (2011),
+// and is accurate to less than a millimeter within 4000km of the central meridian.
+func TransverseMercator(params Parameters) (forward, inverse orb.Projection) {
+	p := params.withDefaults()
+	tm := newTransverseMercator(p.Ellipsoid)
+
+	k0a := p.K0 * tm.a
+	xi0, _ := tm.forward(deg2rad(p.Lat0), 0)
+
+	forward = func(g orb.Point) orb.Point {
+		xi, eta := tm.forward(deg2rad(g[1]), lonDiff(g[0], p.Lon0))
+		return orb.Point{
+			p.FalseEasting + k0a*eta,
+			p.FalseNorthing + k0a*(xi-xi0),
+		}
+	}
+
+	inverse = func(g orb.Point) orb.Point {
+		xi := (g[1]-p.FalseNorthing)/k0a + xi0
+		eta := (g[0] - p.FalseEasting) / k0a
+
+		phi, lambda := tm.inverse(xi, eta)
+		return orb.Point{lonSum(p.Lon0, lambda), rad2deg(phi)}
+	}
+
+	return forward, inverse
+}
+
+// transverseMercator holds the series coefficients for an ellipsoid.
+// The rectifying radius is a, and xi, eta are the projected coordinates
+// divided by it.
+type transverseMercator struct {
+	e, a        float64
+	alpha, beta [7]float64
+}
+
+func newTransverseMercator(el Ellipsoid) *transverseMercator {
+	n := el.F / (2 - el.F)
+	n2 := n * n
+	n3 := n2 * n
+	n4 := n3 * n
+	n5 := n4 * n
+	n6 := n5 * n
+
+	tm := &transverseMercator{
+		e: math.Sqrt(el.e2()),
+		a: el.A / (1 + n) * (1 + n2/4 + n4/64 + n6/256),
+	}
+
+	tm.alpha = [7]float64{
+		0,
+		n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180 - 127*n5/288 + 7891*n6/37800,
+		13*n2/48 - 3*n3/5 + 557*n4/1440 + 281*n5/630 - 1983433*n6/1935360,
+		61*n3/240 - 103*n4/140 + 15061*n5/26880 + 167603*n6/181440,
+		49561*n4/161280 - 179*n5/168 + 6601661*n6/7257600,
+		34729*n5/80640 - 3418889*n6/1995840,
+		212378941 * n6 / 319334400,
+	}
+
+	tm.beta = [7]float64{
+		0,
+		n/2 - 2*n2/3 + 37*n3/96 - n4/360 - 81*n5/512 + 96199*n6/604800,
+		n2/48 + n3/15 - 437*n4/1440 + 46*n5/105 - 1118711*n6/3870720,
+		17*n3/480 - 37*n4/840 - 209*n5/4480 + 5569*n6/90720,
+		4397*n4/161280 - 11*n5/504 - 830251*n6/7257600,
+		4583*n5/161280 - 108847*n6/3991680,
+		20648693 * n6 / 638668800,
+	}
+
+	return tm
+}
+
+// forward returns xi, eta for the latitude and longitude from
+// the central meridian in radians.
+func (tm *transverseMercator) forward(phi, lambda float64) (float64, float64) {
+	// conformal latitude
+	sinPhi := math.Sin(phi)
+	t := math.Sinh(math.Atanh(sinPhi) - tm.e*math.Atanh(tm.e*sinPhi))
+
+	sinLambda, cosLambda := math.Sincos(lambda)
+	xip := math.Atan2(t, cosLambda)
+	etap := math.Atanh(sinLambda / math.Sqrt(1+t*t))
+
+	xi, eta := xip, etap
+	for j := 1; j <= 6; j++ {
+		s, c := math.Sincos(2 * float64(j) * xip)
+		xi += tm.alpha[j] * s * math.Cosh(2*float64(j)*etap)
+		eta += tm.alpha[j] * c * math.Sinh(2*float64(j)*etap)
+	}
+
+	return xi, eta
+}
+
+// inverse returns the latitude and longitude from the central meridian
+// in radians for the xi and eta.
+func (tm *transverseMercator) inverse(xi, eta float64) (float64, float64) {
+	xip, etap := xi, eta
+	for j := 1; j <= 6; j++ {
+		s, c := math.Sincos(2 * float64(j) * xi)
+		xip -= tm.beta[j] * s * math.Cosh(2*float64(j)*eta)
+		etap -= tm.beta[j] * c * math.Sinh(2*float64(j)*eta)
+	}
+
+	sinhEtap := math.Sinh(etap)
+	sinXip, cosXip := math.Sincos(xip)
+	lambda := math.Atan2(sinhEtap, cosXip)
+
+	// tan of the conformal latitude, then newton's method for the latitude
+	taup := sinXip / math.Hypot(sinhEtap, cosXip)
+
+	e2 := tm.e * tm.e
+	tau := taup
+	for i := 0; i < 10; i++ {
+		sqrt1tau := math.Sqrt(1 + tau*tau)
+		sigma := math.Sinh(tm.e * math.Atanh(tm.e*tau/sqrt1tau))
+		taui := tau*math.Sqrt(1+sigma*sigma) - sigma*sqrt1tau
+
+		dtau := (taup - taui) / math.Sqrt(1+taui*taui) *
+			(1 + (1-e2)*tau*tau) / ((1 - e2) * sqrt1tau)
+		tau += dtau
+
+		if math.Abs(dtau) < 1e-14 {
+			break
+		}
+	}
+
+	return math.Atan(tau), lambda
+}
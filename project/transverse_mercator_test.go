@@ -0,0 +1,114 @@
+package project
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/mercator"
+)
+
+// checkProjection checks the forward projection against the expected value
+// and that the inverse returns the original point.
+func checkProjection(t testing.TB, forward, inverse orb.Projection, ll, expected orb.Point, tolerance float64) {
+	t.Helper()
+
+	p := forward(ll)
+	if math.Abs(p[0]-expected[0]) > tolerance || math.Abs(p[1]-expected[1]) > tolerance {
+		t.Errorf("incorrect projection: %v != %v", p, expected)
+	}
+
+	back := inverse(p)
+	if math.Abs(back[0]-ll[0]) > 1e-9 || math.Abs(back[1]-ll[1]) > 1e-9 {
+		t.Errorf("incorrect inverse: %v != %v", back, ll)
+	}
+}
+
+func TestTransverseMercator(t *testing.T) {
+	// OSGB 1936 / British National Grid, from EPSG guidance note 7-2
+	forward, inverse := TransverseMercator(Parameters{
+		Ellipsoid:     EllipsoidAiry1830,
+		Lon0:          -2,
+		Lat0:          49,
+		K0:            0.9996012717,
+		FalseEasting:  400000,
+		FalseNorthing: -100000,
+	})
+
+	checkProjection(t, forward, inverse, orb.Point{0.5, 50.5}, orb.Point{577274.99, 69740.50}, 0.01)
+
+	for _, city := range mercator.Cities {
+		ll := orb.Point{city[1], city[0]}
+		forward, inverse := UTM(UTMZone(ll))
+
+		back := inverse(forward(ll))
+		if math.Abs(back[0]-ll[0]) > 1e-9 || math.Abs(back[1]-ll[1]) > 1e-9 {
+			t.Errorf("incorrect round trip: %v != %v", back, ll)
+		}
+	}
+}
+
+func TestUTM(t *testing.T) {
+	cases := []struct {
+		name     string
+		zone     int
+		north    bool
+		point    orb.Point
+		expected orb.Point
+	}{
+		{
+			name:     "on the central meridian",
+			zone:     31,
+			north:    true,
+			point:    orb.Point{3, 0},
+			expected: orb.Point{500000, 0},
+		},
+		{
+			name:     "southern hemisphere",
+			zone:     31,
+			north:    false,
+			point:    orb.Point{3, 0},
+			expected: orb.Point{500000, 10000000},
+		},
+		{
+			// matches Snyder's series to a millimeter
+			name:     "london",
+			zone:     30,
+			north:    true,
+			point:    orb.Point{-0.1275, 51.507222},
+			expected: orb.Point{699337.826, 5710144.785},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			forward, inverse := UTM(tc.zone, tc.north)
+			checkProjection(t, forward, inverse, tc.point, tc.expected, 1e-3)
+		})
+	}
+}
+
+func TestUTMZone(t *testing.T) {
+	cases := []struct {
+		name  string
+		point orb.Point
+		zone  int
+		north bool
+	}{
+		{name: "first zone", point: orb.Point{-180, 10}, zone: 1, north: true},
+		{name: "last zone", point: orb.Point{179.9, -10}, zone: 60, north: false},
+		{name: "wrap", point: orb.Point{180, 10}, zone: 1, north: true},
+		{name: "san francisco", point: orb.Point{-122.4, 37.8}, zone: 10, north: true},
+		{name: "norway", point: orb.Point{5, 60}, zone: 32, north: true},
+		{name: "svalbard", point: orb.Point{10, 78}, zone: 33, north: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			zone, north := UTMZone(tc.point)
+			if zone != tc.zone || north != tc.north {
+				t.Errorf("incorrect zone: %v %v", zone, north)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package project
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// UTM returns the forward and inverse projections for the Universal
+// Transverse Mercator zone on the WGS84 ellipsoid. Zones are numbered 1 to 60
+// going east from -180 degrees longitude. Will panic if the zone is not valid.
+func UTM(zone int, north bool) (forward, inverse orb.Projection) {
+	if zone < 1 || zone > 60 {
+		panic(fmt.Sprintf("project: invalid UTM zone %d", zone))
+	}
+
+	params := Parameters{
+		Ellipsoid:    EllipsoidWGS84,
+		Lon0:         float64(6*zone - 183),
+		K0:           0.9996,
+		FalseEasting: 500000,
+	}
+
+	if !north {
+		params.FalseNorthing = 10000000
+	}
+
+	return TransverseMercator(params)
+}
+
+// UTMZone returns the UTM zone containing the lon/lat point and if it's
+// in the northern hemisphere. The exceptions for southwest Norway and
+// Svalbard are included.
+func UTMZone(p orb.Point) (zone int, north bool) {
+	lon := p[0]
+	if lon < -180 || lon >= 180 {
+		lon = math.Remainder(lon, 360)
+		if lon == 180 {
+			lon = -180
+		}
+	}
+
+	lat := p[1]
+	zone = int(math.Floor((lon+180)/6)) + 1
+
+	if lat >= 56 && lat < 64 && lon >= 3 && lon < 12 {
+		zone = 32
+	}
+
+	if lat >= 72 && lat <= 84 && lon >= 0 && lon < 42 {
+		switch {
+		case lon < 9:
+			zone = 31
+		case lon < 21:
+			zone = 33
+		case lon < 33:
+			zone = 35
+		default:
+			zone = 37
+		}
+	}
+
+	return zone, lat >= 0
+}
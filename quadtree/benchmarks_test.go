@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
 	"github.com/paulmach/orb/planar"
 )
 
@@ -194,3 +195,101 @@ func BenchmarkRandomKNearest100(b *testing.B) {
 		qt.KNearest(buf[:0], orb.Point{r.Float64(), r.Float64()}, 100)
 	}
 }
+
+func BenchmarkRandomKNearestDistance10(b *testing.B) {
+	r := rand.New(rand.NewSource(43))
+
+	qt := New(orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, 90}})
+	for i := 0; i < 1000; i++ {
+		p := orb.Point{360*r.Float64() - 180, 180*r.Float64() - 90}
+		err := qt.Add(p)
+		if err != nil {
+			b.Fatalf("unexpected error for %v: %v", p, err)
+		}
+	}
+
+	buf := make([]orb.Pointer, 0, 10)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		qt.KNearestDistance(buf[:0], orb.Point{360*r.Float64() - 180, 180*r.Float64() - 90}, 10, geo.Distance)
+	}
+}
+
+func BenchmarkRandomInRadius(b *testing.B) {
+	r := rand.New(rand.NewSource(43))
+
+	qt := New(orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, 90}})
+	for i := 0; i < 1000; i++ {
+		p := orb.Point{360*r.Float64() - 180, 180*r.Float64() - 90}
+		err := qt.Add(p)
+		if err != nil {
+			b.Fatalf("unexpected error for %v: %v", p, err)
+		}
+	}
+
+	buf := make([]orb.Pointer, 0, 50)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		qt.InRadius(buf[:0], orb.Point{360*r.Float64() - 180, 180*r.Float64() - 90}, 1000000, nil)
+	}
+}
+
+func BenchmarkExtentsAdd(b *testing.B) {
+	r := rand.New(rand.NewSource(22))
+	e := NewExtents(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	bounds := randomBounds(r, 10000, 0.01)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := e.Add(bounds[i%len(bounds)])
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkExtentsRandomIntersecting1000(b *testing.B) {
+	r := rand.New(rand.NewSource(43))
+
+	e := NewExtents(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	for _, bound := range randomBounds(r, 1000, 0.05) {
+		err := e.Add(bound)
+		if err != nil {
+			b.Fatalf("unexpected error for %v: %v", bound, err)
+		}
+	}
+
+	var buf []orb.Bounder
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := orb.Point{r.Float64(), r.Float64()}
+		buf = e.Intersecting(buf, orb.Bound{Min: p, Max: p}.Pad(0.1))
+	}
+}
+
+func BenchmarkExtentsRandomKNearest10(b *testing.B) {
+	r := rand.New(rand.NewSource(43))
+
+	e := NewExtents(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	for _, bound := range randomBounds(r, 1000, 0.05) {
+		err := e.Add(bound)
+		if err != nil {
+			b.Fatalf("unexpected error for %v: %v", bound, err)
+		}
+	}
+
+	buf := make([]orb.Bounder, 0, 10)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.KNearest(buf[:0], orb.Point{r.Float64(), r.Float64()}, 10)
+	}
+}
@@ -0,0 +1,287 @@
+package quadtree
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/paulmach/orb"
+)
+
+// Concurrent is a quadtree that is safe for concurrent use by multiple
+// goroutines. Reads use an immutable snapshot of the tree and never block.
+// Writes are serialized, copy the nodes they change, so the snapshots being
+// read are not affected, and then publish a new snapshot.
+type Concurrent struct {
+	mu   sync.Mutex
+	tree atomic.Value // *Quadtree
+}
+
+// NewConcurrent creates a new concurrent quadtree for the given bound.
+// Added points must be within this bound.
+func NewConcurrent(bound orb.Bound) *Concurrent {
+	c := &Concurrent{}
+	c.tree.Store(New(bound))
+
+	return c
+}
+
+// Bound returns the bounds used for the quad tree.
+func (c *Concurrent) Bound() orb.Bound {
+	return c.Snapshot().Bound()
+}
+
+// Len returns the number of pointers in the tree.
+func (c *Concurrent) Len() int {
+	return c.Snapshot().Len()
+}
+
+// Snapshot returns the current state of the tree. It will not change and
+// can be used for multiple consistent reads. It must not be modified.
+func (c *Concurrent) Snapshot() *Quadtree {
+	return c.tree.Load().(*Quadtree)
+}
+
+// Add puts an object into the quad tree, must be within the quadtree bounds.
+// Reads that have already started do not see the new point.
+func (c *Concurrent) Add(p orb.Pointer) error {
+	if p == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	q, err := c.Snapshot().addCopy(p)
+	if err != nil {
+		return err
+	}
+
+	c.tree.Store(q)
+	return nil
+}
+
+// Remove will remove the pointer from the quadtree. By default it'll match
+// using the points, but a FilterFunc can be provided for a more specific test
+// if there are elements with the same point value in the tree.
+// See Quadtree.Remove for more details.
+func (c *Concurrent) Remove(p orb.Pointer, eq FilterFunc) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	q, removed := c.Snapshot().removeCopy(p, eq)
+	if removed {
+		c.tree.Store(q)
+	}
+
+	return removed
+}
+
+// Clear removes all the pointers from the tree.
+// Reads that have already started still see them.
+func (c *Concurrent) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tree.Store(New(c.Snapshot().bound))
+}
+
+// Replace removes the pointer matching old, like Remove, and adds the new
+// pointer as one change. Readers see either the old or the new pointer but
+// never neither or both, e.g. when tracking moving objects. Returns false
+// and does not add the new pointer if nothing matched.
+func (c *Concurrent) Replace(old orb.Pointer, eq FilterFunc, p orb.Pointer) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.Snapshot().bound.Contains(p.Point()) {
+		return false, ErrPointOutsideOfBounds
+	}
+
+	q, removed := c.Snapshot().removeCopy(old, eq)
+	if !removed {
+		return false, nil
+	}
+
+	q, err := q.addCopy(p)
+	if err != nil {
+		return false, err
+	}
+
+	c.tree.Store(q)
+	return true, nil
+}
+
+// Find returns the closest Value/Pointer in the quadtree.
+func (c *Concurrent) Find(p orb.Point) orb.Pointer {
+	return c.Snapshot().Find(p)
+}
+
+// Matching returns the closest Value/Pointer in the quadtree for which
+// the given filter function returns true.
+func (c *Concurrent) Matching(p orb.Point, f FilterFunc) orb.Pointer {
+	return c.Snapshot().Matching(p, f)
+}
+
+// KNearest returns k closest Value/Pointer in the quadtree.
+// See Quadtree.KNearest for more details.
+func (c *Concurrent) KNearest(buf []orb.Pointer, p orb.Point, k int, maxDistance ...float64) []orb.Pointer {
+	return c.Snapshot().KNearest(buf, p, k, maxDistance...)
+}
+
+// KNearestMatching returns k closest Value/Pointer in the quadtree for which
+// the given filter function returns true. See Quadtree.KNearestMatching for more details.
+func (c *Concurrent) KNearestMatching(buf []orb.Pointer, p orb.Point, k int, f FilterFunc, maxDistance ...float64) []orb.Pointer {
+	return c.Snapshot().KNearestMatching(buf, p, k, f, maxDistance...)
+}
+
+// Walk calls the function for every pointer in the current snapshot
+// of the tree until it returns false.
+func (c *Concurrent) Walk(fn func(p orb.Pointer) bool) {
+	c.Snapshot().Walk(fn)
+}
+
+// KNearestDistance returns k closest Value/Pointer in the quadtree using the
+// distance function. See Quadtree.KNearestDistance for more details.
+func (c *Concurrent) KNearestDistance(buf []orb.Pointer, p orb.Point, k int, df orb.DistanceFunc, maxDistance ...float64) []orb.Pointer {
+	return c.Snapshot().KNearestDistance(buf, p, k, df, maxDistance...)
+}
+
+// KNearestDistanceMatching returns k closest Value/Pointer in the quadtree using
+// the distance function and for which the given filter function returns true.
+// See Quadtree.KNearestDistanceMatching for more details.
+func (c *Concurrent) KNearestDistanceMatching(buf []orb.Pointer, p orb.Point, k int, df orb.DistanceFunc, f FilterFunc, maxDistance ...float64) []orb.Pointer {
+	return c.Snapshot().KNearestDistanceMatching(buf, p, k, df, f, maxDistance...)
+}
+
+// InRadius returns a slice with all the pointers in the quadtree within the
+// distance, in meters, of the center. See Quadtree.InRadius for more details.
+func (c *Concurrent) InRadius(buf []orb.Pointer, center orb.Point, meters float64, df orb.DistanceFunc) []orb.Pointer {
+	return c.Snapshot().InRadius(buf, center, meters, df)
+}
+
+// InRadiusMatching returns a slice with all the pointers in the quadtree within
+// the distance, in meters, of the center and matching the given filter function.
+// See Quadtree.InRadiusMatching for more details.
+func (c *Concurrent) InRadiusMatching(buf []orb.Pointer, center orb.Point, meters float64, df orb.DistanceFunc, f FilterFunc) []orb.Pointer {
+	return c.Snapshot().InRadiusMatching(buf, center, meters, df, f)
+}
+
+// InBound returns a slice with all the pointers in the quadtree that are
+// within the given bound. See Quadtree.InBound for more details.
+func (c *Concurrent) InBound(buf []orb.Pointer, b orb.Bound) []orb.Pointer {
+	return c.Snapshot().InBound(buf, b)
+}
+
+// InBoundMatching returns a slice with all the pointers in the quadtree that are
+// within the given bound and matching the give filter function.
+// See Quadtree.InBoundMatching for more details.
+func (c *Concurrent) InBoundMatching(buf []orb.Pointer, b orb.Bound, f FilterFunc) []orb.Pointer {
+	return c.Snapshot().InBoundMatching(buf, b, f)
+}
+
+// addCopy returns a new tree with the pointer added. The nodes on the path
+// to the new point are copied, the rest are shared with q.
+func (q *Quadtree) addCopy(p orb.Pointer) (*Quadtree, error) {
+	point := p.Point()
+	if !q.bound.Contains(point) {
+		return nil, ErrPointOutsideOfBounds
+	}
+
+	result := &Quadtree{bound: q.bound, count: q.count + 1}
+	if q.root == nil {
+		result.root = &node{Value: p}
+	} else if q.root.Value == nil {
+		root := *q.root
+		root.Value = p
+		result.root = &root
+	} else {
+		result.root = addCopy(q.root, p, point,
+			q.bound.Min[0], q.bound.Max[0],
+			q.bound.Min[1], q.bound.Max[1],
+		)
+	}
+
+	return result, nil
+}
+
+// addCopy is the recursive search to find a place to add the point,
+// the same as Quadtree.add, copying the nodes along the way.
+func addCopy(n *node, p orb.Pointer, point orb.Point, left, right, bottom, top float64) *node {
+	c := *n
+
+	var i int
+	i, left, right, bottom, top = descend(point, left, right, bottom, top)
+
+	child := n.Children[i]
+	if child == nil {
+		c.Children[i] = &node{Value: p}
+	} else if child.Value == nil {
+		cc := *child
+		cc.Value = p
+		c.Children[i] = &cc
+	} else {
+		c.Children[i] = addCopy(child, p, point, left, right, bottom, top)
+	}
+
+	return &c
+}
+
+// removeCopy returns a new tree with the pointer removed. The nodes on the
+// path to the removed value and the ones shuffled up are copied.
+func (q *Quadtree) removeCopy(p orb.Pointer, eq FilterFunc) (*Quadtree, bool) {
+	target := q.matchingNode(p, eq)
+	if target == nil {
+		return q, false
+	}
+
+	// values are always on the path of their point from the root.
+	root := removeCopy(q.root, target, target.Value.Point(),
+		q.bound.Min[0], q.bound.Max[0],
+		q.bound.Min[1], q.bound.Max[1],
+	)
+
+	return &Quadtree{bound: q.bound, root: root, count: q.count - 1}, true
+}
+
+func removeCopy(n, target *node, point orb.Point, left, right, bottom, top float64) *node {
+	c := *n
+	if n == target {
+		c.Value = nil
+		removeNodeCopy(&c)
+		return &c
+	}
+
+	var i int
+	i, left, right, bottom, top = descend(point, left, right, bottom, top)
+	c.Children[i] = removeCopy(n.Children[i], target, point, left, right, bottom, top)
+
+	return &c
+}
+
+// removeNodeCopy is the same as removeNode but copies the children
+// it changes. The node itself must already be a copy.
+func removeNodeCopy(n *node) bool {
+	i := -1
+	for j, c := range n.Children {
+		if c != nil {
+			i = j
+			break
+		}
+	}
+
+	if i == -1 {
+		return true
+	}
+
+	child := *n.Children[i]
+	n.Value = child.Value
+	child.Value = nil
+
+	if removeNodeCopy(&child) {
+		n.Children[i] = nil
+	} else {
+		n.Children[i] = &child
+	}
+
+	return false
+}
@@ -0,0 +1,199 @@
+package quadtree
+
+import (
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+type vehicle struct {
+	ID       int
+	Location orb.Point
+}
+
+func (v vehicle) Point() orb.Point {
+	return v.Location
+}
+
+func TestConcurrent(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	bound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}}
+
+	c := NewConcurrent(bound)
+	qt := New(bound)
+
+	// the same operations should give the same tree
+	var points []orb.Point
+	for i := 0; i < 1000; i++ {
+		p := orb.Point{r.Float64(), r.Float64()}
+		points = append(points, p)
+
+		if err := c.Add(p); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		qt.Add(p)
+	}
+
+	for i := 0; i < len(points); i += 3 {
+		if c.Remove(points[i], nil) != qt.Remove(points[i], nil) {
+			t.Errorf("remove result not the same")
+		}
+	}
+
+	if !reflect.DeepEqual(c.Snapshot(), qt) {
+		t.Errorf("trees should be the same")
+	}
+
+	if c.Remove(orb.Point{2, 2}, nil) {
+		t.Errorf("should not remove point not in the tree")
+	}
+
+	if err := c.Add(orb.Point{2, 2}); err != ErrPointOutsideOfBounds {
+		t.Errorf("incorrect error: %v", err)
+	}
+}
+
+func TestConcurrent_snapshot(t *testing.T) {
+	r := rand.New(rand.NewSource(43))
+	c := NewConcurrent(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+
+	var points []orb.Point
+	for i := 0; i < 100; i++ {
+		points = append(points, orb.Point{r.Float64(), r.Float64()})
+		c.Add(points[i])
+	}
+
+	before := c.Snapshot()
+	for _, p := range points[:50] {
+		c.Remove(p, nil)
+	}
+
+	if l := len(before.InBound(nil, before.Bound())); l != 100 {
+		t.Errorf("snapshot should not change: %v", l)
+	}
+
+	if l := len(c.InBound(nil, c.Bound())); l != 50 {
+		t.Errorf("incorrect number of points: %v", l)
+	}
+
+	if l := before.Len(); l != 100 {
+		t.Errorf("incorrect snapshot length: %v", l)
+	}
+
+	if l := c.Len(); l != 50 {
+		t.Errorf("incorrect length: %v", l)
+	}
+
+	c.Clear()
+	if l := c.Len(); l != 0 {
+		t.Errorf("should be cleared: %v", l)
+	}
+
+	if l := before.Len(); l != 100 {
+		t.Errorf("snapshot should not be cleared: %v", l)
+	}
+}
+
+func TestConcurrent_Replace(t *testing.T) {
+	c := NewConcurrent(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	c.Add(vehicle{ID: 1, Location: orb.Point{0.1, 0.1}})
+
+	ok, err := c.Replace(vehicle{ID: 1, Location: orb.Point{0.1, 0.1}}, nil, vehicle{ID: 1, Location: orb.Point{0.9, 0.9}})
+	if !ok || err != nil {
+		t.Errorf("should replace: %v %v", ok, err)
+	}
+
+	if v := c.Find(orb.Point{0, 0}).(vehicle); !v.Location.Equal(orb.Point{0.9, 0.9}) {
+		t.Errorf("should be moved: %v", v)
+	}
+
+	ok, err = c.Replace(vehicle{ID: 2, Location: orb.Point{0.5, 0.5}}, nil, vehicle{ID: 2})
+	if ok || err != nil {
+		t.Errorf("should not replace: %v %v", ok, err)
+	}
+
+	ok, err = c.Replace(vehicle{ID: 1, Location: orb.Point{0.9, 0.9}}, nil, vehicle{ID: 1, Location: orb.Point{2, 2}})
+	if ok || err != ErrPointOutsideOfBounds {
+		t.Errorf("should be outside of bounds: %v %v", ok, err)
+	}
+
+	if l := len(c.InBound(nil, c.Bound())); l != 1 {
+		t.Errorf("should still have the vehicle: %v", l)
+	}
+}
+
+// TestConcurrent_race should be run with the race detector, go test -race.
+func TestConcurrent_race(t *testing.T) {
+	const vehicles = 100
+
+	bound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}}
+	c := NewConcurrent(bound)
+
+	r := rand.New(rand.NewSource(44))
+	fleet := make([]vehicle, vehicles)
+	for i := range fleet {
+		fleet[i] = vehicle{ID: i, Location: orb.Point{r.Float64(), r.Float64()}}
+		c.Add(fleet[i])
+	}
+
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+
+	// the writer moves the vehicles around
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+
+		for i := 0; i < 5000; i++ {
+			v := fleet[i%vehicles]
+			moved := vehicle{ID: v.ID, Location: orb.Point{r.Float64(), r.Float64()}}
+
+			ok, err := c.Replace(v, func(p orb.Pointer) bool {
+				return p.(vehicle).ID == v.ID
+			}, moved)
+			if !ok || err != nil {
+				t.Errorf("should replace: %v %v", ok, err)
+				return
+			}
+
+			fleet[i%vehicles] = moved
+		}
+	}()
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(seed))
+			var buf []orb.Pointer
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				p := orb.Point{r.Float64(), r.Float64()}
+				buf = c.KNearest(buf, p, 5)
+				if len(buf) != 5 {
+					t.Errorf("incorrect number of nearest: %v", len(buf))
+					return
+				}
+
+				// every vehicle is always in the tree exactly once
+				buf = c.InBound(buf, bound)
+				if len(buf) != vehicles {
+					t.Errorf("incorrect number of vehicles: %v", len(buf))
+					return
+				}
+			}
+		}(int64(i))
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,356 @@
+package quadtree
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"unsafe"
+
+	"github.com/paulmach/orb"
+)
+
+var (
+	// ErrMissingID is returned when writing a tree with a value that does
+	// not implement the Identifier interface.
+	ErrMissingID = errors.New("quadtree: value does not have an id")
+
+	// ErrInvalidData is returned when reading data that is not a serialized tree.
+	ErrInvalidData = errors.New("quadtree: invalid data")
+)
+
+const (
+	quadtreeMagic   = "ORBQ"
+	extentsMagic    = "ORBE"
+	encodingVersion = 1
+
+	// headerSize is the magic, version, bound, number of values and number
+	// of nodes. It's a multiple of 8 so the nodes are aligned if the data is.
+	headerSize = 56
+
+	// the number of 8 byte words used for each node and item.
+	quadtreeNodeWords = 4
+	extentsNodeWords  = 2
+	extentsItemWords  = 5
+
+	maxInt = uint64(^uint(0) >> 1)
+)
+
+var hostLittleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// Identifier is implemented by values with an ID. The values of a tree must
+// implement it to be written, only the ID is saved with the point or bound.
+type Identifier interface {
+	ID() uint64
+}
+
+// Item is a point with an ID. Trees read using ReadFrom contain Items.
+type Item struct {
+	point orb.Point
+	id    uint64
+}
+
+// NewItem creates an item for the point and ID.
+func NewItem(p orb.Point, id uint64) Item {
+	return Item{point: p, id: id}
+}
+
+// Point returns the location of the item.
+func (i Item) Point() orb.Point {
+	return i.point
+}
+
+// ID returns the id of the item.
+func (i Item) ID() uint64 {
+	return i.id
+}
+
+// BoundItem is a bound with an ID. Extents read using ReadFrom contain BoundItems.
+type BoundItem struct {
+	bound orb.Bound
+	id    uint64
+}
+
+// NewBoundItem creates an item for the bound and ID.
+func NewBoundItem(b orb.Bound, id uint64) BoundItem {
+	return BoundItem{bound: b, id: id}
+}
+
+// Bound returns the bound of the item.
+func (i BoundItem) Bound() orb.Bound {
+	return i.bound
+}
+
+// ID returns the id of the item.
+func (i BoundItem) ID() uint64 {
+	return i.id
+}
+
+// WriteTo writes the tree in a compact binary format that can be read using
+// ReadFrom or used directly with Load. The nodes are written in pre-order
+// with the point and ID of their value. Every value must implement the
+// Identifier interface, e.g. an Item, and only its point and ID are written.
+func (q *Quadtree) WriteTo(w io.Writer) (int64, error) {
+	var nodes []uint64
+	if q.root != nil {
+		var err error
+		nodes, err = appendQuadtreeNode(nodes, q.root)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	numNodes := len(nodes) / quadtreeNodeWords
+	return writeData(w, quadtreeMagic, q.bound, uint64(q.count), uint64(numNodes), nodes)
+}
+
+// appendQuadtreeNode adds the node and its children in pre-order. A node is
+// the point and ID of the value and the index after the node's subtree,
+// shifted, with a bit for each child and one if there is a value.
+func appendQuadtreeNode(nodes []uint64, n *node) ([]uint64, error) {
+	i := len(nodes)
+	nodes = append(nodes, 0, 0, 0, 0)
+
+	var flags uint64
+	if n.Value != nil {
+		id, ok := n.Value.(Identifier)
+		if !ok {
+			return nil, ErrMissingID
+		}
+
+		p := n.Value.Point()
+		nodes[i] = math.Float64bits(p[0])
+		nodes[i+1] = math.Float64bits(p[1])
+		nodes[i+2] = id.ID()
+		flags |= flatValue
+	}
+
+	for j, c := range n.Children {
+		if c == nil {
+			continue
+		}
+
+		flags |= 1 << uint(j)
+
+		var err error
+		nodes, err = appendQuadtreeNode(nodes, c)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nodes[i+3] = uint64(len(nodes)/quadtreeNodeWords)<<flatFlagBits | flags
+	return nodes, nil
+}
+
+// ReadFrom reads a tree written by WriteTo into q, replacing its contents.
+// The values are Items with the point and ID. The nodes are decoded and
+// allocated, use Load to search the data directly without copying.
+func (q *Quadtree) ReadFrom(r io.Reader) (int64, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+
+	f, err := Load(data)
+	if err != nil {
+		return int64(len(data)), err
+	}
+
+	q.bound = f.bound
+	q.root = f.tree()
+	q.count = f.count
+	return int64(len(data)), nil
+}
+
+// WriteTo writes the index in a compact binary format that can be read using
+// ReadFrom or used directly with LoadExtents. The nodes are written in
+// pre-order followed by the items. Every item must implement the Identifier
+// interface, e.g. a BoundItem, and only its bound and ID are written.
+func (e *Extents) WriteTo(w io.Writer) (int64, error) {
+	root := e.root
+	if root == nil {
+		root = &extentsNode{}
+	}
+
+	nodes, items, err := appendExtentsNode(nil, nil, root)
+	if err != nil {
+		return 0, err
+	}
+
+	numNodes := len(nodes) / extentsNodeWords
+	return writeData(w, extentsMagic, e.bound, uint64(e.count), uint64(numNodes), nodes, items)
+}
+
+// appendExtentsNode adds the node and its children in pre-order, and their
+// items. A node is the end of its items and the index after its subtree,
+// shifted, with a bit if it has children.
+func appendExtentsNode(nodes, items []uint64, n *extentsNode) ([]uint64, []uint64, error) {
+	for _, item := range n.Items {
+		id, ok := item.(Identifier)
+		if !ok {
+			return nil, nil, ErrMissingID
+		}
+
+		b := item.Bound()
+		items = append(items,
+			math.Float64bits(b.Min[0]), math.Float64bits(b.Min[1]),
+			math.Float64bits(b.Max[0]), math.Float64bits(b.Max[1]),
+			id.ID(),
+		)
+	}
+
+	i := len(nodes)
+	nodes = append(nodes, uint64(len(items)/extentsItemWords), 0)
+
+	var flags uint64
+	if n.Children != nil {
+		flags = 1
+		for j := range n.Children {
+			var err error
+			nodes, items, err = appendExtentsNode(nodes, items, &n.Children[j])
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	nodes[i+1] = uint64(len(nodes)/extentsNodeWords)<<1 | flags
+	return nodes, items, nil
+}
+
+// ReadFrom reads an index written by WriteTo into e, replacing its contents.
+// The items are BoundItems with the bound and ID. The nodes are decoded and
+// allocated, use LoadExtents to search the data directly without copying.
+func (e *Extents) ReadFrom(r io.Reader) (int64, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+
+	f, err := LoadExtents(data)
+	if err != nil {
+		return int64(len(data)), err
+	}
+
+	e.bound = f.bound
+	e.root = f.tree()
+	e.count = f.count
+	return int64(len(data)), nil
+}
+
+// writeData writes the header and then the words, all little endian.
+func writeData(w io.Writer, magic string, bound orb.Bound, count, numNodes uint64, data ...[]uint64) (int64, error) {
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	binary.LittleEndian.PutUint32(header[4:], encodingVersion)
+
+	values := [6]uint64{
+		math.Float64bits(bound.Min[0]), math.Float64bits(bound.Min[1]),
+		math.Float64bits(bound.Max[0]), math.Float64bits(bound.Max[1]),
+		count, numNodes,
+	}
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(header[8+8*i:], v)
+	}
+
+	written, err := w.Write(header)
+	if err != nil {
+		return int64(written), err
+	}
+
+	total := int64(written)
+	for _, words := range data {
+		var b []byte
+		if hostLittleEndian {
+			b = uint64Bytes(words)
+		} else {
+			b = make([]byte, 8*len(words))
+			for i, v := range words {
+				binary.LittleEndian.PutUint64(b[8*i:], v)
+			}
+		}
+
+		n, err := w.Write(b)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// loadHeader checks the header and returns the bound, the number of values,
+// the number of nodes and the rest of the data.
+func loadHeader(data []byte, magic string) (orb.Bound, uint64, uint64, []byte, error) {
+	if len(data) < headerSize || string(data[:4]) != magic ||
+		binary.LittleEndian.Uint32(data[4:]) != encodingVersion {
+		return orb.Bound{}, 0, 0, nil, ErrInvalidData
+	}
+
+	var values [6]uint64
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint64(data[8+8*i:])
+	}
+
+	bound := orb.Bound{
+		Min: orb.Point{math.Float64frombits(values[0]), math.Float64frombits(values[1])},
+		Max: orb.Point{math.Float64frombits(values[2]), math.Float64frombits(values[3])},
+	}
+
+	if values[4] > maxInt {
+		return orb.Bound{}, 0, 0, nil, ErrInvalidData
+	}
+
+	return bound, values[4], values[5], data[headerSize:], nil
+}
+
+// loadWords returns the data as little endian words. If possible the data
+// is used directly, it's copied if it's not 8 byte aligned or the machine
+// is not little endian.
+func loadWords(data []byte) []uint64 {
+	if len(data) == 0 {
+		return []uint64{}
+	}
+
+	if hostLittleEndian && uintptr(unsafe.Pointer(&data[0]))%8 == 0 {
+		return bytesUint64(data)
+	}
+
+	words := make([]uint64, len(data)/8)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+
+	return words
+}
+
+func uint64Bytes(s []uint64) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+
+	var b []byte
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	h.Data = uintptr(unsafe.Pointer(&s[0]))
+	h.Len = 8 * len(s)
+	h.Cap = 8 * len(s)
+
+	return b
+}
+
+func bytesUint64(b []byte) []uint64 {
+	var s []uint64
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	h.Data = uintptr(unsafe.Pointer(&b[0]))
+	h.Len = len(b) / 8
+	h.Cap = len(b) / 8
+
+	return s
+}
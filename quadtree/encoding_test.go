@@ -0,0 +1,198 @@
+package quadtree
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestQuadtreeWriteTo(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	qt := New(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	items := make([]Item, 1000)
+	for i := range items {
+		items[i] = NewItem(orb.Point{r.Float64(), r.Float64()}, uint64(i))
+		if err := qt.Add(items[i]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// empty nodes are kept
+	for i := 0; i < len(items); i += 7 {
+		qt.Remove(items[i], nil)
+	}
+
+	buf := &bytes.Buffer{}
+	written, err := qt.WriteTo(buf)
+	if err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	if written != int64(buf.Len()) {
+		t.Errorf("incorrect length: %v != %v", written, buf.Len())
+	}
+
+	read := &Quadtree{}
+	n, err := read.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	if n != written {
+		t.Errorf("incorrect read length: %v != %v", n, written)
+	}
+
+	if !reflect.DeepEqual(read, qt) {
+		t.Errorf("trees should be the same")
+	}
+
+	for i := 0; i < 100; i++ {
+		p := orb.Point{r.Float64(), r.Float64()}
+		if a, b := read.Find(p), qt.Find(p); a != b {
+			t.Errorf("incorrect find: %v != %v", a, b)
+		}
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		qt := New(orb.Bound{Max: orb.Point{1, 1}})
+
+		buf := &bytes.Buffer{}
+		if _, err := qt.WriteTo(buf); err != nil {
+			t.Fatalf("write error: %v", err)
+		}
+
+		read := &Quadtree{}
+		if _, err := read.ReadFrom(buf); err != nil {
+			t.Fatalf("read error: %v", err)
+		}
+
+		if !reflect.DeepEqual(read, qt) {
+			t.Errorf("trees should be the same")
+		}
+	})
+
+	t.Run("missing id", func(t *testing.T) {
+		qt := New(orb.Bound{Max: orb.Point{1, 1}})
+		qt.Add(orb.Point{0.5, 0.5})
+
+		_, err := qt.WriteTo(&bytes.Buffer{})
+		if err != ErrMissingID {
+			t.Errorf("incorrect error: %v", err)
+		}
+	})
+}
+
+func TestExtentsWriteTo(t *testing.T) {
+	r := rand.New(rand.NewSource(43))
+
+	e := NewExtents(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	for i, b := range randomBounds(r, 1000, 0.1) {
+		if err := e.Add(NewBoundItem(b, uint64(i))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	written, err := e.WriteTo(buf)
+	if err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	read := &Extents{}
+	n, err := read.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	if n != written {
+		t.Errorf("incorrect read length: %v != %v", n, written)
+	}
+
+	if !reflect.DeepEqual(read, e) {
+		t.Errorf("indexes should be the same")
+	}
+
+	t.Run("missing id", func(t *testing.T) {
+		e := NewExtents(orb.Bound{Max: orb.Point{1, 1}})
+		e.Add(orb.Point{0.5, 0.5}.Bound())
+
+		_, err := e.WriteTo(&bytes.Buffer{})
+		if err != ErrMissingID {
+			t.Errorf("incorrect error: %v", err)
+		}
+	})
+}
+
+func TestReadFrom_errors(t *testing.T) {
+	qt := New(orb.Bound{Max: orb.Point{1, 1}})
+	qt.Add(NewItem(orb.Point{0.5, 0.5}, 1))
+	qt.Add(NewItem(orb.Point{0.2, 0.5}, 2))
+
+	buf := &bytes.Buffer{}
+	if _, err := qt.WriteTo(buf); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	data := buf.Bytes()
+
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "magic", data: append([]byte("ORBE"), data[4:]...)},
+		{name: "truncated", data: data[:len(data)-1]},
+		{name: "extra", data: append(append([]byte{}, data...), 0)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := (&Quadtree{}).ReadFrom(bytes.NewReader(tc.data))
+			if err != ErrInvalidData {
+				t.Errorf("incorrect error: %v", err)
+			}
+		})
+	}
+}
+
+func TestQuadtreeReadFrom_deep(t *testing.T) {
+	// the same point many times makes a deep tree
+	qt := New(orb.Bound{Max: orb.Point{1, 1}})
+	for i := 0; i < 5000; i++ {
+		qt.Add(NewItem(orb.Point{0.5, 0.5}, uint64(i)))
+	}
+
+	buf := &bytes.Buffer{}
+	if _, err := qt.WriteTo(buf); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	read := &Quadtree{}
+	if _, err := read.ReadFrom(buf); err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+
+	if !reflect.DeepEqual(read, qt) {
+		t.Errorf("trees should be the same")
+	}
+
+	// a long chain of empty nodes
+	n := 1 << 18
+	nodes := make([]uint64, quadtreeNodeWords*n)
+	for i := 0; i < n-1; i++ {
+		nodes[quadtreeNodeWords*i+3] = uint64(n)<<flatFlagBits | 1
+	}
+	nodes[quadtreeNodeWords*(n-1)+3] = uint64(n) << flatFlagBits
+
+	buf.Reset()
+	if _, err := writeData(buf, quadtreeMagic, qt.Bound(), 0, uint64(n), nodes); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	if _, err := read.ReadFrom(buf); err != nil {
+		t.Errorf("read error: %v", err)
+	}
+}
@@ -1,10 +1,12 @@
 package quadtree_test
 
 import (
+	"bytes"
 	"fmt"
 	"math/rand"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
 	"github.com/paulmach/orb/quadtree"
 )
 
@@ -81,3 +83,155 @@ func ExampleQuadtree_InBound() {
 	// Output:
 	// in bound: 10
 }
+
+func ExampleExtents_Intersecting() {
+	e := quadtree.NewExtents(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 10}})
+
+	// a large parcel and a road segment
+	parcel := orb.Polygon{{{1, 1}, {9, 1}, {9, 9}, {1, 9}, {1, 1}}}
+	road := orb.LineString{{0, 9.5}, {10, 9.5}}
+
+	if err := e.Add(parcel); err != nil {
+		panic(err)
+	}
+
+	if err := e.Add(road); err != nil {
+		panic(err)
+	}
+
+	// the center of the parcel is not in the query but it still intersects
+	found := e.Intersecting(nil, orb.Bound{Min: orb.Point{8, 8}, Max: orb.Point{10, 10}})
+	fmt.Printf("intersecting: %v\n", len(found))
+
+	nearest := e.Find(orb.Point{5, 9.8})
+	fmt.Printf("nearest: %v\n", nearest.(orb.Geometry).GeoJSONType())
+
+	// Output:
+	// intersecting: 2
+	// nearest: LineString
+}
+
+func ExampleQuadtree_WriteTo() {
+	qt := quadtree.New(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+
+	// the values must have an id to be written
+	qt.Add(quadtree.NewItem(orb.Point{0.2, 0.3}, 100))
+	qt.Add(quadtree.NewItem(orb.Point{0.7, 0.8}, 200))
+
+	buf := &bytes.Buffer{}
+	if _, err := qt.WriteTo(buf); err != nil {
+		panic(err)
+	}
+
+	// e.g. on the next service start
+	loaded := &quadtree.Quadtree{}
+	if _, err := loaded.ReadFrom(buf); err != nil {
+		panic(err)
+	}
+
+	nearest := loaded.Find(orb.Point{0.6, 0.6}).(quadtree.Item)
+	fmt.Printf("nearest: %v\n", nearest.ID())
+
+	// Output:
+	// nearest: 200
+}
+
+func ExampleLoad() {
+	qt := quadtree.New(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	qt.Add(quadtree.NewItem(orb.Point{0.2, 0.3}, 100))
+	qt.Add(quadtree.NewItem(orb.Point{0.7, 0.8}, 200))
+
+	buf := &bytes.Buffer{}
+	if _, err := qt.WriteTo(buf); err != nil {
+		panic(err)
+	}
+
+	// the data is used as is, e.g. a memory-mapped file
+	loaded, err := quadtree.Load(buf.Bytes())
+	if err != nil {
+		panic(err)
+	}
+
+	nearest := loaded.Find(orb.Point{0.6, 0.6}).(quadtree.Item)
+	fmt.Printf("nearest: %v\n", nearest.ID())
+
+	// Output:
+	// nearest: 200
+}
+
+func ExampleConcurrent_Replace() {
+	type vehicle struct {
+		orb.Pointer
+		ID int
+	}
+
+	c := quadtree.NewConcurrent(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+
+	old := vehicle{Pointer: orb.Point{0.1, 0.1}, ID: 1}
+	if err := c.Add(old); err != nil {
+		panic(err)
+	}
+
+	// readers in other goroutines always see the vehicle in one place
+	moved := vehicle{Pointer: orb.Point{0.8, 0.8}, ID: 1}
+	_, err := c.Replace(old, func(p orb.Pointer) bool {
+		return p.(vehicle).ID == old.ID
+	}, moved)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(c.Find(orb.Point{1, 1}).Point())
+
+	// Output:
+	// [0.8 0.8]
+}
+
+func ExampleQuadtree_Move() {
+	type vehicle struct {
+		orb.Pointer
+		ID int
+	}
+
+	qt := quadtree.New(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+
+	location := &orb.Point{0.1, 0.1}
+	v := vehicle{Pointer: location, ID: 1}
+	if err := qt.Add(v); err != nil {
+		panic(err)
+	}
+
+	// update the tree and then the location
+	newLocation := orb.Point{0.8, 0.8}
+	_, err := qt.Move(v, newLocation, func(p orb.Pointer) bool {
+		return p.(vehicle).ID == v.ID
+	})
+	if err != nil {
+		panic(err)
+	}
+	*location = newLocation
+
+	fmt.Println(qt.Len(), qt.Find(orb.Point{1, 1}).Point())
+
+	// Output:
+	// 1 [0.8 0.8]
+}
+
+func ExampleQuadtree_InRadius() {
+	qt := quadtree.New(orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, 90}})
+
+	center := orb.Point{18, 59}
+	qt.Add(orb.Point{18, 62}) // 3 degrees north, 334km
+	qt.Add(orb.Point{23, 59}) // 5 degrees east, 287km
+
+	fmt.Println(qt.InRadius(nil, center, 300000, geo.Distance))
+
+	// planar distances are wrong away from the equator
+	fmt.Println(qt.KNearest(nil, center, 1))
+	fmt.Println(qt.KNearestDistance(nil, center, 1, geo.Distance))
+
+	// Output:
+	// [[23 59]]
+	// [[18 62]]
+	// [[23 59]]
+}
@@ -0,0 +1,447 @@
+package quadtree
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+var (
+	// ErrBoundOutsideOfBounds is returned when trying to add an item
+	// to an extents index and its bound is not within the bounds used
+	// to create the index.
+	ErrBoundOutsideOfBounds = errors.New("quadtree: bound outside of bounds")
+)
+
+const (
+	// extentsNodeCapacity is the number of items a leaf holds before splitting.
+	extentsNodeCapacity = 8
+
+	// extentsMaxDepth limits splitting when many items have the same bound.
+	extentsMaxDepth = 24
+)
+
+// Extents implements a two-dimensional recursive spatial subdivision of
+// orb.Bounders, e.g. polygons or line segments, using rectangular partitions.
+// Each item is stored in the smallest partition that completely contains it,
+// so large items are found even if their center is far from the query.
+type Extents struct {
+	bound orb.Bound
+	root  *extentsNode
+	count int
+}
+
+// A BoundFilterFunc is a function that filters the items to search for.
+type BoundFilterFunc func(b orb.Bounder) bool
+
+// extentsNode is a partition of the tree. Items that do not fit
+// within one of the children are kept in the node.
+type extentsNode struct {
+	Items    []orb.Bounder
+	Children *[4]extentsNode
+}
+
+// NewExtents creates a new extents index for the given bound.
+// Added items must be within this bound.
+func NewExtents(bound orb.Bound) *Extents {
+	return &Extents{bound: bound, root: &extentsNode{}}
+}
+
+// Bound returns the bounds used for the index.
+func (e *Extents) Bound() orb.Bound {
+	return e.bound
+}
+
+// Len returns the number of items in the index.
+func (e *Extents) Len() int {
+	return e.count
+}
+
+// Add puts an item into the index, its bound must be within the index bounds.
+// This function is not thread-safe, ie. multiple goroutines cannot insert into
+// a single index.
+func (e *Extents) Add(b orb.Bounder) error {
+	if b == nil {
+		return nil
+	}
+
+	bound := b.Bound()
+	if !e.bound.Contains(bound.Min) || !e.bound.Contains(bound.Max) {
+		return ErrBoundOutsideOfBounds
+	}
+
+	n := e.root
+	left, right := e.bound.Min[0], e.bound.Max[0]
+	bottom, top := e.bound.Min[1], e.bound.Max[1]
+
+	for depth := 0; ; depth++ {
+		if n.Children == nil {
+			n.Items = append(n.Items, b)
+			if len(n.Items) > extentsNodeCapacity && depth < extentsMaxDepth {
+				n.split(left, right, bottom, top)
+			}
+			break
+		}
+
+		i := extentsChildIndex(bound, left, right, bottom, top)
+		if i == -1 {
+			n.Items = append(n.Items, b)
+			break
+		}
+
+		n = &n.Children[i]
+		left, right, bottom, top = childBound(i, left, right, bottom, top)
+	}
+
+	e.count++
+	return nil
+}
+
+// split creates the children of a leaf and moves down the items that fit.
+func (n *extentsNode) split(left, right, bottom, top float64) {
+	n.Children = &[4]extentsNode{}
+
+	items := n.Items[:0]
+	for _, item := range n.Items {
+		i := extentsChildIndex(item.Bound(), left, right, bottom, top)
+		if i == -1 {
+			items = append(items, item)
+			continue
+		}
+
+		n.Children[i].Items = append(n.Children[i].Items, item)
+	}
+
+	for i := len(items); i < len(n.Items); i++ {
+		n.Items[i] = nil
+	}
+	n.Items = items
+}
+
+// Remove will remove the item from the index. By default it'll match
+// using the bounds, but a BoundFilterFunc can be provided for a more specific
+// test if there are items with the same bound in the index. For example:
+//
+//	func(b orb.Bounder) {
+//		return b.(*MyType).ID == lookingFor.ID
+//	}
+func (e *Extents) Remove(b orb.Bounder, eq BoundFilterFunc) bool {
+	bound := b.Bound()
+	if eq == nil {
+		eq = func(item orb.Bounder) bool {
+			return bound.Equal(item.Bound())
+		}
+	}
+
+	removed := e.remove(e.root, bound, eq,
+		e.bound.Min[0], e.bound.Max[0],
+		e.bound.Min[1], e.bound.Max[1],
+	)
+
+	if removed {
+		e.count--
+	}
+
+	return removed
+}
+
+// remove follows the path the item was added along. The children of
+// a node are dropped once they are all empty leaves.
+func (e *Extents) remove(n *extentsNode, bound orb.Bound, eq BoundFilterFunc, left, right, bottom, top float64) bool {
+	for i, item := range n.Items {
+		if eq(item) {
+			last := len(n.Items) - 1
+			n.Items[i] = n.Items[last]
+			n.Items[last] = nil
+			n.Items = n.Items[:last]
+			return true
+		}
+	}
+
+	if n.Children == nil {
+		return false
+	}
+
+	i := extentsChildIndex(bound, left, right, bottom, top)
+	if i == -1 {
+		return false
+	}
+
+	l, r, b, t := childBound(i, left, right, bottom, top)
+	if !e.remove(&n.Children[i], bound, eq, l, r, b, t) {
+		return false
+	}
+
+	for j := range n.Children {
+		if n.Children[j].Children != nil || len(n.Children[j].Items) > 0 {
+			return true
+		}
+	}
+
+	n.Children = nil
+	return true
+}
+
+// Intersecting returns a slice with all the items in the index whose bound
+// intersects the given bound. An optional buffer parameter is provided to allow
+// for the reuse of result slice memory. This function is thread safe.
+// Multiple goroutines can read from a pre-created index.
+func (e *Extents) Intersecting(buf []orb.Bounder, b orb.Bound) []orb.Bounder {
+	return e.IntersectingMatching(buf, b, nil)
+}
+
+// IntersectingMatching returns a slice with all the items in the index whose
+// bound intersects the given bound and matching the given filter function.
+// An optional buffer parameter is provided to allow for the reuse of result
+// slice memory. This function is thread safe. Multiple goroutines can read
+// from a pre-created index.
+func (e *Extents) IntersectingMatching(buf []orb.Bounder, b orb.Bound, f BoundFilterFunc) []orb.Bounder {
+	var result []orb.Bounder
+	if buf != nil {
+		result = buf[:0]
+	}
+
+	return e.intersecting(result, e.root, b, f,
+		e.bound.Min[0], e.bound.Max[0],
+		e.bound.Min[1], e.bound.Max[1],
+	)
+}
+
+func (e *Extents) intersecting(result []orb.Bounder, n *extentsNode, b orb.Bound, f BoundFilterFunc, left, right, bottom, top float64) []orb.Bounder {
+	if left > b.Max[0] || right < b.Min[0] ||
+		bottom > b.Max[1] || top < b.Min[1] {
+		return result
+	}
+
+	for _, item := range n.Items {
+		if f != nil && !f(item) {
+			continue
+		}
+
+		if item.Bound().Intersects(b) {
+			result = append(result, item)
+		}
+	}
+
+	if n.Children == nil {
+		return result
+	}
+
+	for i := range n.Children {
+		l, r, bt, t := childBound(i, left, right, bottom, top)
+		result = e.intersecting(result, &n.Children[i], b, f, l, r, bt, t)
+	}
+
+	return result
+}
+
+// Find returns the item in the index whose bound is closest to the point.
+// The distance is zero if the point is within the bound. This function is
+// thread safe. Multiple goroutines can read from a pre-created index.
+func (e *Extents) Find(p orb.Point) orb.Bounder {
+	result := e.KNearestMatching(nil, p, 1, nil)
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result[0]
+}
+
+// KNearest returns the k items whose bounds are closest to the point.
+// This function is thread safe. Multiple goroutines can read from a
+// pre-created index. An optional buffer parameter is provided to allow for
+// the reuse of result slice memory. The items are returned in a sorted
+// order, nearest first. This function allows defining a maximum distance
+// in order to reduce search iterations.
+func (e *Extents) KNearest(buf []orb.Bounder, p orb.Point, k int, maxDistance ...float64) []orb.Bounder {
+	return e.KNearestMatching(buf, p, k, nil, maxDistance...)
+}
+
+// KNearestMatching returns the k items whose bounds are closest to the point
+// and matching the given filter function. This function is thread safe.
+// Multiple goroutines can read from a pre-created index. An optional buffer
+// parameter is provided to allow for the reuse of result slice memory.
+// The items are returned in a sorted order, nearest first.
+// This function allows defining a maximum distance in order to reduce search iterations.
+func (e *Extents) KNearestMatching(buf []orb.Bounder, p orb.Point, k int, f BoundFilterFunc, maxDistance ...float64) []orb.Bounder {
+	if k <= 0 {
+		return nil
+	}
+
+	v := newExtentsNearest(p, k, f, maxDistance)
+	v.visit(e.root,
+		e.bound.Min[0], e.bound.Max[0],
+		e.bound.Min[1], e.bound.Max[1],
+	)
+
+	return v.result(buf)
+}
+
+// extentsNearest keeps the k nearest items sorted by distance.
+type extentsNearest struct {
+	point          orb.Point
+	filter         BoundFilterFunc
+	k              int
+	items          []extentsItem
+	maxDistSquared float64
+}
+
+type extentsItem struct {
+	value    orb.Bounder
+	distance float64
+}
+
+func newExtentsNearest(p orb.Point, k int, f BoundFilterFunc, maxDistance []float64) *extentsNearest {
+	v := &extentsNearest{
+		point:          p,
+		filter:         f,
+		k:              k,
+		maxDistSquared: math.MaxFloat64,
+	}
+
+	if len(maxDistance) > 0 {
+		v.maxDistSquared = maxDistance[0] * maxDistance[0]
+	}
+
+	return v
+}
+
+func (v *extentsNearest) visit(n *extentsNode, left, right, bottom, top float64) {
+	if boundDistanceSquared(v.point, left, right, bottom, top) > v.maxDistSquared {
+		return
+	}
+
+	for _, item := range n.Items {
+		v.visitItem(item)
+	}
+
+	if n.Children == nil {
+		return
+	}
+
+	for _, i := range v.childOrder(left, right, bottom, top) {
+		l, r, b, t := childBound(i, left, right, bottom, top)
+		v.visit(&n.Children[i], l, r, b, t)
+	}
+}
+
+func (v *extentsNearest) visitItem(item orb.Bounder) {
+	if v.filter != nil && !v.filter(item) {
+		return
+	}
+
+	b := item.Bound()
+	d := boundDistanceSquared(v.point, b.Min[0], b.Max[0], b.Min[1], b.Max[1])
+	if d > v.maxDistSquared {
+		return
+	}
+
+	v.add(item, d)
+}
+
+// childOrder returns the children sorted by distance, so the nearest are
+// visited first to restrict the range quickly.
+func (v *extentsNearest) childOrder(left, right, bottom, top float64) [4]int {
+	var distances [4]float64
+	for i := range distances {
+		l, r, b, t := childBound(i, left, right, bottom, top)
+		distances[i] = boundDistanceSquared(v.point, l, r, b, t)
+	}
+
+	indexes := [4]int{0, 1, 2, 3}
+	for i := 1; i < 4; i++ {
+		for j := i; j > 0 && distances[indexes[j]] < distances[indexes[j-1]]; j-- {
+			indexes[j], indexes[j-1] = indexes[j-1], indexes[j]
+		}
+	}
+
+	return indexes
+}
+
+// result returns the items, nearest first, reusing the buffer if possible.
+func (v *extentsNearest) result(buf []orb.Bounder) []orb.Bounder {
+	if cap(buf) < len(v.items) {
+		buf = make([]orb.Bounder, len(v.items))
+	} else {
+		buf = buf[:len(v.items)]
+	}
+
+	for i, item := range v.items {
+		buf[i] = item.value
+	}
+
+	return buf
+}
+
+func (v *extentsNearest) add(value orb.Bounder, d float64) {
+	i := sort.Search(len(v.items), func(i int) bool {
+		return v.items[i].distance > d
+	})
+
+	if i >= v.k {
+		return
+	}
+
+	if len(v.items) < v.k {
+		v.items = append(v.items, extentsItem{})
+	}
+
+	copy(v.items[i+1:], v.items[i:])
+	v.items[i] = extentsItem{value: value, distance: d}
+
+	if len(v.items) == v.k {
+		// we have k items, so we start to restrict the searching range
+		v.maxDistSquared = v.items[v.k-1].distance
+	}
+}
+
+// extentsChildIndex returns the child that completely contains the bound,
+// or -1 if it crosses the center lines. The children are numbered the same
+// as the point quadtree.
+func extentsChildIndex(b orb.Bound, left, right, bottom, top float64) int {
+	cx := (left + right) / 2.0
+	cy := (bottom + top) / 2.0
+
+	i := 0
+	if b.Max[1] <= cy {
+		i = 2
+	} else if b.Min[1] <= cy {
+		return -1
+	}
+
+	if b.Min[0] >= cx {
+		i++
+	} else if b.Max[0] >= cx {
+		return -1
+	}
+
+	return i
+}
+
+// childBound returns the bound of the child partition.
+func childBound(i int, left, right, bottom, top float64) (float64, float64, float64, float64) {
+	cx := (left + right) / 2.0
+	cy := (bottom + top) / 2.0
+
+	switch i {
+	case 0:
+		return left, cx, cy, top
+	case 1:
+		return cx, right, cy, top
+	case 2:
+		return left, cx, bottom, cy
+	default:
+		return cx, right, bottom, cy
+	}
+}
+
+// boundDistanceSquared returns the squared distance from the point to the
+// rectangle, zero if the point is inside.
+func boundDistanceSquared(p orb.Point, left, right, bottom, top float64) float64 {
+	dx := math.Max(0, math.Max(left-p[0], p[0]-right))
+	dy := math.Max(0, math.Max(bottom-p[1], p[1]-top))
+
+	return dx*dx + dy*dy
+}
@@ -0,0 +1,229 @@
+package quadtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+type extentsItemID struct {
+	orb.Bounder
+	ID int
+}
+
+func randomBounds(r *rand.Rand, n int, size float64) []orb.Bound {
+	bounds := make([]orb.Bound, 0, n)
+	for i := 0; i < n; i++ {
+		min := orb.Point{r.Float64() * (1 - size), r.Float64() * (1 - size)}
+		bounds = append(bounds, orb.Bound{
+			Min: min,
+			Max: orb.Point{min[0] + r.Float64()*size, min[1] + r.Float64()*size},
+		})
+	}
+
+	return bounds
+}
+
+func TestExtentsAdd(t *testing.T) {
+	e := NewExtents(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+
+	b := orb.Bound{Min: orb.Point{0.2, 0.2}, Max: orb.Point{0.3, 0.3}}
+	for i := 0; i < 100; i++ {
+		// should be able to insert the same bound over and over.
+		err := e.Add(b)
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %v", b, err)
+		}
+	}
+
+	if l := e.Len(); l != 100 {
+		t.Errorf("incorrect length: %v", l)
+	}
+
+	err := e.Add(orb.Bound{Min: orb.Point{0.5, 0.5}, Max: orb.Point{1.5, 1}})
+	if err != ErrBoundOutsideOfBounds {
+		t.Errorf("incorrect error: %v", err)
+	}
+
+	// geometry types are bounders
+	err = e.Add(orb.LineString{{0.1, 0.1}, {0.9, 0.2}})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestExtentsIntersecting(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	e := NewExtents(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	bounds := randomBounds(r, 1000, 0.1)
+	for _, b := range bounds {
+		if err := e.Add(b); err != nil {
+			t.Fatalf("unexpected error for %v: %v", b, err)
+		}
+	}
+
+	// a large item with its center outside the query
+	large := orb.Polygon{{{0.01, 0.01}, {0.99, 0.01}, {0.99, 0.99}, {0.01, 0.99}, {0.01, 0.01}}}
+	if err := e.Add(large); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bounds = append(bounds, large.Bound())
+
+	for i := 0; i < 1000; i++ {
+		query := orb.Bound{Min: orb.Point{r.Float64(), r.Float64()}}
+		query.Max = query.Min
+		query = query.Pad(0.05)
+
+		result := e.Intersecting(nil, query)
+
+		expected := 0
+		for _, b := range bounds {
+			if b.Intersects(query) {
+				expected++
+			}
+		}
+
+		if len(result) != expected {
+			t.Errorf("index: %d, lengths not equal %v != %v", i, len(result), expected)
+		}
+	}
+
+	t.Run("matching", func(t *testing.T) {
+		result := e.IntersectingMatching(nil, orb.Bound{Max: orb.Point{0.1, 0.1}}, func(b orb.Bounder) bool {
+			_, ok := b.(orb.Polygon)
+			return ok
+		})
+
+		if len(result) != 1 {
+			t.Errorf("should only find the polygon: %v", result)
+		}
+	})
+}
+
+func TestExtentsKNearest(t *testing.T) {
+	r := rand.New(rand.NewSource(43))
+
+	e := NewExtents(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	bounds := randomBounds(r, 1000, 0.05)
+	for _, b := range bounds {
+		if err := e.Add(b); err != nil {
+			t.Fatalf("unexpected error for %v: %v", b, err)
+		}
+	}
+
+	dist := func(p orb.Point, b orb.Bound) float64 {
+		return boundDistanceSquared(p, b.Min[0], b.Max[0], b.Min[1], b.Max[1])
+	}
+
+	var buf []orb.Bounder
+	for i := 0; i < 500; i++ {
+		p := orb.Point{r.Float64(), r.Float64()}
+		buf = e.KNearest(buf, p, 5)
+
+		if len(buf) != 5 {
+			t.Fatalf("incorrect number of results: %v", len(buf))
+		}
+
+		// brute force the 5th distance
+		var ds []float64
+		for _, b := range bounds {
+			ds = append(ds, dist(p, b))
+		}
+		kth := kthSmallest(ds, 5)
+
+		for j, item := range buf {
+			d := dist(p, item.Bound())
+			if d > kth {
+				t.Errorf("index %d: result further than expected: %v > %v", i, d, kth)
+			}
+
+			if j > 0 && d < dist(p, buf[j-1].Bound()) {
+				t.Errorf("index %d: not sorted", i)
+			}
+		}
+
+		f := e.Find(p)
+		if d := dist(p, f.Bound()); d != kthSmallest(ds, 1) {
+			t.Errorf("index %d: incorrect find: %v", i, d)
+		}
+	}
+
+	t.Run("max distance", func(t *testing.T) {
+		result := e.KNearest(nil, orb.Point{2, 2}, 5, 0.5)
+		if len(result) != 0 {
+			t.Errorf("should not find anything: %v", result)
+		}
+	})
+
+	t.Run("inside", func(t *testing.T) {
+		e := NewExtents(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+		e.Add(orb.Bound{Min: orb.Point{0.4, 0.4}, Max: orb.Point{0.41, 0.41}})
+		e.Add(orb.Bound{Min: orb.Point{0.1, 0.1}, Max: orb.Point{0.9, 0.9}})
+
+		f := e.Find(orb.Point{0.2, 0.8})
+		if f.Bound().Min[0] != 0.1 {
+			t.Errorf("should find the containing bound: %v", f)
+		}
+	})
+}
+
+func TestExtentsRemove(t *testing.T) {
+	r := rand.New(rand.NewSource(44))
+
+	e := NewExtents(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	bounds := randomBounds(r, 1000, 0.2)
+	for i, b := range bounds {
+		if err := e.Add(&extentsItemID{Bounder: b, ID: i}); err != nil {
+			t.Fatalf("unexpected error for %v: %v", b, err)
+		}
+	}
+
+	for i := 0; i < len(bounds); i += 2 {
+		id := i
+		removed := e.Remove(&extentsItemID{Bounder: bounds[i]}, func(b orb.Bounder) bool {
+			return b.(*extentsItemID).ID == id
+		})
+
+		if !removed {
+			t.Errorf("should remove %d", i)
+		}
+	}
+
+	if l := e.Len(); l != 500 {
+		t.Errorf("incorrect length: %v", l)
+	}
+
+	if e.Remove(bounds[0], nil) {
+		t.Errorf("should not remove again")
+	}
+
+	all := e.Intersecting(nil, e.Bound())
+	if len(all) != 500 {
+		t.Errorf("incorrect number of items: %v", len(all))
+	}
+
+	for _, item := range all {
+		if item.(*extentsItemID).ID%2 == 0 {
+			t.Errorf("item should be removed: %v", item)
+		}
+	}
+
+	for i := 1; i < len(bounds); i += 2 {
+		if !e.Remove(bounds[i], nil) {
+			t.Errorf("should remove %d", i)
+		}
+	}
+
+	if e.root.Children != nil || len(e.root.Items) != 0 {
+		t.Errorf("tree should be empty: %v", e.root)
+	}
+}
+
+func kthSmallest(vals []float64, k int) float64 {
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+	return sorted[k-1]
+}
@@ -0,0 +1,586 @@
+package quadtree
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+const (
+	// flatValue is the flag of a flat quadtree node with a value,
+	// the lower 4 bits are set for each child.
+	flatValue = 1 << 4
+
+	// flatFlagBits are the bits of the node info used for the flags,
+	// the rest is the index after the node's subtree.
+	flatFlagBits = 5
+)
+
+// Flat is a read-only quadtree that searches the data written by
+// Quadtree.WriteTo directly, e.g. a memory-mapped file, see Load.
+// The values are Items with the point and ID.
+type Flat struct {
+	bound orb.Bound
+	count int
+
+	// nodes in pre-order, 4 words each: the point and ID of the value and
+	// the index after the subtree with the flags. The first child of a node
+	// is the next node, the others start after the previous child's subtree.
+	nodes []uint64
+}
+
+// Load returns the tree written by Quadtree.WriteTo in the data. If possible
+// the tree uses the data directly without copying, e.g. for a memory-mapped
+// file, so the data must not be modified while the tree is in use. The data
+// is copied if it's not 8 byte aligned or the machine is not little endian.
+func Load(data []byte) (*Flat, error) {
+	bound, count, numNodes, data, err := loadHeader(data, quadtreeMagic)
+	if err != nil {
+		return nil, err
+	}
+
+	if numNodes > uint64(len(data))/(8*quadtreeNodeWords) ||
+		len(data) != int(numNodes)*8*quadtreeNodeWords {
+		return nil, ErrInvalidData
+	}
+
+	f := &Flat{
+		bound: bound,
+		count: int(count),
+		nodes: loadWords(data),
+	}
+
+	values := uint64(0)
+	_, ok := validPreorder(int(numNodes), func(i int) (uint64, int) {
+		info := f.nodes[quadtreeNodeWords*i+3]
+		if info&flatValue != 0 {
+			values++
+		}
+
+		children := 0
+		for j := uint(0); j < 4; j++ {
+			if info&(1<<j) != 0 {
+				children++
+			}
+		}
+
+		return info >> flatFlagBits, children
+	})
+
+	if !ok || values != count {
+		return nil, ErrInvalidData
+	}
+
+	return f, nil
+}
+
+// Bound returns the bounds used for the quad tree.
+func (f *Flat) Bound() orb.Bound {
+	return f.bound
+}
+
+// Len returns the number of values in the tree.
+func (f *Flat) Len() int {
+	return f.count
+}
+
+// Walk calls the function for every value in the tree, in the same order
+// as Quadtree.Walk. Walking stops if the function returns false.
+func (f *Flat) Walk(fn func(p orb.Pointer) bool) {
+	for i := 0; i < len(f.nodes)/quadtreeNodeWords; i++ {
+		if f.hasValue(i) && !fn(f.item(i)) {
+			return
+		}
+	}
+}
+
+// Find returns the closest value in the tree.
+// This function is thread safe.
+func (f *Flat) Find(p orb.Point) orb.Pointer {
+	return matching(f, p, nil)
+}
+
+// Matching returns the closest value in the tree for which the given
+// filter function returns true. This function is thread safe.
+func (f *Flat) Matching(p orb.Point, fn FilterFunc) orb.Pointer {
+	return matching(f, p, fn)
+}
+
+// KNearest returns k closest values in the tree, nearest first.
+// See Quadtree.KNearest for more details.
+func (f *Flat) KNearest(buf []orb.Pointer, p orb.Point, k int, maxDistance ...float64) []orb.Pointer {
+	return kNearest(f, buf, p, k, nil, nil, maxDistance...)
+}
+
+// KNearestMatching returns k closest values in the tree for which the given
+// filter function returns true. See Quadtree.KNearestMatching for more details.
+func (f *Flat) KNearestMatching(buf []orb.Pointer, p orb.Point, k int, fn FilterFunc, maxDistance ...float64) []orb.Pointer {
+	return kNearest(f, buf, p, k, nil, fn, maxDistance...)
+}
+
+// KNearestDistance returns k closest values in the tree using the distance
+// function. See Quadtree.KNearestDistance for more details.
+func (f *Flat) KNearestDistance(buf []orb.Pointer, p orb.Point, k int, df orb.DistanceFunc, maxDistance ...float64) []orb.Pointer {
+	return kNearest(f, buf, p, k, df, nil, maxDistance...)
+}
+
+// KNearestDistanceMatching returns k closest values in the tree using the
+// distance function and for which the given filter function returns true.
+// See Quadtree.KNearestDistance for more details.
+func (f *Flat) KNearestDistanceMatching(buf []orb.Pointer, p orb.Point, k int, df orb.DistanceFunc, fn FilterFunc, maxDistance ...float64) []orb.Pointer {
+	return kNearest(f, buf, p, k, df, fn, maxDistance...)
+}
+
+// InRadius returns all the values in the tree within the distance, in meters,
+// of the center. See Quadtree.InRadius for more details.
+func (f *Flat) InRadius(buf []orb.Pointer, center orb.Point, meters float64, df orb.DistanceFunc) []orb.Pointer {
+	return inRadius(f, buf, center, meters, df, nil)
+}
+
+// InRadiusMatching returns all the values in the tree within the distance,
+// in meters, of the center and matching the given filter function.
+// See Quadtree.InRadius for more details.
+func (f *Flat) InRadiusMatching(buf []orb.Pointer, center orb.Point, meters float64, df orb.DistanceFunc, fn FilterFunc) []orb.Pointer {
+	return inRadius(f, buf, center, meters, df, fn)
+}
+
+// InBound returns all the values in the tree that are within the given bound.
+// See Quadtree.InBound for more details.
+func (f *Flat) InBound(buf []orb.Pointer, b orb.Bound) []orb.Pointer {
+	return inBound(f, buf, b, nil)
+}
+
+// InBoundMatching returns all the values in the tree that are within the
+// given bound and matching the given filter function.
+// See Quadtree.InBoundMatching for more details.
+func (f *Flat) InBoundMatching(buf []orb.Pointer, b orb.Bound, fn FilterFunc) []orb.Pointer {
+	return inBound(f, buf, b, fn)
+}
+
+func (f *Flat) info(i int) uint64 {
+	return f.nodes[quadtreeNodeWords*i+3]
+}
+
+func (f *Flat) hasValue(i int) bool {
+	return f.info(i)&flatValue != 0
+}
+
+func (f *Flat) item(i int) Item {
+	n := f.nodes[quadtreeNodeWords*i:]
+	return Item{
+		point: orb.Point{math.Float64frombits(n[0]), math.Float64frombits(n[1])},
+		id:    n[2],
+	}
+}
+
+// children returns the index of each child of the node, 0 if there is none.
+func (f *Flat) children(i int) [4]int {
+	var result [4]int
+
+	info := f.info(i)
+	next := i + 1
+	for j := range result {
+		if info&(1<<uint(j)) != 0 {
+			result[j] = next
+			next = int(f.info(next) >> flatFlagBits)
+		}
+	}
+
+	return result
+}
+
+func (f *Flat) visit(v visitor) bool {
+	if len(f.nodes) == 0 {
+		return false
+	}
+
+	f.visitNode(v, 0,
+		f.bound.Min[0], f.bound.Max[0],
+		f.bound.Min[1], f.bound.Max[1],
+	)
+
+	return true
+}
+
+// visitNode is the same as visit.Visit for the flat nodes.
+func (f *Flat) visitNode(v visitor, i int, left, right, bottom, top float64) {
+	b := v.Bound()
+	if left > b.Max[0] || right < b.Min[0] ||
+		bottom > b.Max[1] || top < b.Min[1] {
+		return
+	}
+
+	if f.hasValue(i) {
+		v.Visit(&node{Value: f.item(i)})
+	}
+
+	if f.info(i)&0xf == 0 {
+		return
+	}
+
+	children := f.children(i)
+
+	cx := (left + right) / 2.0
+	cy := (bottom + top) / 2.0
+
+	k := childIndex(cx, cy, v.Point())
+	for j := k; j < k+4; j++ {
+		if c := children[j%4]; c != 0 {
+			l, r, bt, t := childBound(j%4, left, right, bottom, top)
+			f.visitNode(v, c, l, r, bt, t)
+		}
+	}
+}
+
+// tree returns the flat nodes as the nodes of a Quadtree. A stack is used
+// instead of recursion since many values at the same point make a deep tree.
+func (f *Flat) tree() *node {
+	numNodes := len(f.nodes) / quadtreeNodeWords
+	if numNodes == 0 {
+		return nil
+	}
+
+	type frame struct {
+		i, end int
+	}
+
+	nodes := make([]node, numNodes)
+	stack := []frame{{i: 0, end: numNodes}}
+
+	for i := range nodes {
+		if f.hasValue(i) {
+			nodes[i].Value = f.item(i)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		for stack[len(stack)-1].end <= i {
+			stack = stack[:len(stack)-1]
+		}
+
+		// the children are in order, the node is the first one not set yet
+		parent := stack[len(stack)-1].i
+		info := f.info(parent)
+		for j, c := range nodes[parent].Children {
+			if info&(1<<uint(j)) != 0 && c == nil {
+				nodes[parent].Children[j] = &nodes[i]
+				break
+			}
+		}
+
+		stack = append(stack, frame{i: i, end: int(f.info(i) >> flatFlagBits)})
+	}
+
+	return &nodes[0]
+}
+
+// FlatExtents is a read-only Extents index that searches the data written
+// by Extents.WriteTo directly, e.g. a memory-mapped file, see LoadExtents.
+// The items are BoundItems with the bound and ID.
+type FlatExtents struct {
+	bound orb.Bound
+	count int
+
+	// nodes in pre-order, 2 words each: the end of the node's items and
+	// the index after the subtree with a bit if there are children.
+	// The items of a node start at the end of the previous node's items.
+	nodes []uint64
+
+	// items are 5 words each, the bound and the ID.
+	items []uint64
+}
+
+// LoadExtents returns the index written by Extents.WriteTo in the data.
+// If possible the index uses the data directly without copying, e.g. for
+// a memory-mapped file, so the data must not be modified while the index
+// is in use. The data is copied if it's not 8 byte aligned or the machine
+// is not little endian.
+func LoadExtents(data []byte) (*FlatExtents, error) {
+	bound, count, numNodes, data, err := loadHeader(data, extentsMagic)
+	if err != nil {
+		return nil, err
+	}
+
+	if numNodes == 0 ||
+		numNodes > uint64(len(data))/(8*extentsNodeWords) ||
+		count > uint64(len(data))/(8*extentsItemWords) ||
+		len(data) != 8*(int(numNodes)*extentsNodeWords+int(count)*extentsItemWords) {
+		return nil, ErrInvalidData
+	}
+
+	words := loadWords(data)
+	f := &FlatExtents{
+		bound: bound,
+		count: int(count),
+		nodes: words[:extentsNodeWords*numNodes],
+		items: words[extentsNodeWords*numNodes:],
+	}
+
+	itemsEnd, itemsOK := uint64(0), true
+	depth, ok := validPreorder(int(numNodes), func(i int) (uint64, int) {
+		end := f.nodes[extentsNodeWords*i]
+		if end < itemsEnd || end > count {
+			itemsOK = false
+		}
+		itemsEnd = end
+
+		info := f.nodes[extentsNodeWords*i+1]
+		return info >> 1, 4 * int(info&1)
+	})
+
+	// nodes are never deeper than extentsMaxDepth, so searching the
+	// index will not recurse too far.
+	if !ok || !itemsOK || itemsEnd != count || depth > extentsMaxDepth+1 {
+		return nil, ErrInvalidData
+	}
+
+	return f, nil
+}
+
+// Bound returns the bounds used for the index.
+func (f *FlatExtents) Bound() orb.Bound {
+	return f.bound
+}
+
+// Len returns the number of items in the index.
+func (f *FlatExtents) Len() int {
+	return f.count
+}
+
+// Intersecting returns a slice with all the items in the index whose bound
+// intersects the given bound. See Extents.Intersecting for more details.
+func (f *FlatExtents) Intersecting(buf []orb.Bounder, b orb.Bound) []orb.Bounder {
+	return f.IntersectingMatching(buf, b, nil)
+}
+
+// IntersectingMatching returns a slice with all the items in the index whose
+// bound intersects the given bound and matching the given filter function.
+// See Extents.IntersectingMatching for more details.
+func (f *FlatExtents) IntersectingMatching(buf []orb.Bounder, b orb.Bound, fn BoundFilterFunc) []orb.Bounder {
+	var result []orb.Bounder
+	if buf != nil {
+		result = buf[:0]
+	}
+
+	return f.intersecting(result, 0, b, fn,
+		f.bound.Min[0], f.bound.Max[0],
+		f.bound.Min[1], f.bound.Max[1],
+	)
+}
+
+func (f *FlatExtents) intersecting(result []orb.Bounder, i int, b orb.Bound, fn BoundFilterFunc, left, right, bottom, top float64) []orb.Bounder {
+	if left > b.Max[0] || right < b.Min[0] ||
+		bottom > b.Max[1] || top < b.Min[1] {
+		return result
+	}
+
+	start, end := f.itemRange(i)
+	for j := start; j < end; j++ {
+		item := f.item(j)
+		if fn != nil && !fn(item) {
+			continue
+		}
+
+		if item.bound.Intersects(b) {
+			result = append(result, item)
+		}
+	}
+
+	if !f.hasChildren(i) {
+		return result
+	}
+
+	for j, c := range f.children(i) {
+		l, r, bt, t := childBound(j, left, right, bottom, top)
+		result = f.intersecting(result, c, b, fn, l, r, bt, t)
+	}
+
+	return result
+}
+
+// Find returns the item in the index whose bound is closest to the point.
+// See Extents.Find for more details.
+func (f *FlatExtents) Find(p orb.Point) orb.Bounder {
+	result := f.KNearestMatching(nil, p, 1, nil)
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result[0]
+}
+
+// KNearest returns the k items whose bounds are closest to the point.
+// See Extents.KNearest for more details.
+func (f *FlatExtents) KNearest(buf []orb.Bounder, p orb.Point, k int, maxDistance ...float64) []orb.Bounder {
+	return f.KNearestMatching(buf, p, k, nil, maxDistance...)
+}
+
+// KNearestMatching returns the k items whose bounds are closest to the point
+// and matching the given filter function. See Extents.KNearestMatching for
+// more details.
+func (f *FlatExtents) KNearestMatching(buf []orb.Bounder, p orb.Point, k int, fn BoundFilterFunc, maxDistance ...float64) []orb.Bounder {
+	if k <= 0 {
+		return nil
+	}
+
+	v := newExtentsNearest(p, k, fn, maxDistance)
+	f.nearest(v, 0,
+		f.bound.Min[0], f.bound.Max[0],
+		f.bound.Min[1], f.bound.Max[1],
+	)
+
+	return v.result(buf)
+}
+
+// nearest is the same as extentsNearest.visit for the flat nodes.
+func (f *FlatExtents) nearest(v *extentsNearest, i int, left, right, bottom, top float64) {
+	if boundDistanceSquared(v.point, left, right, bottom, top) > v.maxDistSquared {
+		return
+	}
+
+	start, end := f.itemRange(i)
+	for j := start; j < end; j++ {
+		v.visitItem(f.item(j))
+	}
+
+	if !f.hasChildren(i) {
+		return
+	}
+
+	children := f.children(i)
+	for _, j := range v.childOrder(left, right, bottom, top) {
+		l, r, b, t := childBound(j, left, right, bottom, top)
+		f.nearest(v, children[j], l, r, b, t)
+	}
+}
+
+func (f *FlatExtents) info(i int) uint64 {
+	return f.nodes[extentsNodeWords*i+1]
+}
+
+func (f *FlatExtents) hasChildren(i int) bool {
+	return f.info(i)&1 != 0
+}
+
+// itemRange returns the start and end of the node's items.
+func (f *FlatExtents) itemRange(i int) (int, int) {
+	start := 0
+	if i > 0 {
+		start = int(f.nodes[extentsNodeWords*(i-1)])
+	}
+
+	return start, int(f.nodes[extentsNodeWords*i])
+}
+
+func (f *FlatExtents) item(j int) BoundItem {
+	w := f.items[extentsItemWords*j:]
+	return BoundItem{
+		bound: orb.Bound{
+			Min: orb.Point{math.Float64frombits(w[0]), math.Float64frombits(w[1])},
+			Max: orb.Point{math.Float64frombits(w[2]), math.Float64frombits(w[3])},
+		},
+		id: w[4],
+	}
+}
+
+// children returns the index of each child of a node that has children.
+func (f *FlatExtents) children(i int) [4]int {
+	var result [4]int
+
+	next := i + 1
+	for j := range result {
+		result[j] = next
+		next = int(f.info(next) >> 1)
+	}
+
+	return result
+}
+
+// tree returns the flat nodes as the nodes of an Extents index.
+func (f *FlatExtents) tree() *extentsNode {
+	root := &extentsNode{}
+	f.treeNode(root, 0)
+
+	return root
+}
+
+func (f *FlatExtents) treeNode(n *extentsNode, i int) {
+	start, end := f.itemRange(i)
+	if start < end {
+		n.Items = make([]orb.Bounder, 0, end-start)
+	}
+
+	for j := start; j < end; j++ {
+		n.Items = append(n.Items, f.item(j))
+	}
+
+	if !f.hasChildren(i) {
+		return
+	}
+
+	n.Children = &[4]extentsNode{}
+	for j, c := range f.children(i) {
+		f.treeNode(&n.Children[j], c)
+	}
+}
+
+// validPreorder checks that the nodes, in pre-order, are one tree. The node
+// function returns the index after the node's subtree and its number of
+// children. The children of a node must exactly cover its subtree, so
+// searching always ends. It returns the depth of the tree.
+func validPreorder(numNodes int, node func(i int) (uint64, int)) (int, bool) {
+	type frame struct {
+		end  uint64
+		left int
+	}
+
+	var stack []frame
+	depth := 0
+
+	for i := 0; i < numNodes; i++ {
+		end, children := node(i)
+
+		// close the subtrees that end here, all their children must be found
+		for len(stack) > 0 && stack[len(stack)-1].end == uint64(i) {
+			if stack[len(stack)-1].left != 0 {
+				return 0, false
+			}
+			stack = stack[:len(stack)-1]
+		}
+
+		if i == 0 {
+			if end != uint64(numNodes) {
+				return 0, false
+			}
+		} else {
+			if len(stack) == 0 {
+				return 0, false
+			}
+
+			parent := &stack[len(stack)-1]
+			if parent.left == 0 || end > parent.end {
+				return 0, false
+			}
+			parent.left--
+		}
+
+		if end <= uint64(i) || (children == 0 && end != uint64(i+1)) {
+			return 0, false
+		}
+
+		stack = append(stack, frame{end: end, left: children})
+		if len(stack) > depth {
+			depth = len(stack)
+		}
+	}
+
+	for _, f := range stack {
+		if f.left != 0 {
+			return 0, false
+		}
+	}
+
+	return depth, true
+}
@@ -0,0 +1,346 @@
+package quadtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+)
+
+func TestLoad(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	bound := orb.Bound{Min: orb.Point{-10, -10}, Max: orb.Point{10, 10}}
+	for _, n := range []int{0, 1, 10, 1000} {
+		qt := New(bound)
+		for i := 0; i < n; i++ {
+			p := orb.Point{20*r.Float64() - 10, 20*r.Float64() - 10}
+			if i%10 == 9 {
+				// some points at the same location
+				p = qt.Find(p).Point()
+			}
+
+			qt.Add(NewItem(p, uint64(i)))
+		}
+
+		buf := &bytes.Buffer{}
+		if _, err := qt.WriteTo(buf); err != nil {
+			t.Fatalf("write error: %v", err)
+		}
+
+		// copy into an unaligned slice to force the copying path
+		unaligned := make([]byte, buf.Len()+1)[1:]
+		copy(unaligned, buf.Bytes())
+
+		for _, data := range [][]byte{aligned(buf.Bytes()), unaligned} {
+			f, err := Load(data)
+			if err != nil {
+				t.Fatalf("load error: %v", err)
+			}
+
+			if f.Len() != qt.Len() || f.Bound() != qt.Bound() {
+				t.Errorf("incorrect tree: %v %v", f.Len(), f.Bound())
+			}
+
+			var expected, actual []orb.Pointer
+			qt.Walk(func(p orb.Pointer) bool {
+				expected = append(expected, p)
+				return true
+			})
+			f.Walk(func(p orb.Pointer) bool {
+				actual = append(actual, p)
+				return true
+			})
+
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("walk not equal: %v != %v", len(actual), len(expected))
+			}
+
+			even := func(p orb.Pointer) bool {
+				return p.(Item).ID()%2 == 0
+			}
+
+			for i := 0; i < 20; i++ {
+				p := orb.Point{20*r.Float64() - 10, 20*r.Float64() - 10}
+				b := p.Bound().Pad(2)
+
+				compare := func(name string, actual, expected interface{}) {
+					t.Helper()
+					if !reflect.DeepEqual(actual, expected) {
+						t.Errorf("%s not equal: %v != %v", name, actual, expected)
+					}
+				}
+
+				compare("find", f.Find(p), qt.Find(p))
+				compare("matching", f.Matching(p, even), qt.Matching(p, even))
+				compare("knearest", f.KNearest(nil, p, 5), qt.KNearest(nil, p, 5))
+				compare("knearest matching", f.KNearestMatching(nil, p, 5, even, 3), qt.KNearestMatching(nil, p, 5, even, 3))
+				compare("knearest distance", f.KNearestDistance(nil, p, 5, geo.Distance), qt.KNearestDistance(nil, p, 5, geo.Distance))
+				compare("in radius", f.InRadius(nil, p, 200000, nil), qt.InRadius(nil, p, 200000, nil))
+				compare("in bound", f.InBound(nil, b), qt.InBound(nil, b))
+				compare("in bound matching", f.InBoundMatching(nil, b, even), qt.InBoundMatching(nil, b, even))
+			}
+		}
+	}
+}
+
+func TestLoad_zeroCopy(t *testing.T) {
+	if !hostLittleEndian {
+		t.Skip("only little endian machines load without copying")
+	}
+
+	qt := New(orb.Bound{Max: orb.Point{1, 1}})
+	qt.Add(NewItem(orb.Point{0.5, 0.5}, 1))
+
+	e := NewExtents(orb.Bound{Max: orb.Point{1, 1}})
+	e.Add(NewBoundItem(orb.Bound{Max: orb.Point{0.5, 0.5}}, 1))
+
+	buf := &bytes.Buffer{}
+	if _, err := qt.WriteTo(buf); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	data := aligned(buf.Bytes())
+	f, err := Load(data)
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+
+	if &uint64Bytes(f.nodes)[0] != &data[headerSize] {
+		t.Errorf("should use the data without copying")
+	}
+
+	buf.Reset()
+	if _, err := e.WriteTo(buf); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	data = aligned(buf.Bytes())
+	fe, err := LoadExtents(data)
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+
+	if &uint64Bytes(fe.nodes)[0] != &data[headerSize] {
+		t.Errorf("should use the data without copying")
+	}
+}
+
+func TestLoad_errors(t *testing.T) {
+	// a root with the first and third child
+	qt := New(orb.Bound{Max: orb.Point{1, 1}})
+	qt.Add(NewItem(orb.Point{0.5, 0.5}, 1))
+	qt.Add(NewItem(orb.Point{0.2, 0.8}, 2))
+	qt.Add(NewItem(orb.Point{0.2, 0.2}, 3))
+
+	buf := &bytes.Buffer{}
+	if _, err := qt.WriteTo(buf); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	data := buf.Bytes()
+
+	// info is the index after the subtree and the flags
+	withInfo := func(i int, end, flags uint64) []byte {
+		d := append([]byte{}, data...)
+		binary.LittleEndian.PutUint64(d[headerSize+32*i+24:], end<<flatFlagBits|flags)
+		return d
+	}
+
+	withCount := func(count uint64) []byte {
+		d := append([]byte{}, data...)
+		binary.LittleEndian.PutUint64(d[48:], count)
+		return d
+	}
+
+	if _, err := Load(withInfo(0, 3, flatValue|1|4)); err != nil {
+		t.Fatalf("should load the unchanged tree: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "magic", data: append([]byte("ORBE"), data[4:]...)},
+		{name: "version", data: append(append([]byte{}, data[:4]...), append([]byte{9, 0, 0, 0}, data[8:]...)...)},
+		{name: "truncated", data: data[:len(data)-1]},
+		{name: "extra", data: append(append([]byte{}, data...), 0)},
+		{name: "more values", data: withCount(4)},
+		{name: "fewer values", data: withCount(2)},
+		{name: "huge count", data: withCount(1 << 63)},
+		{name: "root end", data: withInfo(0, 2, flatValue|1|4)},
+		{name: "missing child", data: withInfo(0, 3, flatValue|1)},
+		{name: "extra child", data: withInfo(0, 3, flatValue|1|2|4)},
+		{name: "leaf with subtree", data: withInfo(1, 3, flatValue)},
+		{name: "child to itself", data: withInfo(1, 1, flatValue)},
+		{name: "child of leaf", data: withInfo(1, 2, flatValue|1)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(tc.data)
+			if err != ErrInvalidData {
+				t.Errorf("incorrect error: %v", err)
+			}
+
+			_, err = (&Quadtree{}).ReadFrom(bytes.NewReader(tc.data))
+			if err != ErrInvalidData {
+				t.Errorf("incorrect read error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadExtents(t *testing.T) {
+	r := rand.New(rand.NewSource(43))
+
+	bound := orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}}
+	for _, n := range []int{0, 1, 10, 1000} {
+		e := NewExtents(bound)
+		for i, b := range randomBounds(r, n, 0.1) {
+			e.Add(NewBoundItem(b, uint64(i)))
+		}
+
+		buf := &bytes.Buffer{}
+		if _, err := e.WriteTo(buf); err != nil {
+			t.Fatalf("write error: %v", err)
+		}
+
+		unaligned := make([]byte, buf.Len()+1)[1:]
+		copy(unaligned, buf.Bytes())
+
+		for _, data := range [][]byte{aligned(buf.Bytes()), unaligned} {
+			f, err := LoadExtents(data)
+			if err != nil {
+				t.Fatalf("load error: %v", err)
+			}
+
+			if f.Len() != e.Len() || f.Bound() != e.Bound() {
+				t.Errorf("incorrect index: %v %v", f.Len(), f.Bound())
+			}
+
+			even := func(b orb.Bounder) bool {
+				return b.(BoundItem).ID()%2 == 0
+			}
+
+			for i := 0; i < 20; i++ {
+				p := orb.Point{r.Float64(), r.Float64()}
+				b := p.Bound().Pad(0.05)
+
+				compare := func(name string, actual, expected interface{}) {
+					t.Helper()
+					if !reflect.DeepEqual(actual, expected) {
+						t.Errorf("%s not equal: %v != %v", name, actual, expected)
+					}
+				}
+
+				compare("find", f.Find(p), e.Find(p))
+				compare("knearest", f.KNearest(nil, p, 5), e.KNearest(nil, p, 5))
+				compare("knearest matching", f.KNearestMatching(nil, p, 5, even, 0.2), e.KNearestMatching(nil, p, 5, even, 0.2))
+				compare("intersecting", f.Intersecting(nil, b), e.Intersecting(nil, b))
+				compare("intersecting matching", f.IntersectingMatching(nil, b, even), e.IntersectingMatching(nil, b, even))
+			}
+		}
+	}
+}
+
+func TestLoadExtents_errors(t *testing.T) {
+	bound := orb.Bound{Max: orb.Point{1, 1}}
+	items := make([]uint64, 2*extentsItemWords)
+
+	// a root with an item and children, the second child has the other item.
+	// nodes are the end of their items and the index after the subtree
+	// with a bit for children.
+	write := func(count uint64, nodes ...uint64) []byte {
+		buf := &bytes.Buffer{}
+		if _, err := writeData(buf, extentsMagic, bound, count, uint64(len(nodes)/2), nodes, items); err != nil {
+			t.Fatalf("write error: %v", err)
+		}
+		return buf.Bytes()
+	}
+
+	valid := []uint64{1, 5<<1 | 1, 1, 2 << 1, 2, 3 << 1, 2, 4 << 1, 2, 5 << 1}
+	if _, err := LoadExtents(write(2, valid...)); err != nil {
+		t.Fatalf("should load the index: %v", err)
+	}
+
+	// chain is an index with no items where the first child is split
+	// until the given depth.
+	chain := func(depth int) []byte {
+		numNodes := 4*depth + 1
+
+		var nodes []uint64
+		for d := 0; d < depth; d++ {
+			end := uint64(depth + 1 + 3*(depth-d))
+			nodes = append(nodes, 0, end<<1|1)
+		}
+
+		for i := depth; i < numNodes; i++ {
+			nodes = append(nodes, 0, uint64(i+1)<<1)
+		}
+
+		buf := &bytes.Buffer{}
+		if _, err := writeData(buf, extentsMagic, bound, 0, uint64(numNodes), nodes); err != nil {
+			t.Fatalf("write error: %v", err)
+		}
+		return buf.Bytes()
+	}
+
+	if _, err := LoadExtents(chain(extentsMaxDepth)); err != nil {
+		t.Errorf("should load an index with max depth: %v", err)
+	}
+
+	change := func(i int, v uint64) []uint64 {
+		nodes := append([]uint64{}, valid...)
+		nodes[i] = v
+		return nodes
+	}
+
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "magic", data: append([]byte("ORBQ"), write(2, valid...)[4:]...)},
+		{name: "no nodes", data: write(0)},
+		{name: "more items", data: write(3, valid...)},
+		{name: "huge count", data: write(1<<63, valid...)},
+		{name: "items past count", data: write(1, change(0, 2)...)},
+		{name: "items not in order", data: write(2, change(2, 0)...)},
+		{name: "items not all used", data: write(2, 1, 5<<1|1, 1, 2<<1, 1, 3<<1, 1, 4<<1, 1, 5<<1)},
+		{name: "root end", data: write(2, change(1, 4<<1|1)...)},
+		{name: "leaf with subtree", data: write(2, change(3, 3<<1)...)},
+		{name: "missing children", data: write(2, change(1, 5<<1)...)},
+		{name: "too deep", data: chain(extentsMaxDepth + 1)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := LoadExtents(tc.data)
+			if err != ErrInvalidData {
+				t.Errorf("incorrect error: %v", err)
+			}
+
+			_, err = (&Extents{}).ReadFrom(bytes.NewReader(tc.data))
+			if err != ErrInvalidData {
+				t.Errorf("incorrect read error: %v", err)
+			}
+		})
+	}
+}
+
+// aligned returns a copy of the data that is 8 byte aligned.
+func aligned(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+
+	b := uint64Bytes(make([]uint64, len(data)/8+1))[:len(data)]
+	copy(b, data)
+
+	return b
+}
@@ -9,6 +9,7 @@ import (
 	"math"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
 	"github.com/paulmach/orb/planar"
 )
 
@@ -23,6 +24,7 @@ var (
 type Quadtree struct {
 	bound orb.Bound
 	root  *node
+	count int
 }
 
 // A FilterFunc is a function that filters the points to search for.
@@ -46,6 +48,39 @@ func (q *Quadtree) Bound() orb.Bound {
 	return q.bound
 }
 
+// Len returns the number of pointers in the tree.
+func (q *Quadtree) Len() int {
+	return q.count
+}
+
+// Walk calls the function for every pointer in the tree, in no particular
+// order, until it returns false. The tree must not be modified during the walk.
+func (q *Quadtree) Walk(fn func(p orb.Pointer) bool) {
+	if q.root != nil {
+		walk(q.root, fn)
+	}
+}
+
+func walk(n *node, fn func(p orb.Pointer) bool) bool {
+	if n.Value != nil && !fn(n.Value) {
+		return false
+	}
+
+	for _, c := range n.Children {
+		if c != nil && !walk(c, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clear removes all the pointers from the tree. The bound stays the same.
+func (q *Quadtree) Clear() {
+	q.root = nil
+	q.count = 0
+}
+
 // Add puts an object into the quad tree, must be within the quadtree bounds.
 // This function is not thread-safe, ie. multiple goroutines cannot insert into
 // a single quadtree.
@@ -59,6 +94,7 @@ func (q *Quadtree) Add(p orb.Pointer) error {
 		return ErrPointOutsideOfBounds
 	}
 
+	q.count++
 	if q.root == nil {
 		q.root = &node{
 			Value: p,
@@ -81,9 +117,26 @@ func (q *Quadtree) Add(p orb.Pointer) error {
 
 // add is the recursive search to find a place to add the point
 func (q *Quadtree) add(n *node, p orb.Pointer, point orb.Point, left, right, bottom, top float64) {
-	i := 0
-
 	// figure which child of this internal node the point is in.
+	var i int
+	i, left, right, bottom, top = descend(point, left, right, bottom, top)
+
+	if n.Children[i] == nil {
+		n.Children[i] = &node{Value: p}
+		return
+	} else if n.Children[i].Value == nil {
+		n.Children[i].Value = p
+		return
+	}
+
+	// proceed down to the child to see if it's a leaf yet and we can add the pointer there.
+	q.add(n.Children[i], p, point, left, right, bottom, top)
+}
+
+// descend returns the child of the node that contains the point
+// and the bound of that child.
+func descend(point orb.Point, left, right, bottom, top float64) (int, float64, float64, float64, float64) {
+	i := 0
 	if cy := (bottom + top) / 2.0; point[1] <= cy {
 		top = cy
 		i = 2
@@ -98,16 +151,7 @@ func (q *Quadtree) add(n *node, p orb.Pointer, point orb.Point, left, right, bot
 		right = cx
 	}
 
-	if n.Children[i] == nil {
-		n.Children[i] = &node{Value: p}
-		return
-	} else if n.Children[i].Value == nil {
-		n.Children[i].Value = p
-		return
-	}
-
-	// proceed down to the child to see if it's a leaf yet and we can add the pointer there.
-	q.add(n.Children[i], p, point, left, right, bottom, top)
+	return i, left, right, bottom, top
 }
 
 // Remove will remove the pointer from the quadtree. By default it'll match
@@ -118,6 +162,80 @@ func (q *Quadtree) add(n *node, p orb.Pointer, point orb.Point, left, right, bot
 //		return pointer.(*MyType).ID == lookingFor.ID
 //	}
 func (q *Quadtree) Remove(p orb.Pointer, eq FilterFunc) bool {
+	n := q.matchingNode(p, eq)
+	if n == nil {
+		return false
+	}
+
+	n.Value = nil
+	q.count--
+
+	// if n is NOT a leaf node, values will be shuffled up into this node.
+	// if n IS a leaf node, the call is a no-op but we can't delete
+	// the now empty node because we don't know the parent here.
+	//
+	// Future adds will reuse this node if applicable.
+	// Removing n parent will cause this node to be removed,
+	// but the parent will be a leaf with a nil value.
+	removeNode(n)
+	return true
+}
+
+// Move changes the location of the pointer matching p, found the same way
+// as Remove, to the new point. The value is searched for using its current
+// point, so it must still report the old point when Move is called and be
+// updated to the new point after, e.g. a pointer to a struct whose location
+// is changed once Move returns. If the new point is still within the partition
+// of its node the value stays where it is, otherwise it is removed and
+// added again. Returns false if nothing matched.
+func (q *Quadtree) Move(p orb.Pointer, newPoint orb.Point, eq FilterFunc) (bool, error) {
+	if !q.bound.Contains(newPoint) {
+		return false, ErrPointOutsideOfBounds
+	}
+
+	n := q.matchingNode(p, eq)
+	if n == nil {
+		return false, nil
+	}
+
+	// values only need to be on the path of their point from the root,
+	// so the node can be kept if the path to the new point goes through it.
+	left, right := q.bound.Min[0], q.bound.Max[0]
+	bottom, top := q.bound.Min[1], q.bound.Max[1]
+	for c := q.root; c != nil; {
+		if c == n {
+			return true, nil
+		}
+
+		var i int
+		i, left, right, bottom, top = descend(newPoint, left, right, bottom, top)
+		c = c.Children[i]
+	}
+
+	value := n.Value
+	n.Value = nil
+	removeNode(n)
+
+	if q.root.Value == nil {
+		q.root.Value = value
+		return true, nil
+	}
+
+	q.add(q.root, value, newPoint,
+		q.bound.Min[0], q.bound.Max[0],
+		q.bound.Min[1], q.bound.Max[1],
+	)
+
+	return true, nil
+}
+
+// matchingNode returns the node with the closest value to the pointer
+// for which eq returns true. By default it matches using the points.
+func (q *Quadtree) matchingNode(p orb.Pointer, eq FilterFunc) *node {
+	if q.root == nil {
+		return nil
+	}
+
 	if eq == nil {
 		point := p.Point()
 		eq = func(pointer orb.Pointer) bool {
@@ -140,21 +258,7 @@ func (q *Quadtree) Remove(p orb.Pointer, eq FilterFunc) bool {
 		q.bound.Min[1], q.bound.Max[1],
 	)
 
-	if v.closest == nil {
-		return false
-	}
-
-	v.closest.Value = nil
-
-	// if v.closest is NOT a leaf node, values will be shuffled up into this node.
-	// if v.closest IS a leaf node, the call is a no-op but we can't delete
-	// the now empty node because we don't know the parent here.
-	//
-	// Future adds will reuse this node if applicable.
-	// Removing v.closest parent will cause this node to be removed,
-	// but the parent will be a leaf with a nil value.
-	removeNode(v.closest)
-	return true
+	return v.closest
 }
 
 // removeNode is the recursive fixing up of the tree when we remove a node.
@@ -200,29 +304,7 @@ func (q *Quadtree) Find(p orb.Point) orb.Pointer {
 // the given filter function returns true. This function is thread safe.
 // Multiple goroutines can read from a pre-created tree.
 func (q *Quadtree) Matching(p orb.Point, f FilterFunc) orb.Pointer {
-	if q.root == nil {
-		return nil
-	}
-
-	b := q.bound
-	v := &findVisitor{
-		point:          p,
-		filter:         f,
-		closestBound:   &b,
-		minDistSquared: math.MaxFloat64,
-	}
-
-	newVisit(v).Visit(q.root,
-		// q.bound.Left(), q.bound.Right(),
-		// q.bound.Bottom(), q.bound.Top(),
-		q.bound.Min[0], q.bound.Max[0],
-		q.bound.Min[1], q.bound.Max[1],
-	)
-
-	if v.closest == nil {
-		return nil
-	}
-	return v.closest.Value
+	return matching(q, p, f)
 }
 
 // KNearest returns k closest Value/Pointer in the quadtree.
@@ -230,6 +312,7 @@ func (q *Quadtree) Matching(p orb.Point, f FilterFunc) orb.Pointer {
 // An optional buffer parameter is provided to allow for the reuse of result slice memory.
 // The points are returned in a sorted order, nearest first.
 // This function allows defining a maximum distance in order to reduce search iterations.
+// The distance is planar, use KNearestDistance for lon/lat points.
 func (q *Quadtree) KNearest(buf []orb.Pointer, p orb.Point, k int, maxDistance ...float64) []orb.Pointer {
 	return q.KNearestMatching(buf, p, k, nil, maxDistance...)
 }
@@ -241,22 +324,83 @@ func (q *Quadtree) KNearest(buf []orb.Pointer, p orb.Point, k int, maxDistance .
 // The points are returned in a sorted order, nearest first.
 // This function allows defining a maximum distance in order to reduce search iterations.
 func (q *Quadtree) KNearestMatching(buf []orb.Pointer, p orb.Point, k int, f FilterFunc, maxDistance ...float64) []orb.Pointer {
-	if q.root == nil {
-		return nil
-	}
+	return q.KNearestDistanceMatching(buf, p, k, nil, f, maxDistance...)
+}
 
-	b := q.bound
-	v := &nearestVisitor{
-		point:          p,
-		filter:         f,
-		k:              k,
-		maxHeap:        make(maxHeap, 0, k+1),
-		closestBound:   &b,
-		maxDistSquared: math.MaxFloat64,
+// KNearestDistance returns k closest Value/Pointer in the quadtree using the
+// distance function, e.g. geo.Distance for lon/lat points, where planar
+// distances give the wrong neighbors away from the equator. The distance
+// function must return meters and the search is pruned using
+// geo.NewBoundAroundPoint. If nil the planar distance is used, the same
+// as KNearest. The maximum distance uses the same units as the distance.
+func (q *Quadtree) KNearestDistance(buf []orb.Pointer, p orb.Point, k int, df orb.DistanceFunc, maxDistance ...float64) []orb.Pointer {
+	return q.KNearestDistanceMatching(buf, p, k, df, nil, maxDistance...)
+}
+
+// KNearestDistanceMatching returns k closest Value/Pointer in the quadtree
+// using the distance function and for which the given filter function returns
+// true. See KNearestDistance for more details.
+func (q *Quadtree) KNearestDistanceMatching(buf []orb.Pointer, p orb.Point, k int, df orb.DistanceFunc, f FilterFunc, maxDistance ...float64) []orb.Pointer {
+	return kNearest(q, buf, p, k, df, f, maxDistance...)
+}
+
+// InRadius returns a slice with all the pointers in the quadtree within the
+// distance, in meters, of the center. The distance function defaults to
+// geo.Distance and the search is pruned using geo.NewBoundAroundPoint.
+// An optional buffer parameter is provided to allow for the reuse of result
+// slice memory. The points are not sorted. This function is thread safe.
+// Multiple goroutines can read from a pre-created tree.
+func (q *Quadtree) InRadius(buf []orb.Pointer, center orb.Point, meters float64, df orb.DistanceFunc) []orb.Pointer {
+	return q.InRadiusMatching(buf, center, meters, df, nil)
+}
+
+// InRadiusMatching returns a slice with all the pointers in the quadtree within
+// the distance, in meters, of the center and matching the given filter function.
+// See InRadius for more details.
+func (q *Quadtree) InRadiusMatching(buf []orb.Pointer, center orb.Point, meters float64, df orb.DistanceFunc, f FilterFunc) []orb.Pointer {
+	return inRadius(q, buf, center, meters, df, f)
+}
+
+// geoBound returns a lon/lat bound containing all the points within the
+// distance, in meters, of the center. The distance is padded by 1% so
+// ellipsoidal distances, e.g. geo.GeodesicDistance, are also contained.
+// Bounds that cross the antimeridian use the full longitude range.
+func geoBound(center orb.Point, meters float64) orb.Bound {
+	b := geo.NewBoundAroundPoint(center, 1.01*meters)
+	if b.Min[0] > b.Max[0] {
+		b.Min[0], b.Max[0] = -180, 180
 	}
 
-	if len(maxDistance) > 0 {
-		v.maxDistSquared = maxDistance[0] * maxDistance[0]
+	return b
+}
+
+// InBound returns a slice with all the pointers in the quadtree that are
+// within the given bound. An optional buffer parameter is provided to allow
+// for the reuse of result slice memory. This function is thread safe.
+// Multiple goroutines can read from a pre-created tree.
+func (q *Quadtree) InBound(buf []orb.Pointer, b orb.Bound) []orb.Pointer {
+	return q.InBoundMatching(buf, b, nil)
+}
+
+// InBoundMatching returns a slice with all the pointers in the quadtree that are
+// within the given bound and matching the give filter function. An optional buffer
+// parameter is provided to allow for the reuse of result slice memory. This function
+// is thread safe.  Multiple goroutines can read from a pre-created tree.
+func (q *Quadtree) InBoundMatching(buf []orb.Pointer, b orb.Bound, f FilterFunc) []orb.Pointer {
+	return inBound(q, buf, b, f)
+}
+
+// searcher is a Quadtree or a Flat tree, so they can share the search functions.
+type searcher interface {
+	Bound() orb.Bound
+
+	// visit walks the tree with the visitor and returns false if there is no root.
+	visit(v visitor) bool
+}
+
+func (q *Quadtree) visit(v visitor) bool {
+	if q.root == nil {
+		return false
 	}
 
 	newVisit(v).Visit(q.root,
@@ -266,6 +410,50 @@ func (q *Quadtree) KNearestMatching(buf []orb.Pointer, p orb.Point, k int, f Fil
 		q.bound.Min[1], q.bound.Max[1],
 	)
 
+	return true
+}
+
+func matching(s searcher, p orb.Point, f FilterFunc) orb.Pointer {
+	b := s.Bound()
+	v := &findVisitor{
+		point:          p,
+		filter:         f,
+		closestBound:   &b,
+		minDistSquared: math.MaxFloat64,
+	}
+
+	if !s.visit(v) || v.closest == nil {
+		return nil
+	}
+
+	return v.closest.Value
+}
+
+func kNearest(s searcher, buf []orb.Pointer, p orb.Point, k int, df orb.DistanceFunc, f FilterFunc, maxDistance ...float64) []orb.Pointer {
+	b := s.Bound()
+	v := &nearestVisitor{
+		point:        p,
+		filter:       f,
+		distFunc:     df,
+		k:            k,
+		maxHeap:      make(maxHeap, 0, k+1),
+		closestBound: &b,
+		maxDistance:  math.MaxFloat64,
+	}
+
+	if len(maxDistance) > 0 {
+		if df == nil {
+			v.maxDistance = maxDistance[0] * maxDistance[0]
+		} else {
+			v.maxDistance = maxDistance[0]
+			b = geoBound(p, maxDistance[0])
+		}
+	}
+
+	if !s.visit(v) {
+		return nil
+	}
+
 	//repack result
 	if cap(buf) < len(v.maxHeap) {
 		buf = make([]orb.Pointer, len(v.maxHeap))
@@ -281,23 +469,21 @@ func (q *Quadtree) KNearestMatching(buf []orb.Pointer, p orb.Point, k int, f Fil
 	return buf
 }
 
-// InBound returns a slice with all the pointers in the quadtree that are
-// within the given bound. An optional buffer parameter is provided to allow
-// for the reuse of result slice memory. This function is thread safe.
-// Multiple goroutines can read from a pre-created tree.
-func (q *Quadtree) InBound(buf []orb.Pointer, b orb.Bound) []orb.Pointer {
-	return q.InBoundMatching(buf, b, nil)
-}
-
-// InBoundMatching returns a slice with all the pointers in the quadtree that are
-// within the given bound and matching the give filter function. An optional buffer
-// parameter is provided to allow for the reuse of result slice memory. This function
-// is thread safe.  Multiple goroutines can read from a pre-created tree.
-func (q *Quadtree) InBoundMatching(buf []orb.Pointer, b orb.Bound, f FilterFunc) []orb.Pointer {
-	if q.root == nil {
-		return nil
+func inRadius(s searcher, buf []orb.Pointer, center orb.Point, meters float64, df orb.DistanceFunc, f FilterFunc) []orb.Pointer {
+	if df == nil {
+		df = geo.Distance
 	}
 
+	return inBound(s, buf, geoBound(center, meters), func(p orb.Pointer) bool {
+		if f != nil && !f(p) {
+			return false
+		}
+
+		return df(center, p.Point()) <= meters
+	})
+}
+
+func inBound(s searcher, buf []orb.Pointer, b orb.Bound, f FilterFunc) []orb.Pointer {
 	var p []orb.Pointer
 	if buf != nil {
 		p = buf[:0]
@@ -308,12 +494,9 @@ func (q *Quadtree) InBoundMatching(buf []orb.Pointer, b orb.Bound, f FilterFunc)
 		filter:   f,
 	}
 
-	newVisit(v).Visit(q.root,
-		// q.bound.Left(), q.bound.Right(),
-		// q.bound.Bottom(), q.bound.Top(),
-		q.bound.Min[0], q.bound.Max[0],
-		q.bound.Min[1], q.bound.Max[1],
-	)
+	if !s.visit(v) {
+		return nil
+	}
 
 	return v.pointers
 }
@@ -467,12 +650,15 @@ func (v *findVisitor) Visit(n *node) {
 // }
 
 type nearestVisitor struct {
-	point          orb.Point
-	filter         FilterFunc
-	k              int
-	maxHeap        maxHeap
-	closestBound   *orb.Bound
-	maxDistSquared float64
+	point        orb.Point
+	filter       FilterFunc
+	distFunc     orb.DistanceFunc
+	k            int
+	maxHeap      maxHeap
+	closestBound *orb.Bound
+
+	// maxDistance is squared if using the planar distance.
+	maxDistance float64
 }
 
 func (v *nearestVisitor) Bound() *orb.Bound {
@@ -489,8 +675,14 @@ func (v *nearestVisitor) Visit(n *node) {
 		return
 	}
 
-	point := n.Value.Point()
-	if d := planar.DistanceSquared(point, v.point); d < v.maxDistSquared {
+	var d float64
+	if v.distFunc == nil {
+		d = planar.DistanceSquared(n.Value.Point(), v.point)
+	} else {
+		d = v.distFunc(v.point, n.Value.Point())
+	}
+
+	if d < v.maxDistance {
 		v.maxHeap.Push(n.Value, d)
 		if len(v.maxHeap) > v.k {
 
@@ -500,9 +692,14 @@ func (v *nearestVisitor) Visit(n *node) {
 			// top element without function call
 			top := v.maxHeap[0]
 
-			v.maxDistSquared = top.distance
+			v.maxDistance = top.distance
 
 			// We have filled queue, so we start to restrict searching range
+			if v.distFunc != nil {
+				*v.closestBound = geoBound(v.point, top.distance)
+				return
+			}
+
 			d = math.Sqrt(top.distance)
 			v.closestBound.Min[0] = v.point[0] - d
 			v.closestBound.Max[0] = v.point[0] + d
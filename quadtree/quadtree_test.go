@@ -2,6 +2,7 @@ package quadtree
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"sort"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
 	"github.com/paulmach/orb/planar"
 )
 
@@ -773,3 +775,445 @@ func countNodes(n *node) int {
 
 	return c
 }
+
+func TestQuadtreeMove(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	qt := New(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	ps := make([]*PExtra, 1000)
+	for i := range ps {
+		ps[i] = &PExtra{p: orb.Point{r.Float64(), r.Float64()}, id: fmt.Sprint(i)}
+		if err := qt.Add(ps[i]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for i := 0; i < 5000; i++ {
+		pe := ps[r.Intn(len(ps))]
+
+		// mix of small moves, that should keep the node, and large ones
+		np := orb.Point{r.Float64(), r.Float64()}
+		if i%2 == 0 {
+			np[0] = math.Max(0, math.Min(1, pe.p[0]+0.001*(r.Float64()-0.5)))
+			np[1] = math.Max(0, math.Min(1, pe.p[1]+0.001*(r.Float64()-0.5)))
+		}
+
+		moved, err := qt.Move(pe, np, func(p orb.Pointer) bool {
+			return p.(*PExtra).id == pe.id
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !moved {
+			t.Fatalf("point not moved: %v", pe)
+		}
+
+		pe.p = np
+	}
+
+	if l := qt.Len(); l != len(ps) {
+		t.Errorf("incorrect length: %v != %v", l, len(ps))
+	}
+
+	checkPaths(t, qt)
+
+	mp := orb.MultiPoint{}
+	for _, pe := range ps {
+		mp = append(mp, pe.p)
+	}
+
+	for i := 0; i < 1000; i++ {
+		p := orb.Point{r.Float64(), r.Float64()}
+
+		f := qt.Find(p)
+		_, j := planar.DistanceFromWithIndex(mp, p)
+
+		if e := mp[j]; !e.Equal(f.Point()) {
+			t.Errorf("index: %d, unexpected point %v != %v", i, e, f.Point())
+		}
+	}
+}
+
+func TestQuadtreeMove_sameNode(t *testing.T) {
+	qt := New(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+
+	p1 := &PExtra{p: orb.Point{0.1, 0.1}, id: "1"}
+	p2 := &PExtra{p: orb.Point{0.2, 0.2}, id: "2"}
+	qt.Add(p1)
+	qt.Add(p2)
+
+	// still in the bottom left quadrant
+	moved, err := qt.Move(p2, orb.Point{0.3, 0.4}, nil)
+	p2.p = orb.Point{0.3, 0.4}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !moved {
+		t.Errorf("should move point")
+	}
+
+	if qt.root.Children[2].Value != p2 {
+		t.Errorf("should keep the node")
+	}
+
+	// to the top right quadrant
+	qt.Move(p2, orb.Point{0.8, 0.8}, nil)
+	p2.p = orb.Point{0.8, 0.8}
+
+	// like Remove, the empty leaf is kept for future adds
+	if qt.root.Children[2].Value != nil {
+		t.Errorf("should empty the node")
+	}
+	if qt.root.Children[1].Value != p2 {
+		t.Errorf("should add to a new node")
+	}
+
+	// the root value can be moved anywhere
+	qt.Move(p1, orb.Point{0.9, 0.1}, nil)
+	p1.p = orb.Point{0.9, 0.1}
+	if qt.root.Value != p1 {
+		t.Errorf("should keep the root node")
+	}
+
+	if c := countNodes(qt.root); c != 3 {
+		t.Errorf("incorrect number of nodes: %v != 3", c)
+	}
+}
+
+func TestQuadtreeMove_errors(t *testing.T) {
+	qt := New(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+
+	moved, err := qt.Move(orb.Point{0.5, 0.5}, orb.Point{0.1, 0.1}, nil)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if moved {
+		t.Errorf("should not move point in empty tree")
+	}
+
+	qt.Add(orb.Point{0.5, 0.5})
+	moved, err = qt.Move(orb.Point{0.5, 0.5}, orb.Point{2, 2}, nil)
+	if err != ErrPointOutsideOfBounds {
+		t.Errorf("incorrect error: %v", err)
+	}
+	if moved {
+		t.Errorf("should not move point outside of bounds")
+	}
+
+	moved, err = qt.Move(orb.Point{0.4, 0.4}, orb.Point{0.1, 0.1}, nil)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if moved {
+		t.Errorf("should not move point that does not match")
+	}
+}
+
+func TestQuadtreeLen(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	qt := New(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	if l := qt.Len(); l != 0 {
+		t.Errorf("empty tree should have length 0: %v", l)
+	}
+
+	mp := orb.MultiPoint{}
+	for i := 0; i < 100; i++ {
+		mp = append(mp, orb.Point{r.Float64(), r.Float64()})
+		qt.Add(mp[i])
+	}
+
+	qt.Add(orb.Point{2, 2}) // outside bound
+	if l := qt.Len(); l != 100 {
+		t.Errorf("incorrect length: %v != 100", l)
+	}
+
+	for i := 0; i < 100; i += 2 {
+		qt.Remove(mp[i], nil)
+	}
+	qt.Remove(orb.Point{2, 2}, nil)
+
+	if l := qt.Len(); l != 50 {
+		t.Errorf("incorrect length: %v != 50", l)
+	}
+
+	qt.Clear()
+	if l := qt.Len(); l != 0 {
+		t.Errorf("cleared tree should have length 0: %v", l)
+	}
+
+	if p := qt.Find(orb.Point{0.5, 0.5}); p != nil {
+		t.Errorf("cleared tree should not find points: %v", p)
+	}
+
+	qt.Add(mp[1])
+	if p := qt.Find(orb.Point{0.5, 0.5}); p != mp[1] {
+		t.Errorf("should find point after clear: %v", p)
+	}
+}
+
+func TestQuadtreeWalk(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	qt := New(orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}})
+	qt.Walk(func(p orb.Pointer) bool {
+		t.Errorf("should not walk empty tree")
+		return true
+	})
+
+	expected := map[orb.Point]bool{}
+	for i := 0; i < 100; i++ {
+		p := orb.Point{r.Float64(), r.Float64()}
+		expected[p] = true
+		qt.Add(p)
+	}
+
+	// removed values leave empty nodes
+	for i := 0; i < 20; i++ {
+		p := qt.Find(orb.Point{r.Float64(), r.Float64()})
+		delete(expected, p.Point())
+		qt.Remove(p, nil)
+	}
+
+	seen := map[orb.Point]bool{}
+	qt.Walk(func(p orb.Pointer) bool {
+		seen[p.Point()] = true
+		return true
+	})
+
+	if !reflect.DeepEqual(seen, expected) {
+		t.Errorf("incorrect points walked: %v != %v", len(seen), len(expected))
+	}
+
+	count := 0
+	qt.Walk(func(p orb.Pointer) bool {
+		count++
+		return count < 10
+	})
+
+	if count != 10 {
+		t.Errorf("should stop walking: %v", count)
+	}
+}
+
+func TestQuadtreeKNearestDistance(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	// high latitudes where planar distances are very wrong,
+	// including points on both sides of the antimeridian.
+	qt := New(orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, 90}})
+	mp := orb.MultiPoint{}
+	for i := 0; i < 1000; i++ {
+		p := orb.Point{360*r.Float64() - 180, 55 + 35*r.Float64()}
+		mp = append(mp, p)
+		qt.Add(p)
+	}
+
+	dfs := map[string]orb.DistanceFunc{
+		"distance":  geo.Distance,
+		"haversine": geo.DistanceHaversine,
+		"geodesic":  geo.GeodesicDistance,
+	}
+
+	planarDiffers := false
+	for name, df := range dfs {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				p := orb.Point{360*r.Float64() - 180, 55 + 35*r.Float64()}
+				if i%10 == 0 {
+					p[0] = 179.9
+				}
+
+				result := qt.KNearestDistance(nil, p, 5, df)
+				expected := nearestByDistance(mp, p, 5, df)
+				if !reflect.DeepEqual(pointersToPoints(result), expected) {
+					t.Errorf("incorrect points for %v: %v != %v", p, result, expected)
+				}
+
+				planar := qt.KNearest(nil, p, 5)
+				if !reflect.DeepEqual(pointersToPoints(planar), expected) {
+					planarDiffers = true
+				}
+
+				// the max distance is in meters
+				max := df(p, expected[2]) + 1
+				result = qt.KNearestDistance(nil, p, 5, df, max)
+				if !reflect.DeepEqual(pointersToPoints(result), expected[:3]) {
+					t.Errorf("incorrect points for %v: %v != %v", p, result, expected[:3])
+				}
+			}
+		})
+	}
+
+	if !planarDiffers {
+		t.Errorf("planar distance should give different neighbors")
+	}
+}
+
+func TestQuadtreeKNearestDistanceMatching(t *testing.T) {
+	qt := New(orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, 90}})
+	pointers := []*PExtra{
+		{p: orb.Point{10, 70}, id: "1"},
+		{p: orb.Point{11, 70}, id: "2"},
+		{p: orb.Point{10, 70.5}, id: "3"},
+		{p: orb.Point{30, 70}, id: "4"},
+	}
+
+	for _, p := range pointers {
+		qt.Add(p)
+	}
+
+	// a degree of longitude is ~38km, a degree of latitude ~111km
+	result := qt.KNearestDistance(nil, orb.Point{10, 70}, 3, geo.Distance)
+	if ids := pointerIDs(result); !reflect.DeepEqual(ids, []string{"1", "2", "3"}) {
+		t.Errorf("incorrect order: %v", ids)
+	}
+
+	result = qt.KNearestDistanceMatching(nil, orb.Point{10, 70}, 3, geo.Distance, func(p orb.Pointer) bool {
+		return p.(*PExtra).id != "2"
+	}, 100000)
+	if ids := pointerIDs(result); !reflect.DeepEqual(ids, []string{"1", "3"}) {
+		t.Errorf("incorrect filtered points: %v", ids)
+	}
+
+	// nil distance function is planar
+	result = qt.KNearestDistance(nil, orb.Point{10, 70}, 3, nil)
+	if ids := pointerIDs(result); !reflect.DeepEqual(ids, []string{"1", "3", "2"}) {
+		t.Errorf("incorrect planar order: %v", ids)
+	}
+}
+
+func TestQuadtreeInRadius(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	qt := New(orb.Bound{Min: orb.Point{-180, -90}, Max: orb.Point{180, 90}})
+	mp := orb.MultiPoint{}
+	for i := 0; i < 1000; i++ {
+		p := orb.Point{360*r.Float64() - 180, 50 + 40*r.Float64()}
+		mp = append(mp, p)
+		qt.Add(p)
+	}
+
+	cases := []struct {
+		name   string
+		center orb.Point
+		meters float64
+		df     orb.DistanceFunc
+	}{
+		{name: "default", center: orb.Point{10, 60}, meters: 500000},
+		{name: "geodesic", center: orb.Point{10, 60}, meters: 500000, df: geo.GeodesicDistance},
+		{name: "antimeridian", center: orb.Point{179.5, 70}, meters: 300000},
+		{name: "pole", center: orb.Point{0, 85}, meters: 800000},
+		{name: "empty", center: orb.Point{0, -45}, meters: 100000},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			df := tc.df
+			if df == nil {
+				df = geo.Distance
+			}
+
+			expected := map[orb.Point]bool{}
+			for _, p := range mp {
+				if df(tc.center, p) <= tc.meters {
+					expected[p] = true
+				}
+			}
+
+			result := map[orb.Point]bool{}
+			for _, p := range qt.InRadius(nil, tc.center, tc.meters, tc.df) {
+				result[p.Point()] = true
+			}
+
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("incorrect points: %v != %v", len(result), len(expected))
+			}
+
+			if tc.name != "empty" && len(expected) == 0 {
+				t.Errorf("should find some points")
+			}
+		})
+	}
+
+	t.Run("matching", func(t *testing.T) {
+		center := orb.Point{10, 60}
+		all := qt.InRadius(nil, center, 1000000, nil)
+		filtered := qt.InRadiusMatching(nil, center, 1000000, nil, func(p orb.Pointer) bool {
+			return p.Point()[1] > 60
+		})
+
+		count := 0
+		for _, p := range all {
+			if p.Point()[1] > 60 {
+				count++
+			}
+		}
+
+		if len(filtered) != count {
+			t.Errorf("incorrect number of points: %v != %v", len(filtered), count)
+		}
+	})
+}
+
+// nearestByDistance returns the k nearest points using a brute force search.
+func nearestByDistance(mp orb.MultiPoint, p orb.Point, k int, df orb.DistanceFunc) []orb.Point {
+	distances := make([]float64, len(mp))
+	indexes := make([]int, len(mp))
+	for i := range mp {
+		distances[i] = df(p, mp[i])
+		indexes[i] = i
+	}
+
+	sort.Slice(indexes, func(i, j int) bool {
+		return distances[indexes[i]] < distances[indexes[j]]
+	})
+
+	result := make([]orb.Point, 0, k)
+	for _, i := range indexes[:k] {
+		result = append(result, mp[i])
+	}
+
+	return result
+}
+
+func pointersToPoints(pointers []orb.Pointer) []orb.Point {
+	result := make([]orb.Point, 0, len(pointers))
+	for _, p := range pointers {
+		result = append(result, p.Point())
+	}
+
+	return result
+}
+
+func pointerIDs(pointers []orb.Pointer) []string {
+	result := make([]string, 0, len(pointers))
+	for _, p := range pointers {
+		result = append(result, p.(*PExtra).id)
+	}
+
+	return result
+}
+
+// checkPaths makes sure every value is on the path of its point from the root.
+func checkPaths(t testing.TB, qt *Quadtree) {
+	t.Helper()
+
+	qt.Walk(func(p orb.Pointer) bool {
+		left, right := qt.bound.Min[0], qt.bound.Max[0]
+		bottom, top := qt.bound.Min[1], qt.bound.Max[1]
+
+		for n := qt.root; n != nil; {
+			if n.Value == p {
+				return true
+			}
+
+			var i int
+			i, left, right, bottom, top = descend(p.Point(), left, right, bottom, top)
+			n = n.Children[i]
+		}
+
+		t.Errorf("value not on the path of its point: %v", p)
+		return true
+	})
+}
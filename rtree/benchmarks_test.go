@@ -0,0 +1,103 @@
+package rtree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+// These mirror the quadtree benchmarks, using the same random points,
+// so the two can be compared.
+
+func randomPoints(r *rand.Rand, n int) []orb.Bound {
+	bounds := make([]orb.Bound, 0, n)
+	for i := 0; i < n; i++ {
+		p := orb.Point{r.Float64(), r.Float64()}
+		bounds = append(bounds, p.Bound())
+	}
+
+	return bounds
+}
+
+func BenchmarkNewHilbert100000(b *testing.B) {
+	bounds := randomPoints(rand.New(rand.NewSource(22)), 100000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewFromBounds(bounds, Hilbert, DefaultNodeSize)
+	}
+}
+
+func BenchmarkNewSTR100000(b *testing.B) {
+	bounds := randomPoints(rand.New(rand.NewSource(22)), 100000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewFromBounds(bounds, STR, DefaultNodeSize)
+	}
+}
+
+func BenchmarkRandomSearch1000(b *testing.B) {
+	r := rand.New(rand.NewSource(43))
+	tree := NewFromBounds(randomPoints(r, 1000), Hilbert, DefaultNodeSize)
+
+	var buf []uint64
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := orb.Point{r.Float64(), r.Float64()}
+
+		buf = buf[:0]
+		tree.Search(p.Bound().Pad(0.1), func(i uint64) bool {
+			buf = append(buf, i)
+			return true
+		})
+	}
+}
+
+func BenchmarkRandomSearch1000STR(b *testing.B) {
+	r := rand.New(rand.NewSource(43))
+	tree := NewFromBounds(randomPoints(r, 1000), STR, DefaultNodeSize)
+
+	var buf []uint64
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := orb.Point{r.Float64(), r.Float64()}
+
+		buf = buf[:0]
+		tree.Search(p.Bound().Pad(0.1), func(i uint64) bool {
+			buf = append(buf, i)
+			return true
+		})
+	}
+}
+
+func BenchmarkRandomNearest10(b *testing.B) {
+	r := rand.New(rand.NewSource(43))
+	tree := NewFromBounds(randomPoints(r, 1000), Hilbert, DefaultNodeSize)
+
+	buf := make([]uint64, 0, 10)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Nearest(buf, orb.Point{r.Float64(), r.Float64()}, 10)
+	}
+}
+
+func BenchmarkRandomNearest100(b *testing.B) {
+	r := rand.New(rand.NewSource(43))
+	tree := NewFromBounds(randomPoints(r, 1000), Hilbert, DefaultNodeSize)
+
+	buf := make([]uint64, 0, 100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Nearest(buf, orb.Point{r.Float64(), r.Float64()}, 100)
+	}
+}
@@ -0,0 +1,208 @@
+package rtree
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// ErrInvalidData is returned when loading data that is not a serialized tree.
+var ErrInvalidData = errors.New("rtree: invalid data")
+
+const (
+	magic   = "ORBR"
+	version = 1
+
+	// headerSize is the magic, version, node size, 4 unused bytes and the
+	// number of items. It's a multiple of 8 so the boxes are aligned if
+	// the data is aligned.
+	headerSize = 24
+)
+
+var hostLittleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// WriteTo writes the tree in a compact binary format that can be read
+// using ReadFrom or Load. The format is the header followed by the node
+// bounds and the ids, all little endian.
+func (t *RTree) WriteTo(w io.Writer) (int64, error) {
+	var header [headerSize]byte
+	copy(header[:], magic)
+	binary.LittleEndian.PutUint32(header[4:], version)
+	binary.LittleEndian.PutUint32(header[8:], uint32(t.nodeSize))
+	binary.LittleEndian.PutUint64(header[16:], uint64(t.numItems))
+
+	var written int64
+	n, err := w.Write(header[:])
+	written += int64(n)
+	if err != nil {
+		return written, err
+	}
+
+	if hostLittleEndian {
+		n, err = w.Write(float64Bytes(t.boxes))
+	} else {
+		buf := make([]byte, 8*len(t.boxes))
+		for i, v := range t.boxes {
+			binary.LittleEndian.PutUint64(buf[8*i:], math.Float64bits(v))
+		}
+		n, err = w.Write(buf)
+	}
+
+	written += int64(n)
+	if err != nil {
+		return written, err
+	}
+
+	if hostLittleEndian {
+		n, err = w.Write(uint64Bytes(t.indices))
+	} else {
+		buf := make([]byte, 8*len(t.indices))
+		for i, v := range t.indices {
+			binary.LittleEndian.PutUint64(buf[8*i:], v)
+		}
+		n, err = w.Write(buf)
+	}
+
+	written += int64(n)
+	return written, err
+}
+
+// ReadFrom reads a tree written by WriteTo into t, replacing its contents.
+func (t *RTree) ReadFrom(r io.Reader) (int64, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+
+	loaded, err := Load(data)
+	if err != nil {
+		return int64(len(data)), err
+	}
+
+	*t = *loaded
+	return int64(len(data)), nil
+}
+
+// Load returns the tree written by WriteTo in the data. If possible the tree
+// uses the data directly without copying, e.g. for a memory-mapped file, so
+// the data must not be modified while the tree is in use. The data is copied
+// if it's not 8 byte aligned or the machine is not little endian.
+func Load(data []byte) (*RTree, error) {
+	if len(data) < headerSize || string(data[:4]) != magic {
+		return nil, ErrInvalidData
+	}
+
+	if v := binary.LittleEndian.Uint32(data[4:]); v != version {
+		return nil, ErrInvalidData
+	}
+
+	// every item is at least one node of 40 bytes
+	numItems := binary.LittleEndian.Uint64(data[16:])
+	if numItems > uint64(len(data))/40 {
+		return nil, ErrInvalidData
+	}
+
+	t := &RTree{
+		nodeSize: int(binary.LittleEndian.Uint32(data[8:])),
+		numItems: int(numItems),
+	}
+
+	if t.nodeSize < 2 || t.nodeSize > maxNodeSize {
+		return nil, ErrInvalidData
+	}
+
+	numNodes := t.computeLevels()
+	if len(data) != headerSize+40*numNodes {
+		return nil, ErrInvalidData
+	}
+
+	boxes := data[headerSize : headerSize+32*numNodes]
+	indices := data[headerSize+32*numNodes:]
+
+	if numNodes == 0 {
+		t.boxes = []float64{}
+		t.indices = []uint64{}
+	} else if hostLittleEndian && uintptr(unsafe.Pointer(&data[0]))%8 == 0 {
+		t.boxes = bytesFloat64(boxes)
+		t.indices = bytesUint64(indices)
+	} else {
+		t.boxes = make([]float64, 4*numNodes)
+		for i := range t.boxes {
+			t.boxes[i] = math.Float64frombits(binary.LittleEndian.Uint64(boxes[8*i:]))
+		}
+
+		t.indices = make([]uint64, numNodes)
+		for i := range t.indices {
+			t.indices[i] = binary.LittleEndian.Uint64(indices[8*i:])
+		}
+	}
+
+	// the child offsets of a packed tree only depend on the number of items
+	// and the node size, make sure they match so searching always ends.
+	start := 0
+	for l, end := range t.levelBounds[:len(t.levelBounds)-1] {
+		for i := end; i < t.levelBounds[l+1]; i++ {
+			if t.indices[i] != uint64(start+(i-end)*t.nodeSize) {
+				return nil, ErrInvalidData
+			}
+		}
+		start = end
+	}
+
+	return t, nil
+}
+
+func float64Bytes(s []float64) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+
+	var b []byte
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	h.Data = uintptr(unsafe.Pointer(&s[0]))
+	h.Len = 8 * len(s)
+	h.Cap = 8 * len(s)
+
+	return b
+}
+
+func uint64Bytes(s []uint64) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+
+	var b []byte
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	h.Data = uintptr(unsafe.Pointer(&s[0]))
+	h.Len = 8 * len(s)
+	h.Cap = 8 * len(s)
+
+	return b
+}
+
+func bytesFloat64(b []byte) []float64 {
+	var s []float64
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	h.Data = uintptr(unsafe.Pointer(&b[0]))
+	h.Len = len(b) / 8
+	h.Cap = len(b) / 8
+
+	return s
+}
+
+func bytesUint64(b []byte) []uint64 {
+	var s []uint64
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	h.Data = uintptr(unsafe.Pointer(&b[0]))
+	h.Len = len(b) / 8
+	h.Cap = len(b) / 8
+
+	return s
+}
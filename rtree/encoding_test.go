@@ -0,0 +1,202 @@
+package rtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestRTreeWriteTo(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for _, n := range []int{0, 1, 16, 1000} {
+		bounds := randomBounds(r, n, 0.1)
+
+		ids := make([]uint64, n)
+		for i := range ids {
+			ids[i] = uint64(1000 + 2*i)
+		}
+
+		tree := NewWithIDs(bounds, ids, Hilbert, 8)
+
+		buf := &bytes.Buffer{}
+		written, err := tree.WriteTo(buf)
+		if err != nil {
+			t.Fatalf("write error: %v", err)
+		}
+
+		if written != int64(buf.Len()) {
+			t.Errorf("incorrect length: %v != %v", written, buf.Len())
+		}
+
+		data := buf.Bytes()
+
+		// copy into an unaligned slice to force the copying path
+		unaligned := make([]byte, len(data)+1)[1:]
+		copy(unaligned, data)
+
+		read := &RTree{}
+		if _, err := read.ReadFrom(bytes.NewReader(data)); err != nil {
+			t.Fatalf("read error: %v", err)
+		}
+
+		loaded, err := Load(unaligned)
+		if err != nil {
+			t.Fatalf("load error: %v", err)
+		}
+
+		for _, result := range []*RTree{read, loaded} {
+			if result.Len() != n || !reflect.DeepEqual(result.levelBounds, tree.levelBounds) {
+				t.Fatalf("incorrect tree: %v %v", result.Len(), result.levelBounds)
+			}
+
+			for i := 0; i < 20; i++ {
+				p := orb.Point{r.Float64(), r.Float64()}
+				query := p.Bound().Pad(0.1)
+
+				var expected, actual []uint64
+				tree.Search(query, func(id uint64) bool {
+					expected = append(expected, id)
+					return true
+				})
+
+				result.Search(query, func(id uint64) bool {
+					actual = append(actual, id)
+					return true
+				})
+
+				if !reflect.DeepEqual(expected, actual) {
+					t.Errorf("search not equal: %v != %v", actual, expected)
+				}
+
+				expected = tree.Nearest(expected, p, 5)
+				actual = result.Nearest(actual, p, 5)
+				if !reflect.DeepEqual(expected, actual) {
+					t.Errorf("nearest not equal: %v != %v", actual, expected)
+				}
+
+				for _, id := range actual {
+					if id < 1000 || id%2 != 0 {
+						t.Errorf("should return the ids: %v", id)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLoad_zeroCopy(t *testing.T) {
+	if !hostLittleEndian {
+		t.Skip("only little endian machines load without copying")
+	}
+
+	tree := NewFromBounds(randomBounds(rand.New(rand.NewSource(43)), 100, 0.1), STR, DefaultNodeSize)
+
+	buf := &bytes.Buffer{}
+	if _, err := tree.WriteTo(buf); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	// a []float64 is 8 byte aligned
+	aligned := float64Bytes(make([]float64, buf.Len()/8+1))[:buf.Len()]
+	copy(aligned, buf.Bytes())
+
+	loaded, err := Load(aligned)
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+
+	if &float64Bytes(loaded.boxes)[0] != &aligned[headerSize] {
+		t.Errorf("should use the data without copying")
+	}
+}
+
+func TestLoad_errors(t *testing.T) {
+	tree := NewFromBounds(randomBounds(rand.New(rand.NewSource(44)), 100, 0.1), Hilbert, DefaultNodeSize)
+
+	buf := &bytes.Buffer{}
+	if _, err := tree.WriteTo(buf); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	data := buf.Bytes()
+
+	withItems := func(n uint64) []byte {
+		d := append([]byte{}, data...)
+		binary.LittleEndian.PutUint64(d[16:], n)
+		return d
+	}
+
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "magic", data: append([]byte("ABCD"), data[4:]...)},
+		{name: "version", data: append(append([]byte{}, data[:4]...), append([]byte{9, 0, 0, 0}, data[8:]...)...)},
+		{name: "truncated", data: data[:len(data)-1]},
+		{name: "extra", data: append(append([]byte{}, data...), 0)},
+		{name: "items", data: withItems(101)},
+		{name: "huge items", data: withItems(1 << 40)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(tc.data)
+			if err != ErrInvalidData {
+				t.Errorf("incorrect error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoad_corruptOffsets(t *testing.T) {
+	// 40 items with node size 4 gives 10, 3 and 1 parent nodes.
+	tree := NewFromBounds(randomBounds(rand.New(rand.NewSource(45)), 40, 0.1), Hilbert, 4)
+
+	buf := &bytes.Buffer{}
+	if _, err := tree.WriteTo(buf); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	numNodes := len(tree.indices)
+	if numNodes != 54 {
+		t.Fatalf("incorrect number of nodes: %v", numNodes)
+	}
+
+	cases := []struct {
+		name   string
+		node   int
+		offset uint64
+	}{
+		{name: "sibling", node: 41, offset: 40},
+		{name: "self", node: 41, offset: 41},
+		{name: "level above", node: 41, offset: 52},
+		{name: "misaligned", node: 41, offset: 5},
+		{name: "root", node: 53, offset: 40},
+		{name: "out of range", node: 53, offset: 1000},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := append([]byte{}, buf.Bytes()...)
+			binary.LittleEndian.PutUint64(data[headerSize+32*numNodes+8*tc.node:], tc.offset)
+
+			_, err := Load(data)
+			if err != ErrInvalidData {
+				t.Errorf("incorrect error: %v", err)
+			}
+		})
+	}
+
+	// item ids can be anything
+	data := append([]byte{}, buf.Bytes()...)
+	binary.LittleEndian.PutUint64(data[headerSize+32*numNodes+8*3:], 1000)
+
+	if _, err := Load(data); err != nil {
+		t.Errorf("should load with any item id: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package rtree_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/rtree"
+)
+
+func ExampleRTree_Search() {
+	parcels := []orb.Bounder{
+		orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		orb.Polygon{{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 0}}},
+		orb.Polygon{{{5, 5}, {6, 5}, {6, 6}, {5, 6}, {5, 5}}},
+	}
+
+	tree := rtree.New(parcels, rtree.Hilbert)
+
+	tree.Search(orb.Bound{Min: orb.Point{0.5, 0.5}, Max: orb.Point{1.5, 0.6}}, func(i uint64) bool {
+		fmt.Printf("found parcel %d\n", i)
+		return true
+	})
+
+	// Unordered output:
+	// found parcel 0
+	// found parcel 1
+}
+
+func ExampleRTree_Nearest() {
+	points := []orb.Bounder{
+		orb.Point{0, 0},
+		orb.Point{1, 1},
+		orb.Point{2, 2},
+		orb.Point{3, 3},
+	}
+
+	tree := rtree.New(points, rtree.STR)
+
+	nearest := tree.Nearest(nil, orb.Point{2.2, 2.1}, 2)
+	fmt.Println(nearest)
+
+	// Output:
+	// [2 3]
+}
+
+func ExampleLoad() {
+	bounds := []orb.Bound{
+		{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}},
+		{Min: orb.Point{5, 5}, Max: orb.Point{6, 6}},
+	}
+
+	// e.g. the database ids of the rows
+	ids := []uint64{1001, 1002}
+
+	tree := rtree.NewWithIDs(bounds, ids, rtree.Hilbert, rtree.DefaultNodeSize)
+
+	// write to a file, or in this case a buffer
+	buf := &bytes.Buffer{}
+	if _, err := tree.WriteTo(buf); err != nil {
+		panic(err)
+	}
+
+	// the data can be a memory-mapped file
+	loaded, err := rtree.Load(buf.Bytes())
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(loaded.Nearest(nil, orb.Point{4, 4}, 1))
+
+	// Output:
+	// [1002]
+}
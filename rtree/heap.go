@@ -0,0 +1,64 @@
+package rtree
+
+// minHeap is the priority queue of nodes and items for the nearest search.
+// The closest is always at the top.
+type minHeap []queueItem
+
+type queueItem struct {
+	node     int
+	distance float64
+	item     bool
+}
+
+func (h *minHeap) Push(item queueItem) {
+	*h = append(*h, item)
+
+	mh := *h
+	i := len(mh) - 1
+	for i > 0 {
+		up := (i - 1) / 2
+		if mh[up].distance <= item.distance {
+			break
+		}
+
+		mh[i] = mh[up]
+		i = up
+	}
+	mh[i] = item
+}
+
+// Pop removes and returns the closest item.
+func (h *minHeap) Pop() queueItem {
+	mh := *h
+	top := mh[0]
+
+	last := mh[len(mh)-1]
+	mh = mh[:len(mh)-1]
+	*h = mh
+
+	if len(mh) == 0 {
+		return top
+	}
+
+	i := 0
+	for {
+		child := 2*i + 1
+		if child >= len(mh) {
+			break
+		}
+
+		if right := child + 1; right < len(mh) && mh[right].distance < mh[child].distance {
+			child = right
+		}
+
+		if last.distance <= mh[child].distance {
+			break
+		}
+
+		mh[i] = mh[child]
+		i = child
+	}
+	mh[i] = last
+
+	return top
+}
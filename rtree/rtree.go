@@ -0,0 +1,412 @@
+// Package rtree implements a static, packed R-tree. All the items are
+// loaded at once, sorted using Hilbert or Sort-Tile-Recursive packing and
+// stored in flat arrays. This makes the tree compact and fast to search
+// but items can not be added or removed after it is created.
+// The design is based on flatbush: https://github.com/mourner/flatbush
+package rtree
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// DefaultNodeSize is the number of children of each node.
+const DefaultNodeSize = 16
+
+// maxNodeSize is the largest number of children of each node.
+const maxNodeSize = 1<<16 - 1
+
+// Packing is the method used to order the items when loading the tree.
+type Packing int
+
+const (
+	// Hilbert sorts the items by the Hilbert curve value of their centers.
+	Hilbert Packing = iota
+
+	// STR sorts the items using Sort-Tile-Recursive, into vertical
+	// slices by the x of their centers and then by y within the slices.
+	STR
+)
+
+// RTree is a static spatial index of bounds. The items are referenced by
+// their index in the slice used to create the tree, or by the IDs given
+// to NewWithIDs.
+type RTree struct {
+	nodeSize int
+	numItems int
+
+	// boxes are the bounds of the nodes as minX, minY, maxX, maxY. The first
+	// numItems are the leaves followed by each level up to the root.
+	boxes []float64
+
+	// indices are the item id for leaves and the first child for nodes.
+	indices []uint64
+
+	// levelBounds are the end of each level in nodes.
+	levelBounds []int
+}
+
+// New creates a tree of the bounds of the items using the packing method.
+// The ids passed to Search and returned by Nearest are the position of
+// the item in this slice.
+func New(items []orb.Bounder, packing Packing) *RTree {
+	bounds := make([]orb.Bound, len(items))
+	for i, item := range items {
+		bounds[i] = item.Bound()
+	}
+
+	return NewFromBounds(bounds, packing, DefaultNodeSize)
+}
+
+// NewFromBounds creates a tree from the bounds with the given number of
+// children per node, from 2 to 65535.
+func NewFromBounds(bounds []orb.Bound, packing Packing, nodeSize int) *RTree {
+	return build(bounds, nil, packing, nodeSize)
+}
+
+// NewWithIDs creates a tree from the bounds where each item is referenced
+// by its ID, e.g. a database key, instead of its index. This is useful if
+// the tree is saved and loaded separately from the items. The IDs are passed
+// to Search and returned by Nearest. Will panic if the number of bounds
+// and ids are not the same.
+func NewWithIDs(bounds []orb.Bound, ids []uint64, packing Packing, nodeSize int) *RTree {
+	if len(bounds) != len(ids) {
+		panic("rtree: bounds and ids must have the same length")
+	}
+
+	return build(bounds, ids, packing, nodeSize)
+}
+
+func build(bounds []orb.Bound, ids []uint64, packing Packing, nodeSize int) *RTree {
+	if nodeSize < 2 {
+		nodeSize = 2
+	} else if nodeSize > maxNodeSize {
+		nodeSize = maxNodeSize
+	}
+
+	t := &RTree{
+		nodeSize: nodeSize,
+		numItems: len(bounds),
+	}
+
+	numNodes := t.computeLevels()
+	t.boxes = make([]float64, 4*numNodes)
+	t.indices = make([]uint64, numNodes)
+	if len(bounds) == 0 {
+		return t
+	}
+
+	order := make([]uint32, len(bounds))
+	for i := range order {
+		order[i] = uint32(i)
+	}
+
+	switch packing {
+	case STR:
+		sortSTR(order, bounds, nodeSize)
+	default:
+		sortHilbert(order, bounds)
+	}
+
+	for i, o := range order {
+		b := bounds[o]
+		t.setBox(i, b.Min[0], b.Min[1], b.Max[0], b.Max[1])
+
+		t.indices[i] = uint64(o)
+		if ids != nil {
+			t.indices[i] = ids[o]
+		}
+	}
+
+	// build the parent nodes from the level below, each level starts
+	// where the previous one ends.
+	pos := 0
+	for _, end := range t.levelBounds[:len(t.levelBounds)-1] {
+		for parent := end; pos < end; parent++ {
+			first := pos
+
+			minX, minY := math.Inf(1), math.Inf(1)
+			maxX, maxY := math.Inf(-1), math.Inf(-1)
+			for j := 0; j < nodeSize && pos < end; j++ {
+				minX = math.Min(minX, t.boxes[4*pos])
+				minY = math.Min(minY, t.boxes[4*pos+1])
+				maxX = math.Max(maxX, t.boxes[4*pos+2])
+				maxY = math.Max(maxY, t.boxes[4*pos+3])
+				pos++
+			}
+
+			t.setBox(parent, minX, minY, maxX, maxY)
+			t.indices[parent] = uint64(first)
+		}
+	}
+
+	return t
+}
+
+// computeLevels sets the end of each level and returns the total number of nodes.
+func (t *RTree) computeLevels() int {
+	n := t.numItems
+	numNodes := n
+	t.levelBounds = []int{n}
+	for n > 1 {
+		n = (n + t.nodeSize - 1) / t.nodeSize
+		numNodes += n
+		t.levelBounds = append(t.levelBounds, numNodes)
+	}
+
+	return numNodes
+}
+
+func (t *RTree) setBox(i int, minX, minY, maxX, maxY float64) {
+	t.boxes[4*i] = minX
+	t.boxes[4*i+1] = minY
+	t.boxes[4*i+2] = maxX
+	t.boxes[4*i+3] = maxY
+}
+
+// Len returns the number of items in the tree.
+func (t *RTree) Len() int {
+	return t.numItems
+}
+
+// Bound returns the bound of all the items in the tree.
+func (t *RTree) Bound() orb.Bound {
+	if t.numItems == 0 {
+		return orb.Bound{}
+	}
+
+	return t.box(len(t.indices) - 1)
+}
+
+func (t *RTree) box(i int) orb.Bound {
+	return orb.Bound{
+		Min: orb.Point{t.boxes[4*i], t.boxes[4*i+1]},
+		Max: orb.Point{t.boxes[4*i+2], t.boxes[4*i+3]},
+	}
+}
+
+// Search calls the function with the index, or ID, of every item whose bound
+// intersects the given bound. The search stops if the function returns false.
+// This function is thread safe. Multiple goroutines can read from the tree.
+func (t *RTree) Search(b orb.Bound, fn func(id uint64) bool) {
+	if t.numItems == 0 {
+		return
+	}
+
+	var stackArray [64]int
+	stack := append(stackArray[:0], len(t.indices)-1)
+
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !t.intersects(node, b) {
+			continue
+		}
+
+		if node < t.numItems {
+			if !fn(t.indices[node]) {
+				return
+			}
+			continue
+		}
+
+		first := int(t.indices[node])
+		end := t.childEnd(first)
+		for i := end - 1; i >= first; i-- {
+			stack = append(stack, i)
+		}
+	}
+}
+
+// childEnd returns the end of the children starting at first,
+// limited by the end of their level.
+func (t *RTree) childEnd(first int) int {
+	end := first + t.nodeSize
+	for _, l := range t.levelBounds {
+		if first < l {
+			if end > l {
+				end = l
+			}
+			break
+		}
+	}
+
+	return end
+}
+
+func (t *RTree) intersects(i int, b orb.Bound) bool {
+	return t.boxes[4*i] <= b.Max[0] && t.boxes[4*i+1] <= b.Max[1] &&
+		t.boxes[4*i+2] >= b.Min[0] && t.boxes[4*i+3] >= b.Min[1]
+}
+
+// Nearest returns the indexes, or IDs, of the k items whose bounds are closest to
+// the point, nearest first. The distance is zero if the point is within the
+// bound. An optional buffer parameter is provided to allow for the reuse of
+// result slice memory. This function allows defining a maximum distance in
+// order to reduce search iterations. This function is thread safe.
+// Multiple goroutines can read from the tree.
+func (t *RTree) Nearest(buf []uint64, p orb.Point, k int, maxDistance ...float64) []uint64 {
+	result := buf[:0]
+	if t.numItems == 0 || k <= 0 {
+		return result
+	}
+
+	maxDistSquared := math.Inf(1)
+	if len(maxDistance) > 0 {
+		maxDistSquared = maxDistance[0] * maxDistance[0]
+	}
+
+	root := len(t.indices) - 1
+	q := minHeap{{node: root, distance: t.distanceSquared(root, p), item: root < t.numItems}}
+	for len(q) > 0 {
+		item := q.Pop()
+		if item.distance > maxDistSquared {
+			break
+		}
+
+		if item.item {
+			result = append(result, t.indices[item.node])
+			if len(result) == k {
+				break
+			}
+			continue
+		}
+
+		// items are queued with their distance and returned when they
+		// come out of the queue since everything after is further.
+		first := int(t.indices[item.node])
+		end := t.childEnd(first)
+		for i := first; i < end; i++ {
+			d := t.distanceSquared(i, p)
+			if d <= maxDistSquared {
+				q.Push(queueItem{node: i, distance: d, item: i < t.numItems})
+			}
+		}
+	}
+
+	return result
+}
+
+// distanceSquared returns the squared distance from the point to the node bound.
+func (t *RTree) distanceSquared(i int, p orb.Point) float64 {
+	dx := math.Max(0, math.Max(t.boxes[4*i]-p[0], p[0]-t.boxes[4*i+2]))
+	dy := math.Max(0, math.Max(t.boxes[4*i+1]-p[1], p[1]-t.boxes[4*i+3]))
+
+	return dx*dx + dy*dy
+}
+
+// sortHilbert orders the items by the Hilbert value of their centers
+// within the bound of all the items.
+func sortHilbert(order []uint32, bounds []orb.Bound) {
+	total := bounds[0]
+	for _, b := range bounds[1:] {
+		total = total.Union(b)
+	}
+
+	const hilbertMax = (1 << 16) - 1
+	w := total.Max[0] - total.Min[0]
+	h := total.Max[1] - total.Min[1]
+
+	values := make([]uint32, len(bounds))
+	for i, b := range bounds {
+		var x, y uint32
+		if w > 0 {
+			x = uint32(hilbertMax * ((b.Min[0]+b.Max[0])/2 - total.Min[0]) / w)
+		}
+		if h > 0 {
+			y = uint32(hilbertMax * ((b.Min[1]+b.Max[1])/2 - total.Min[1]) / h)
+		}
+		values[i] = hilbert(x, y)
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		return values[order[i]] < values[order[j]]
+	})
+}
+
+// sortSTR orders the items into vertical slices by x and then by y within
+// each slice so that consecutive runs of nodeSize items are tiles.
+func sortSTR(order []uint32, bounds []orb.Bound, nodeSize int) {
+	cx := func(i uint32) float64 { return bounds[i].Min[0] + bounds[i].Max[0] }
+	cy := func(i uint32) float64 { return bounds[i].Min[1] + bounds[i].Max[1] }
+
+	sort.Slice(order, func(i, j int) bool {
+		return cx(order[i]) < cx(order[j])
+	})
+
+	leaves := (len(order) + nodeSize - 1) / nodeSize
+	slices := int(math.Ceil(math.Sqrt(float64(leaves))))
+	size := slices * nodeSize
+
+	for start := 0; start < len(order); start += size {
+		end := start + size
+		if end > len(order) {
+			end = len(order)
+		}
+
+		s := order[start:end]
+		sort.Slice(s, func(i, j int) bool {
+			return cy(s[i]) < cy(s[j])
+		})
+	}
+}
+
+// hilbert returns the position along the Hilbert curve of the 16 bit
+// coordinates. From https://github.com/rawrunprotected/hilbert_curves
+func hilbert(x, y uint32) uint32 {
+	a := x ^ y
+	b := 0xFFFF ^ a
+	c := 0xFFFF ^ (x | y)
+	d := x & (y ^ 0xFFFF)
+
+	A := a | (b >> 1)
+	B := (a >> 1) ^ a
+	C := ((c >> 1) ^ (b & (d >> 1))) ^ c
+	D := ((a & (c >> 1)) ^ (d >> 1)) ^ d
+
+	a = A
+	b = B
+	c = C
+	d = D
+	A = (a & (a >> 2)) ^ (b & (b >> 2))
+	B = (a & (b >> 2)) ^ (b & ((a ^ b) >> 2))
+	C ^= (a & (c >> 2)) ^ (b & (d >> 2))
+	D ^= (b & (c >> 2)) ^ ((a ^ b) & (d >> 2))
+
+	a = A
+	b = B
+	c = C
+	d = D
+	A = (a & (a >> 4)) ^ (b & (b >> 4))
+	B = (a & (b >> 4)) ^ (b & ((a ^ b) >> 4))
+	C ^= (a & (c >> 4)) ^ (b & (d >> 4))
+	D ^= (b & (c >> 4)) ^ ((a ^ b) & (d >> 4))
+
+	a = A
+	b = B
+	c = C
+	d = D
+	C ^= (a & (c >> 8)) ^ (b & (d >> 8))
+	D ^= (b & (c >> 8)) ^ ((a ^ b) & (d >> 8))
+
+	a = C ^ (C >> 1)
+	b = D ^ (D >> 1)
+
+	i0 := x ^ y
+	i1 := b | (0xFFFF ^ (i0 | a))
+
+	i0 = (i0 | (i0 << 8)) & 0x00FF00FF
+	i0 = (i0 | (i0 << 4)) & 0x0F0F0F0F
+	i0 = (i0 | (i0 << 2)) & 0x33333333
+	i0 = (i0 | (i0 << 1)) & 0x55555555
+
+	i1 = (i1 | (i1 << 8)) & 0x00FF00FF
+	i1 = (i1 | (i1 << 4)) & 0x0F0F0F0F
+	i1 = (i1 | (i1 << 2)) & 0x33333333
+	i1 = (i1 | (i1 << 1)) & 0x55555555
+
+	return (i1 << 1) | i0
+}
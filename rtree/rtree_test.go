@@ -0,0 +1,253 @@
+package rtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func randomBounds(r *rand.Rand, n int, size float64) []orb.Bound {
+	bounds := make([]orb.Bound, 0, n)
+	for i := 0; i < n; i++ {
+		min := orb.Point{r.Float64(), r.Float64()}
+		bounds = append(bounds, orb.Bound{
+			Min: min,
+			Max: orb.Point{min[0] + r.Float64()*size, min[1] + r.Float64()*size},
+		})
+	}
+
+	return bounds
+}
+
+func distanceSquared(p orb.Point, b orb.Bound) float64 {
+	dx, dy := 0.0, 0.0
+	if p[0] < b.Min[0] {
+		dx = b.Min[0] - p[0]
+	} else if p[0] > b.Max[0] {
+		dx = p[0] - b.Max[0]
+	}
+
+	if p[1] < b.Min[1] {
+		dy = b.Min[1] - p[1]
+	} else if p[1] > b.Max[1] {
+		dy = p[1] - b.Max[1]
+	}
+
+	return dx*dx + dy*dy
+}
+
+func TestNew(t *testing.T) {
+	items := []orb.Bounder{
+		orb.Point{1, 1},
+		orb.LineString{{0, 0}, {2, 3}},
+		orb.Bound{Min: orb.Point{-1, 0}, Max: orb.Point{0, 1}},
+	}
+
+	tree := New(items, Hilbert)
+	if l := tree.Len(); l != 3 {
+		t.Errorf("incorrect length: %v", l)
+	}
+
+	expected := orb.Bound{Min: orb.Point{-1, 0}, Max: orb.Point{2, 3}}
+	if b := tree.Bound(); !b.Equal(expected) {
+		t.Errorf("incorrect bound: %v", b)
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		tree := New(nil, STR)
+		if l := tree.Len(); l != 0 {
+			t.Errorf("incorrect length: %v", l)
+		}
+
+		tree.Search(orb.Bound{Max: orb.Point{1, 1}}, func(uint64) bool {
+			t.Errorf("should not find anything")
+			return true
+		})
+
+		if r := tree.Nearest(nil, orb.Point{}, 5); len(r) != 0 {
+			t.Errorf("should not find anything: %v", r)
+		}
+	})
+
+	t.Run("one item", func(t *testing.T) {
+		tree := New([]orb.Bounder{orb.Point{1, 1}}, STR)
+
+		found := 0
+		tree.Search(orb.Bound{Max: orb.Point{1, 1}}, func(uint64) bool {
+			found++
+			return true
+		})
+
+		if found != 1 {
+			t.Errorf("should find the item: %v", found)
+		}
+
+		if r := tree.Nearest(nil, orb.Point{}, 5); len(r) != 1 || r[0] != 0 {
+			t.Errorf("incorrect nearest: %v", r)
+		}
+
+		if r := tree.Nearest(nil, orb.Point{}, 5, 1); len(r) != 0 {
+			t.Errorf("should be too far: %v", r)
+		}
+	})
+}
+
+func TestRTreeSearch(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for _, packing := range []Packing{Hilbert, STR} {
+		for _, n := range []int{1, 15, 16, 17, 256, 257, 1000} {
+			bounds := randomBounds(r, n, 0.1)
+			tree := NewFromBounds(bounds, packing, DefaultNodeSize)
+
+			for i := 0; i < 100; i++ {
+				p := orb.Point{r.Float64(), r.Float64()}
+				query := orb.Bound{Min: p, Max: p}.Pad(0.1)
+
+				var result []int
+				tree.Search(query, func(i uint64) bool {
+					result = append(result, int(i))
+					return true
+				})
+				sort.Ints(result)
+
+				var expected []int
+				for j, b := range bounds {
+					if b.Intersects(query) {
+						expected = append(expected, j)
+					}
+				}
+
+				if len(result) != len(expected) {
+					t.Fatalf("packing %d, n %d: lengths not equal %v != %v", packing, n, len(result), len(expected))
+				}
+
+				for j := range result {
+					if result[j] != expected[j] {
+						t.Fatalf("packing %d, n %d: incorrect result %v != %v", packing, n, result, expected)
+					}
+				}
+			}
+		}
+	}
+
+	t.Run("stop early", func(t *testing.T) {
+		tree := NewFromBounds(randomBounds(r, 1000, 0.1), Hilbert, 4)
+
+		count := 0
+		tree.Search(tree.Bound(), func(uint64) bool {
+			count++
+			return count < 10
+		})
+
+		if count != 10 {
+			t.Errorf("should stop: %v", count)
+		}
+	})
+}
+
+func TestRTreeNearest(t *testing.T) {
+	r := rand.New(rand.NewSource(43))
+
+	for _, packing := range []Packing{Hilbert, STR} {
+		bounds := randomBounds(r, 1000, 0.02)
+		tree := NewFromBounds(bounds, packing, 8)
+
+		var buf []uint64
+		for i := 0; i < 200; i++ {
+			p := orb.Point{r.Float64(), r.Float64()}
+			buf = tree.Nearest(buf, p, 10)
+
+			if len(buf) != 10 {
+				t.Fatalf("incorrect number of results: %v", len(buf))
+			}
+
+			ds := make([]float64, len(bounds))
+			for j, b := range bounds {
+				ds[j] = distanceSquared(p, b)
+			}
+			sort.Float64s(ds)
+
+			for j, index := range buf {
+				if d := distanceSquared(p, bounds[index]); d != ds[j] {
+					t.Errorf("packing %d: result %d incorrect distance: %v != %v", packing, j, d, ds[j])
+				}
+			}
+		}
+
+		// max distance
+		p := orb.Point{0.5, 0.5}
+		result := tree.Nearest(nil, p, 1000, 0.1)
+		for _, index := range result {
+			if d := distanceSquared(p, bounds[index]); d > 0.01 {
+				t.Errorf("result too far: %v", d)
+			}
+		}
+
+		count := 0
+		for _, b := range bounds {
+			if distanceSquared(p, b) <= 0.01 {
+				count++
+			}
+		}
+
+		if len(result) != count {
+			t.Errorf("incorrect number within max distance: %v != %v", len(result), count)
+		}
+	}
+}
+
+func TestNewWithIDs(t *testing.T) {
+	bounds := []orb.Bound{
+		{Min: orb.Point{0, 0}, Max: orb.Point{1, 1}},
+		{Min: orb.Point{5, 5}, Max: orb.Point{6, 6}},
+	}
+
+	// ids above the max int64 should be returned as is
+	ids := []uint64{math.MaxUint64, 1 << 63}
+	tree := NewWithIDs(bounds, ids, Hilbert, DefaultNodeSize)
+
+	var found []uint64
+	tree.Search(orb.Bound{Max: orb.Point{10, 10}}, func(id uint64) bool {
+		found = append(found, id)
+		return true
+	})
+
+	sort.Slice(found, func(i, j int) bool { return found[i] < found[j] })
+	if len(found) != 2 || found[0] != 1<<63 || found[1] != math.MaxUint64 {
+		t.Errorf("incorrect ids: %v", found)
+	}
+
+	if r := tree.Nearest(nil, orb.Point{4, 4}, 1); len(r) != 1 || r[0] != 1<<63 {
+		t.Errorf("incorrect nearest: %v", r)
+	}
+}
+
+func TestHilbert(t *testing.T) {
+	// the 4x4 corner is the start of the curve and each step is to a neighbor
+	cells := make([][2]uint32, 16)
+	for x := uint32(0); x < 4; x++ {
+		for y := uint32(0); y < 4; y++ {
+			v := hilbert(x, y)
+			if v >= 16 {
+				t.Fatalf("incorrect value for (%d, %d): %v", x, y, v)
+			}
+			cells[v] = [2]uint32{x + 1, y + 1}
+		}
+	}
+
+	for i := 1; i < len(cells); i++ {
+		a, b := cells[i-1], cells[i]
+		if a[0] == 0 || b[0] == 0 {
+			t.Fatalf("values should be unique: %v", cells)
+		}
+
+		dx, dy := int(a[0])-int(b[0]), int(a[1])-int(b[1])
+		if dx*dx+dy*dy != 1 {
+			t.Errorf("step %d is not to a neighbor: %v -> %v", i, a, b)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package validate_test
+
+import (
+	"fmt"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/validate"
+)
+
+func ExampleIsValid() {
+	bowtie := orb.Ring{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}}
+
+	valid, reasons := validate.IsValid(bowtie)
+	fmt.Println(valid)
+	fmt.Println(reasons)
+	// Output:
+	// false
+	// [self-intersection at [1 1]]
+}
+
+func ExampleMakeValid() {
+	bowtie := orb.Ring{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}}
+
+	fixed := validate.MakeValid(bowtie)
+	fmt.Println(fixed.GeoJSONType())
+	fmt.Println(len(fixed.(orb.MultiPolygon)))
+	// Output:
+	// MultiPolygon
+	// 2
+}
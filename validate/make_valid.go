@@ -0,0 +1,256 @@
+package validate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/segment"
+	"github.com/paulmach/orb/planar"
+)
+
+// MakeValid returns a valid version of the geometry. Invalid coordinates and
+// repeated points are removed, rings are closed, self-intersecting rings are
+// split into simple rings using the even-odd rule, holes are clipped to the
+// outer ring and polygons that overlap or share an edge are merged. Parts that
+// collapse are removed and nil is returned if nothing is left. The input is
+// not modified.
+//
+// Lines that collapse to a single point become a Point. 2d results are rebuilt
+// with outer rings in counter-clockwise order and holes in clockwise order,
+// and may become a MultiPolygon if the input was split into separate parts.
+func MakeValid(g orb.Geometry) orb.Geometry {
+	if g == nil {
+		return nil
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		if !validPoint(g) {
+			return nil
+		}
+
+		return g
+	case orb.MultiPoint:
+		var result orb.MultiPoint
+		for _, p := range g {
+			if validPoint(p) {
+				result = append(result, p)
+			}
+		}
+
+		if len(result) == 0 {
+			return nil
+		}
+
+		return result
+	case orb.LineString:
+		ls := cleanLine(g)
+		switch len(ls) {
+		case 0:
+			return nil
+		case 1:
+			return ls[0]
+		}
+
+		return ls
+	case orb.MultiLineString:
+		var result orb.MultiLineString
+		for _, ls := range g {
+			if ls = cleanLine(ls); len(ls) > 1 {
+				result = append(result, ls)
+			}
+		}
+
+		if len(result) == 0 {
+			return nil
+		}
+
+		return result
+	case orb.Ring:
+		result := makeValidRing(g)
+		if len(result) == 1 && len(result[0]) == 1 {
+			return result[0][0]
+		}
+
+		return polygonResult(result)
+	case orb.Polygon:
+		return polygonResult(makeValidPolygon(g))
+	case orb.MultiPolygon:
+		// the parts are merged in a single overlay
+		var parts orb.MultiPolygon
+		for _, p := range g {
+			parts = append(parts, makeValidPolygon(p)...)
+		}
+
+		result := planar.Union(parts, nil)
+		if len(result) == 0 {
+			return nil
+		}
+
+		return result
+	case orb.Collection:
+		var result orb.Collection
+		for _, c := range g {
+			if v := MakeValid(c); v != nil {
+				result = append(result, v)
+			}
+		}
+
+		if len(result) == 0 {
+			return nil
+		}
+
+		return result
+	case orb.Bound:
+		if !validPoint(g.Min) || !validPoint(g.Max) {
+			return nil
+		}
+
+		return orb.MultiPoint{g.Min, g.Max}.Bound()
+	}
+
+	panic(fmt.Sprintf("geometry type not supported: %T", g))
+}
+
+func polygonResult(mp orb.MultiPolygon) orb.Geometry {
+	if len(mp) == 1 {
+		return mp[0]
+	}
+
+	if len(mp) == 0 {
+		return nil
+	}
+
+	return mp
+}
+
+// makeValidPolygon returns the area of the outer ring minus the area of the holes.
+func makeValidPolygon(p orb.Polygon) orb.MultiPolygon {
+	if len(p) == 0 {
+		return nil
+	}
+
+	shell := makeValidRing(p[0])
+	if len(shell) == 0 {
+		return nil
+	}
+
+	var holes orb.MultiPolygon
+	for _, r := range p[1:] {
+		holes = append(holes, makeValidRing(r)...)
+	}
+
+	if len(holes) == 0 {
+		return shell
+	}
+
+	return planar.Difference(shell, holes)
+}
+
+// makeValidRing splits the ring into simple loops where it touches or crosses
+// itself. The result is the area inside an odd number of loops.
+func makeValidRing(r orb.Ring) orb.MultiPolygon {
+	ls := cleanLine(orb.LineString(r))
+	if len(ls) > 1 && ls[0] != ls[len(ls)-1] {
+		ls = append(ls, ls[0])
+	}
+
+	if len(ls) < 4 {
+		return nil
+	}
+
+	var result orb.MultiPolygon
+	for _, loop := range splitLoops(nodeRing(ls)) {
+		result = planar.SymDifference(result, loop)
+	}
+
+	return result
+}
+
+// cleanLine returns a copy of the line without invalid or repeated points.
+func cleanLine(ls orb.LineString) orb.LineString {
+	result := make(orb.LineString, 0, len(ls))
+	for _, p := range ls {
+		if !validPoint(p) {
+			continue
+		}
+
+		if len(result) == 0 || result[len(result)-1] != p {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+// nodeRing adds a vertex at every place the closed line touches or crosses itself.
+func nodeRing(ls orb.LineString) orb.LineString {
+	n := len(ls) - 1
+	bounds := make([]orb.Bound, n)
+	for i := range bounds {
+		bounds[i] = orb.MultiPoint{ls[i], ls[i+1]}.Bound()
+	}
+
+	splits := make([][]orb.Point, n)
+	segment.Sweep(bounds, func(i, j int) {
+		p1, p2, c := segment.Intersection(ls[i], ls[i+1], ls[j], ls[j+1])
+		found := [2]orb.Point{p1, p2}
+		for _, p := range found[:c] {
+			splits[i] = append(splits[i], p)
+			splits[j] = append(splits[j], p)
+		}
+	})
+
+	result := orb.LineString{ls[0]}
+	for i := 0; i < n; i++ {
+		a, b := ls[i], ls[i+1]
+		dx, dy := b[0]-a[0], b[1]-a[1]
+
+		points := splits[i]
+		sort.Slice(points, func(i, j int) bool {
+			ti := (points[i][0]-a[0])*dx + (points[i][1]-a[1])*dy
+			tj := (points[j][0]-a[0])*dx + (points[j][1]-a[1])*dy
+			return ti < tj
+		})
+
+		for _, p := range append(points, b) {
+			if result[len(result)-1] != p {
+				result = append(result, p)
+			}
+		}
+	}
+
+	return result
+}
+
+// splitLoops splits the closed line into rings at the repeated vertices.
+// The returned rings do not repeat any vertex, besides the closing one.
+func splitLoops(ls orb.LineString) []orb.Ring {
+	var (
+		result []orb.Ring
+		stack  []orb.Point
+	)
+
+	seen := make(map[orb.Point]int, len(ls))
+	for _, p := range ls {
+		i, ok := seen[p]
+		if !ok {
+			seen[p] = len(stack)
+			stack = append(stack, p)
+			continue
+		}
+
+		loop := append(orb.Ring{}, stack[i:]...)
+		if len(loop) >= 3 {
+			result = append(result, append(loop, p))
+		}
+
+		for _, q := range stack[i+1:] {
+			delete(seen, q)
+		}
+		stack = stack[:i+1]
+	}
+
+	return result
+}
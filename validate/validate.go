@@ -0,0 +1,320 @@
+// Package validate checks geometries against the OGC simple feature rules
+// and can repair the common problems.
+package validate
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/internal/segment"
+	"github.com/paulmach/orb/planar"
+)
+
+// A Defect is a problem that makes a geometry invalid.
+type Defect int
+
+// Possible defects found by IsValid.
+const (
+	// InvalidCoordinate is a NaN or infinite coordinate.
+	InvalidCoordinate Defect = iota + 1
+
+	// TooFewPoints is a line string with fewer than 2 unique points
+	// or a ring with fewer than 3.
+	TooFewPoints
+
+	// RepeatedPoint is a point that is the same as the one before it.
+	RepeatedPoint
+
+	// RingNotClosed is a ring where the first and last points are different.
+	RingNotClosed
+
+	// SelfIntersection is a ring that crosses or touches itself.
+	SelfIntersection
+
+	// HoleOutsideShell is a hole that is not completely inside the outer ring.
+	HoleOutsideShell
+
+	// OverlappingHoles are holes of the same polygon that overlap or are nested.
+	OverlappingHoles
+
+	// OverlappingPolygons are polygons of a multi polygon whose interiors overlap.
+	OverlappingPolygons
+
+	// SharedEdge are polygons of a multi polygon whose boundaries share
+	// part of an edge. They may only touch at points.
+	SharedEdge
+)
+
+var defectNames = map[Defect]string{
+	InvalidCoordinate:   "invalid coordinate",
+	TooFewPoints:        "too few points",
+	RepeatedPoint:       "repeated point",
+	RingNotClosed:       "ring not closed",
+	SelfIntersection:    "self-intersection",
+	HoleOutsideShell:    "hole outside shell",
+	OverlappingHoles:    "overlapping holes",
+	OverlappingPolygons: "overlapping polygons",
+	SharedEdge:          "shared edge",
+}
+
+// String returns a description of the defect.
+func (d Defect) String() string {
+	if n, ok := defectNames[d]; ok {
+		return n
+	}
+
+	return fmt.Sprintf("defect(%d)", int(d))
+}
+
+// A Reason describes a defect and where it was found.
+type Reason struct {
+	Defect   Defect
+	Location orb.Point
+}
+
+// String returns a description of the reason.
+func (r Reason) String() string {
+	return fmt.Sprintf("%v at %v", r.Defect, r.Location)
+}
+
+// IsValid checks if the geometry is valid. If not, the reasons
+// list the defects found and their locations.
+func IsValid(g orb.Geometry) (bool, []Reason) {
+	v := &validator{}
+	v.geometry(g)
+
+	return len(v.reasons) == 0, v.reasons
+}
+
+type validator struct {
+	reasons []Reason
+}
+
+func (v *validator) add(d Defect, p orb.Point) {
+	v.reasons = append(v.reasons, Reason{Defect: d, Location: p})
+}
+
+func (v *validator) geometry(g orb.Geometry) {
+	if g == nil {
+		return
+	}
+
+	switch g := g.(type) {
+	case orb.Point:
+		v.coordinates(orb.MultiPoint{g})
+	case orb.MultiPoint:
+		v.coordinates(g)
+	case orb.LineString:
+		v.lineString(g)
+	case orb.MultiLineString:
+		for _, ls := range g {
+			v.lineString(ls)
+		}
+	case orb.Ring:
+		v.ring(g)
+	case orb.Polygon:
+		v.polygon(g)
+	case orb.MultiPolygon:
+		v.multiPolygon(g)
+	case orb.Collection:
+		for _, c := range g {
+			v.geometry(c)
+		}
+	case orb.Bound:
+		v.coordinates(orb.MultiPoint{g.Min, g.Max})
+	default:
+		panic(fmt.Sprintf("geometry type not supported: %T", g))
+	}
+}
+
+// coordinates checks for invalid coordinates and returns true if all are okay.
+func (v *validator) coordinates(mp orb.MultiPoint) bool {
+	valid := true
+	for _, p := range mp {
+		if !validPoint(p) {
+			v.add(InvalidCoordinate, p)
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+// repeated checks for repeated points and returns the number of unique ones.
+func (v *validator) repeated(mp orb.MultiPoint) int {
+	if len(mp) == 0 {
+		return 0
+	}
+
+	count := 1
+	for i := 1; i < len(mp); i++ {
+		if mp[i] == mp[i-1] {
+			v.add(RepeatedPoint, mp[i])
+			continue
+		}
+		count++
+	}
+
+	return count
+}
+
+func (v *validator) lineString(ls orb.LineString) {
+	if !v.coordinates(orb.MultiPoint(ls)) {
+		return
+	}
+
+	if v.repeated(orb.MultiPoint(ls)) < 2 {
+		v.add(TooFewPoints, firstPoint(orb.MultiPoint(ls)))
+	}
+}
+
+// ring checks the ring and returns true if it's good enough
+// to be used in the polygon level checks.
+func (v *validator) ring(r orb.Ring) bool {
+	if !v.coordinates(orb.MultiPoint(r)) {
+		return false
+	}
+
+	valid := true
+	if len(r) > 0 && r[0] != r[len(r)-1] {
+		v.add(RingNotClosed, r[0])
+		valid = false
+	}
+
+	unique := v.repeated(orb.MultiPoint(r))
+	if valid {
+		unique-- // last point matches the first
+	}
+
+	if unique < 3 {
+		v.add(TooFewPoints, firstPoint(orb.MultiPoint(r)))
+		return false
+	}
+
+	for _, p := range planar.SelfIntersections(orb.LineString(r)) {
+		v.add(SelfIntersection, p)
+		valid = false
+	}
+
+	return valid
+}
+
+// polygon checks the polygon and returns true if it's good enough
+// to be used in the multi polygon checks.
+func (v *validator) polygon(p orb.Polygon) bool {
+	if len(p) == 0 {
+		return true
+	}
+
+	valid := true
+	ringsValid := make([]bool, len(p))
+	for i, r := range p {
+		ringsValid[i] = v.ring(r)
+		valid = valid && ringsValid[i]
+	}
+
+	for i := 1; i < len(p); i++ {
+		if !ringsValid[0] || !ringsValid[i] {
+			continue
+		}
+
+		if outside := planar.Difference(p[i], p[0]); len(outside) > 0 {
+			v.add(HoleOutsideShell, outside[0][0][0])
+			valid = false
+		}
+	}
+
+	for i := 1; i < len(p); i++ {
+		for j := i + 1; j < len(p); j++ {
+			if !ringsValid[i] || !ringsValid[j] || !p[i].Bound().Intersects(p[j].Bound()) {
+				continue
+			}
+
+			if overlap := planar.Intersection(p[i], p[j]); len(overlap) > 0 {
+				v.add(OverlappingHoles, overlap[0][0][0])
+				valid = false
+			}
+		}
+	}
+
+	return valid
+}
+
+func (v *validator) multiPolygon(mp orb.MultiPolygon) {
+	valid := make([]bool, len(mp))
+	for i, p := range mp {
+		valid[i] = v.polygon(p)
+	}
+
+	for i := range mp {
+		for j := i + 1; j < len(mp); j++ {
+			if !valid[i] || !valid[j] || len(mp[i]) == 0 || len(mp[j]) == 0 {
+				continue
+			}
+
+			if !mp[i].Bound().Intersects(mp[j].Bound()) {
+				continue
+			}
+
+			if overlap := planar.Intersection(mp[i], mp[j]); len(overlap) > 0 {
+				v.add(OverlappingPolygons, overlap[0][0][0])
+			} else if p, ok := sharedEdge(mp[i], mp[j]); ok {
+				v.add(SharedEdge, p)
+			}
+		}
+	}
+}
+
+// sharedEdge returns the start of a part of an edge that is on
+// the boundary of both polygons.
+func sharedEdge(p1, p2 orb.Polygon) (orb.Point, bool) {
+	var edges [][2]orb.Point
+	for _, p := range []orb.Polygon{p1, p2} {
+		for _, r := range p {
+			for i := 0; i < len(r)-1; i++ {
+				edges = append(edges, [2]orb.Point{r[i], r[i+1]})
+			}
+		}
+	}
+
+	n1 := 0
+	for _, r := range p1 {
+		n1 += len(r) - 1
+	}
+
+	bounds := make([]orb.Bound, len(edges))
+	for i, e := range edges {
+		bounds[i] = orb.MultiPoint{e[0], e[1]}.Bound()
+	}
+
+	var (
+		found  bool
+		result orb.Point
+	)
+	segment.Sweep(bounds, func(i, j int) {
+		if found || i >= n1 || j < n1 {
+			return
+		}
+
+		p, _, n := segment.Intersection(edges[i][0], edges[i][1], edges[j][0], edges[j][1])
+		if n == 2 {
+			found, result = true, p
+		}
+	})
+
+	return result, found
+}
+
+func validPoint(p orb.Point) bool {
+	return !math.IsNaN(p[0]) && !math.IsNaN(p[1]) &&
+		!math.IsInf(p[0], 0) && !math.IsInf(p[1], 0)
+}
+
+func firstPoint(mp orb.MultiPoint) orb.Point {
+	if len(mp) == 0 {
+		return orb.Point{}
+	}
+
+	return mp[0]
+}
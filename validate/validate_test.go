@@ -0,0 +1,431 @@
+package validate
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestIsValid(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		IsValid(g)
+	}
+
+	cases := []struct {
+		name    string
+		input   orb.Geometry
+		defects []Defect
+	}{
+		{
+			name:  "point",
+			input: orb.Point{1, 2},
+		},
+		{
+			name:    "nan point",
+			input:   orb.Point{math.NaN(), 2},
+			defects: []Defect{InvalidCoordinate},
+		},
+		{
+			name:    "infinite multi point",
+			input:   orb.MultiPoint{{1, 2}, {math.Inf(1), 2}},
+			defects: []Defect{InvalidCoordinate},
+		},
+		{
+			name:  "line string",
+			input: orb.LineString{{0, 0}, {1, 1}, {0, 0}},
+		},
+		{
+			name:    "line string repeated point",
+			input:   orb.LineString{{0, 0}, {1, 1}, {1, 1}},
+			defects: []Defect{RepeatedPoint},
+		},
+		{
+			name:    "line string one point",
+			input:   orb.LineString{{0, 0}, {0, 0}},
+			defects: []Defect{RepeatedPoint, TooFewPoints},
+		},
+		{
+			name:  "ring",
+			input: orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+		},
+		{
+			name:    "ring not closed",
+			input:   orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+			defects: []Defect{RingNotClosed},
+		},
+		{
+			name:    "ring too few points",
+			input:   orb.Ring{{0, 0}, {1, 0}, {0, 0}},
+			defects: []Defect{TooFewPoints},
+		},
+		{
+			name:    "bowtie",
+			input:   orb.Ring{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}},
+			defects: []Defect{SelfIntersection},
+		},
+		{
+			name:    "touches itself",
+			input:   orb.Ring{{0, 0}, {4, 0}, {4, 4}, {2, 0}, {0, 4}, {0, 0}},
+			defects: []Defect{SelfIntersection},
+		},
+		{
+			name:    "spike",
+			input:   orb.Ring{{0, 0}, {4, 0}, {6, 0}, {4, 0}, {4, 4}, {0, 0}},
+			defects: []Defect{SelfIntersection},
+		},
+		{
+			name: "polygon with hole",
+			input: orb.Polygon{
+				{{0, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 0}},
+				{{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}},
+			},
+		},
+		{
+			name: "hole touching the shell",
+			input: orb.Polygon{
+				{{0, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 0}},
+				{{0, 0}, {2, 4}, {4, 2}, {0, 0}},
+			},
+		},
+		{
+			name: "hole outside shell",
+			input: orb.Polygon{
+				{{0, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 0}},
+				{{4, 4}, {4, 8}, {8, 8}, {8, 4}, {4, 4}},
+			},
+			defects: []Defect{HoleOutsideShell},
+		},
+		{
+			name: "overlapping holes",
+			input: orb.Polygon{
+				{{0, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 0}},
+				{{1, 1}, {1, 3}, {3, 3}, {3, 1}, {1, 1}},
+				{{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}},
+			},
+			defects: []Defect{OverlappingHoles},
+		},
+		{
+			name: "overlapping polygons",
+			input: orb.MultiPolygon{
+				{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+				{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}},
+			},
+			defects: []Defect{OverlappingPolygons},
+		},
+		{
+			name: "polygons sharing an edge",
+			input: orb.MultiPolygon{
+				{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+				{{{2, 1}, {4, 1}, {4, 3}, {2, 3}, {2, 1}}},
+			},
+			defects: []Defect{SharedEdge},
+		},
+		{
+			name: "polygons touching at a point",
+			input: orb.MultiPolygon{
+				{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+				{{{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}}},
+			},
+		},
+		{
+			name: "collection",
+			input: orb.Collection{
+				orb.Point{1, 2},
+				orb.Ring{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}},
+			},
+			defects: []Defect{SelfIntersection},
+		},
+		{
+			name:    "bound",
+			input:   orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{math.NaN(), 1}},
+			defects: []Defect{InvalidCoordinate},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			valid, reasons := IsValid(tc.input)
+			if valid != (len(tc.defects) == 0) {
+				t.Errorf("incorrect valid: %v", valid)
+			}
+
+			var defects []Defect
+			for _, r := range reasons {
+				defects = append(defects, r.Defect)
+			}
+
+			if !reflect.DeepEqual(defects, tc.defects) {
+				t.Errorf("incorrect defects: %v", reasons)
+				t.Log(defects)
+				t.Log(tc.defects)
+			}
+		})
+	}
+}
+
+func TestIsValid_location(t *testing.T) {
+	_, reasons := IsValid(orb.Ring{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}})
+	if len(reasons) != 1 {
+		t.Fatalf("incorrect number of reasons: %v", reasons)
+	}
+
+	if v := reasons[0].Location; !v.Equal(orb.Point{1, 1}) {
+		t.Errorf("incorrect location: %v", v)
+	}
+
+	if v := reasons[0].String(); v != "self-intersection at [1 1]" {
+		t.Errorf("incorrect string: %v", v)
+	}
+}
+
+func TestMakeValid(t *testing.T) {
+	for _, g := range orb.AllGeometries {
+		MakeValid(g)
+	}
+
+	cases := []struct {
+		name   string
+		input  orb.Geometry
+		output orb.Geometry
+	}{
+		{
+			name:   "nan point",
+			input:  orb.Point{math.NaN(), 2},
+			output: nil,
+		},
+		{
+			name:   "multi point",
+			input:  orb.MultiPoint{{1, 2}, {math.Inf(1), 2}},
+			output: orb.MultiPoint{{1, 2}},
+		},
+		{
+			name:   "line string",
+			input:  orb.LineString{{0, 0}, {1, 1}, {1, 1}, {2, 0}},
+			output: orb.LineString{{0, 0}, {1, 1}, {2, 0}},
+		},
+		{
+			name:   "line string collapses",
+			input:  orb.LineString{{1, 1}, {1, 1}},
+			output: orb.Point{1, 1},
+		},
+		{
+			name:   "multi line string",
+			input:  orb.MultiLineString{{{1, 1}, {1, 1}}, {{0, 0}, {1, 0}}},
+			output: orb.MultiLineString{{{0, 0}, {1, 0}}},
+		},
+		{
+			name:   "ring not closed",
+			input:  orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+			output: orb.Ring{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}},
+		},
+		{
+			name:  "bowtie",
+			input: orb.Ring{{0, 0}, {2, 2}, {2, 0}, {0, 2}, {0, 0}},
+			output: orb.MultiPolygon{
+				{{{0, 0}, {1, 1}, {0, 2}, {0, 0}}},
+				{{{1, 1}, {2, 0}, {2, 2}, {1, 1}}},
+			},
+		},
+		{
+			name:   "spike",
+			input:  orb.Ring{{0, 0}, {4, 0}, {6, 0}, {4, 0}, {4, 4}, {0, 0}},
+			output: orb.Ring{{0, 0}, {4, 0}, {4, 4}, {0, 0}},
+		},
+		{
+			name:   "collapsed ring",
+			input:  orb.Ring{{0, 0}, {4, 0}, {0, 0}},
+			output: nil,
+		},
+		{
+			name: "hole outside shell",
+			input: orb.Polygon{
+				{{0, 0}, {6, 0}, {6, 6}, {0, 6}, {0, 0}},
+				{{4, 4}, {4, 8}, {8, 8}, {8, 4}, {4, 4}},
+			},
+			output: orb.Polygon{
+				{{0, 0}, {6, 0}, {6, 4}, {4, 4}, {4, 6}, {0, 6}, {0, 0}},
+			},
+		},
+		{
+			name: "overlapping polygons",
+			input: orb.MultiPolygon{
+				{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+				{{{1, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 1}}},
+			},
+			output: orb.MultiPolygon{
+				{{{0, 0}, {2, 0}, {2, 1}, {3, 1}, {3, 3}, {1, 3}, {1, 2}, {0, 2}, {0, 0}}},
+			},
+		},
+		{
+			name: "polygons sharing an edge",
+			input: orb.MultiPolygon{
+				{{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}},
+				{{{2, 0}, {4, 0}, {4, 2}, {2, 2}, {2, 0}}},
+			},
+			output: orb.MultiPolygon{
+				{{{0, 0}, {4, 0}, {4, 2}, {0, 2}, {0, 0}}},
+			},
+		},
+		{
+			name:   "bound",
+			input:  orb.Bound{Min: orb.Point{2, 0}, Max: orb.Point{0, 2}},
+			output: orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{2, 2}},
+		},
+		{
+			name: "collection",
+			input: orb.Collection{
+				orb.Point{math.NaN(), 2},
+				orb.LineString{{0, 0}, {1, 1}},
+			},
+			output: orb.Collection{orb.LineString{{0, 0}, {1, 1}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := MakeValid(tc.input)
+			if !equalIgnoreStart(result, tc.output) {
+				t.Errorf("incorrect result: %v", result)
+				t.Log(tc.output)
+			}
+
+			if result == nil {
+				return
+			}
+
+			if valid, reasons := IsValid(result); !valid {
+				t.Errorf("result not valid: %v", reasons)
+			}
+		})
+	}
+}
+
+func TestMakeValid_area(t *testing.T) {
+	// figure eight with the loops in the same direction
+	r := orb.Ring{{0, 0}, {2, 0}, {2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}, {0, 2}, {0, 0}}
+
+	result := MakeValid(r)
+	if v := planar.Area(result); v != 8 {
+		t.Errorf("incorrect area: %v", v)
+	}
+
+	if valid, reasons := IsValid(result); !valid {
+		t.Errorf("result not valid: %v", reasons)
+	}
+
+	// the input should not be modified
+	if len(r) != 9 {
+		t.Errorf("input modified: %v", r)
+	}
+}
+
+func TestMakeValid_large(t *testing.T) {
+	r := circle(20000)
+
+	result, ok := MakeValid(r).(orb.Ring)
+	if !ok || len(result) != len(r) {
+		t.Fatalf("should be the same ring: %v", len(result))
+	}
+
+	if v, e := planar.Area(result), planar.Area(r); math.Abs(v-e) > 1e-9 {
+		t.Errorf("incorrect area: %v != %v", v, e)
+	}
+}
+
+func BenchmarkIsValid(b *testing.B) {
+	r := circle(20000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		IsValid(r)
+	}
+}
+
+func BenchmarkMakeValid(b *testing.B) {
+	r := circle(20000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MakeValid(r)
+	}
+}
+
+// circle returns a valid counter-clockwise ring with n vertices.
+func circle(n int) orb.Ring {
+	r := make(orb.Ring, 0, n+1)
+	for i := 0; i < n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		r = append(r, orb.Point{100 * math.Cos(a), 100 * math.Sin(a)})
+	}
+
+	return append(r, r[0])
+}
+
+// equalIgnoreStart compares the geometries allowing the rings
+// to start at a different point.
+func equalIgnoreStart(a, b orb.Geometry) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	switch a := a.(type) {
+	case orb.Ring:
+		b, ok := b.(orb.Ring)
+		return ok && ringEqualIgnoreStart(a, b)
+	case orb.Polygon:
+		b, ok := b.(orb.Polygon)
+		return ok && equalIgnoreStart(orb.MultiPolygon{a}, orb.MultiPolygon{b})
+	case orb.MultiPolygon:
+		b, ok := b.(orb.MultiPolygon)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+
+		for i := range a {
+			if len(a[i]) != len(b[i]) {
+				return false
+			}
+
+			for j := range a[i] {
+				if !ringEqualIgnoreStart(a[i][j], b[i][j]) {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	return orb.Equal(a, b)
+}
+
+func ringEqualIgnoreStart(a, b orb.Ring) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	if len(a) == 0 {
+		return true
+	}
+
+	for offset := 0; offset < len(a)-1; offset++ {
+		equal := true
+		for i := 0; i < len(a)-1; i++ {
+			if a[(i+offset)%(len(a)-1)] != b[i] {
+				equal = false
+				break
+			}
+		}
+
+		if equal {
+			return true
+		}
+	}
+
+	return false
+}